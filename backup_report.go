@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// backupReport is the JSON summary a backup image may write to its
+// termination message (/dev/termination-log) before exiting
+type backupReport struct {
+	// Destinations reports the upload outcome per storage destination
+	Destinations []destinationReport `json:"destinations,omitempty"`
+
+	// AvailableBackups is the number of backups listed in the manifest
+	// after it was updated
+	AvailableBackups *int32 `json:"availableBackups,omitempty"`
+
+	// Checksum identifies the database content that was backed up
+	Checksum string `json:"checksum,omitempty"`
+
+	// ArtifactSHA256 is the hex SHA256 of the written artifact
+	ArtifactSHA256 string `json:"artifactSHA256,omitempty"`
+
+	// SizeBytes is the size of the written artifact
+	SizeBytes *int64 `json:"sizeBytes,omitempty"`
+
+	// Location is where the artifact was written, e.g. s3://bucket/path/name
+	Location string `json:"location,omitempty"`
+
+	// LogLocation is where the backup log was uploaded, with StoreLogs
+	LogLocation string `json:"logLocation,omitempty"`
+
+	// StagedArtifact is where the artifact was kept on the staging volume
+	// for re-uploads, relative to it
+	StagedArtifact string `json:"stagedArtifact,omitempty"`
+}
+
+// destinationReport is the upload outcome for a single storage destination
+type destinationReport struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// sha256Pattern matches a hex-encoded SHA256
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Helper function to get the termination message of a job's most recently
+// finished container of the given name, or "" if none left one
+func (r *DatabaseBackupReconciler) latestTerminationMessage(ctx context.Context, job *batchv1.Job, container string) (string, error) {
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList,
+		client.InNamespace(job.Namespace),
+		client.MatchingLabels{"job-name": job.Name},
+	); err != nil {
+		return "", err
+	}
+
+	var latest *corev1.ContainerStateTerminated
+	for _, pod := range podList.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			terminated := status.State.Terminated
+			if status.Name != container || terminated == nil || terminated.Message == "" {
+				continue
+			}
+			if latest == nil || latest.FinishedAt.Before(&terminated.FinishedAt) {
+				latest = terminated
+			}
+		}
+	}
+	if latest == nil {
+		return "", nil
+	}
+	return latest.Message, nil
+}
+
+// Helper function to check if a finished backup job's report is needed
+func needsBackupReport(dbBackup *dbbackupv1alpha1.DatabaseBackup) bool {
+	return hasMultipleDestinations(dbBackup) || dbBackup.Spec.Manifest != nil || dbBackup.Spec.SkipIfUnchanged ||
+		dbBackup.Spec.RecordChecksum || dbBackup.Spec.AuditLog || dbBackup.Spec.PublishMetadata ||
+		(dbBackup.Spec.StoreLogs != nil && *dbBackup.Spec.StoreLogs)
+}
+
+// Helper function to read the backup report of a finished job from the
+// termination message of its most recently finished backup container.
+// Returns nil when no pod left a parseable report.
+func (r *DatabaseBackupReconciler) readBackupReport(ctx context.Context, job *batchv1.Job) (*backupReport, error) {
+	message, err := r.latestTerminationMessage(ctx, job, backupReportContainer(job))
+	if err != nil || message == "" {
+		return nil, err
+	}
+
+	var report backupReport
+	if err := json.Unmarshal([]byte(message), &report); err != nil {
+		// Images that don't report leave plain text here; treat as no report
+		return nil, nil
+	}
+	return &report, nil
+}
@@ -0,0 +1,88 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupAuditLogSpec records a single finished backup run. Entries are
+// written once by the controller and not updated afterwards
+type BackupAuditLogSpec struct {
+	// BackupName is the DatabaseBackup the run belongs to
+	BackupName string `json:"backupName"`
+
+	// Run is the Job, VolumeSnapshot or pod that ran the backup
+	Run string `json:"run"`
+
+	// Trigger is Schedule for scheduled runs and Manual for runs requested
+	// through the backup-now annotation
+	// +kubebuilder:validation:Enum=Schedule;Manual
+	Trigger string `json:"trigger"`
+
+	// TriggerValue is the backup-now annotation value of a Manual run
+	TriggerValue string `json:"triggerValue,omitempty"`
+
+	// SpecGeneration is the DatabaseBackup generation the run was built from
+	SpecGeneration int64 `json:"specGeneration,omitempty"`
+
+	// DatabaseType is the type of database that was backed up
+	DatabaseType string `json:"databaseType"`
+
+	// Mode is logical, snapshot or exec
+	Mode string `json:"mode"`
+
+	// BackupType is full or incremental, for logical runs
+	BackupType string `json:"backupType,omitempty"`
+
+	// StartTime is when the run started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the run's outcome was recorded
+	CompletionTime metav1.Time `json:"completionTime"`
+
+	// Outcome is the run's LastBackupStatus, e.g. Succeeded or Failed
+	Outcome string `json:"outcome"`
+
+	// FailureCode is the machine-readable reason of an unsuccessful run
+	FailureCode FailureCode `json:"failureCode,omitempty"`
+
+	// FailureReason explains an unsuccessful run
+	FailureReason string `json:"failureReason,omitempty"`
+
+	// SizeBytes is the size of the artifact, when reported
+	SizeBytes *int64 `json:"sizeBytes,omitempty"`
+
+	// ArtifactSHA256 is the hex SHA256 of the artifact, when reported
+	ArtifactSHA256 string `json:"artifactSHA256,omitempty"`
+
+	// LogLocation is where the run's log was uploaded, when StoreLogs is set
+	LogLocation string `json:"logLocation,omitempty"`
+
+	// Destinations is how each storage destination fared
+	Destinations []DestinationStatus `json:"destinations,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=dbaudit
+// +kubebuilder:printcolumn:name="Backup",type="string",JSONPath=".spec.backupName"
+// +kubebuilder:printcolumn:name="Trigger",type="string",JSONPath=".spec.trigger"
+// +kubebuilder:printcolumn:name="Outcome",type="string",JSONPath=".spec.outcome"
+// +kubebuilder:printcolumn:name="Completed",type="date",JSONPath=".spec.completionTime"
+// BackupAuditLog is an audit record of one DatabaseBackup run
+type BackupAuditLog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec BackupAuditLogSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// BackupAuditLogList contains a list of BackupAuditLog
+type BackupAuditLogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BackupAuditLog `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BackupAuditLog{}, &BackupAuditLogList{})
+}
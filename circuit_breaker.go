@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// apiBreakerThreshold is the number of consecutive transient API errors
+	// after which the circuit breaker opens
+	apiBreakerThreshold = 3
+
+	// apiBreakerBaseBackoff is the backoff applied when the breaker first opens.
+	// It doubles with every further failure up to apiBreakerMaxBackoff
+	apiBreakerBaseBackoff = 5 * time.Second
+	apiBreakerMaxBackoff  = 5 * time.Minute
+)
+
+// apiCircuitBreaker tracks consecutive transient API server errors across
+// reconciles. Once open, reconciles back off exponentially instead of adding
+// load to a struggling control plane. The zero value is ready to use.
+type apiCircuitBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// remaining returns how much longer the breaker stays open
+func (b *apiCircuitBreaker) remaining(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if now.Before(b.openUntil) {
+		return b.openUntil.Sub(now)
+	}
+	return 0
+}
+
+// recordFailure counts a transient error and returns the backoff to apply,
+// or zero while the breaker is still below its threshold
+func (b *apiCircuitBreaker) recordFailure(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.failures < apiBreakerThreshold {
+		return 0
+	}
+
+	backoff := apiBreakerMaxBackoff
+	if exp := b.failures - apiBreakerThreshold; exp < 16 {
+		if d := apiBreakerBaseBackoff << exp; d < apiBreakerMaxBackoff {
+			backoff = d
+		}
+	}
+	b.openUntil = now.Add(backoff)
+	return backoff
+}
+
+// recordSuccess closes the breaker
+func (b *apiCircuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.openUntil = time.Time{}
+}
+
+// isTransientAPIError reports whether err indicates the API server is
+// temporarily unable to serve requests
+func isTransientAPIError(err error) bool {
+	return errors.IsServerTimeout(err) ||
+		errors.IsTimeout(err) ||
+		errors.IsTooManyRequests(err) ||
+		errors.IsServiceUnavailable(err) ||
+		errors.IsInternalError(err) ||
+		errors.IsUnexpectedServerError(err)
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestAPICircuitBreaker(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		failures    int
+		wantBackoff time.Duration
+	}{
+		{failures: 1, wantBackoff: 0},
+		{failures: 2, wantBackoff: 0},
+		{failures: 3, wantBackoff: 5 * time.Second},
+		{failures: 4, wantBackoff: 10 * time.Second},
+		{failures: 5, wantBackoff: 20 * time.Second},
+		{failures: 8, wantBackoff: 160 * time.Second},
+		{failures: 9, wantBackoff: 5 * time.Minute},
+		{failures: 50, wantBackoff: 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d failures", tt.failures), func(t *testing.T) {
+			var b apiCircuitBreaker
+			var backoff time.Duration
+			for i := 0; i < tt.failures; i++ {
+				backoff = b.recordFailure(now)
+			}
+			if backoff != tt.wantBackoff {
+				t.Errorf("backoff = %s, want %s", backoff, tt.wantBackoff)
+			}
+			if got := b.remaining(now); got != tt.wantBackoff {
+				t.Errorf("remaining = %s, want %s", got, tt.wantBackoff)
+			}
+			if got := b.remaining(now.Add(tt.wantBackoff)); got != 0 {
+				t.Errorf("remaining once the backoff has passed = %s, want 0", got)
+			}
+
+			// A success closes the breaker and starts the count over
+			b.recordSuccess()
+			if got := b.remaining(now); got != 0 {
+				t.Errorf("remaining after success = %s, want 0", got)
+			}
+			if got := b.recordFailure(now); got != 0 {
+				t.Errorf("backoff of the first failure after success = %s, want 0", got)
+			}
+		})
+	}
+}
+
+func TestIsTransientAPIError(t *testing.T) {
+	resource := schema.GroupResource{Group: "batch", Resource: "jobs"}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "server timeout", err: apierrors.NewServerTimeout(resource, "list", 1), want: true},
+		{name: "timeout", err: apierrors.NewTimeoutError("request timed out", 1), want: true},
+		{name: "too many requests", err: apierrors.NewTooManyRequests("slow down", 1), want: true},
+		{name: "service unavailable", err: apierrors.NewServiceUnavailable("etcd unavailable"), want: true},
+		{name: "internal error", err: apierrors.NewInternalError(errors.New("boom")), want: true},
+		{name: "not found", err: apierrors.NewNotFound(resource, "backup-1"), want: false},
+		{name: "conflict", err: apierrors.NewConflict(resource, "backup-1", errors.New("modified")), want: false},
+		{name: "forbidden", err: apierrors.NewForbidden(resource, "backup-1", errors.New("denied")), want: false},
+		{name: "invalid", err: apierrors.NewBadRequest("invalid spec"), want: false},
+		{name: "not an API error", err: errors.New("connection refused"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientAPIError(tt.err); got != tt.want {
+				t.Errorf("isTransientAPIError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
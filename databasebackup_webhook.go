@@ -0,0 +1,147 @@
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// Defaults applied at admission. The controller falls back to the same values
+// for objects stored before the webhook was enabled.
+const (
+	// DefaultBackupRetention is how long backups are kept (in hours)
+	DefaultBackupRetention int64 = 168
+
+	// DefaultJobBackoffLimit keeps failing backups from retrying silently for long
+	DefaultJobBackoffLimit int32 = 2
+
+	// DefaultMode takes logical dumps with a backup Job
+	DefaultMode = "logical"
+
+	// DefaultSchedulingMode has the controller launch backup Jobs itself
+	DefaultSchedulingMode = "Controller"
+
+	// DefaultBackupType takes a full backup on every run
+	DefaultBackupType = "full"
+
+	// DefaultTimezone is used for backup windows without an explicit timezone
+	DefaultTimezone = "UTC"
+
+	// DefaultTokenExpirationSeconds is the lifetime of projected storage tokens
+	DefaultTokenExpirationSeconds int64 = 3600
+
+	// DefaultExecTimeout bounds exec-mode backups
+	DefaultExecTimeout = time.Hour
+
+	// DefaultStorageWaitTimeout bounds the wait for storage to become reachable
+	DefaultStorageWaitTimeout = 5 * time.Minute
+
+	// DefaultSSLMode is the DB_SSL_MODE used for database TLS
+	DefaultSSLMode = "verify-full"
+
+	// DefaultRoleLabelKey is the pod label PreferRole matches on
+	DefaultRoleLabelKey = "role"
+
+	// DefaultEncryptionKey is the secret key holding the encryption key
+	DefaultEncryptionKey = "key"
+
+	// DefaultManifestPath is where the manifest lives relative to the destination path
+	DefaultManifestPath = "manifest.json"
+
+	// DefaultReuploadAttempts is how many re-uploads to failed destinations are tried
+	DefaultReuploadAttempts int32 = 3
+
+	// DefaultIntegrityCheckArtifacts is how many recent artifacts an integrity check verifies
+	DefaultIntegrityCheckArtifacts int32 = 3
+
+	// DefaultIncidentProvider is the incident provider when none is set
+	DefaultIncidentProvider = "pagerduty"
+
+	// DefaultIncidentKey is the secret key holding the incident integration key
+	DefaultIncidentKey = "integrationKey"
+
+	// DefaultIncidentAfterFailures is how many consecutive failures open an incident
+	DefaultIncidentAfterFailures int32 = 3
+)
+
+// log is for logging in this package.
+var databasebackuplog = logf.Log.WithName("databasebackup-resource")
+
+// SetupWebhookWithManager registers the DatabaseBackup webhooks with the Manager.
+func (r *DatabaseBackup) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-db-example-io-v1alpha1-databasebackup,mutating=true,failurePolicy=fail,sideEffects=None,groups=db.example.io,resources=databasebackups,verbs=create;update,versions=v1alpha1,name=mdatabasebackup.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &DatabaseBackup{}
+
+// Default fills in unset optional fields so the stored object shows the
+// effective configuration. Explicitly set values are left untouched.
+func (r *DatabaseBackup) Default() {
+	databasebackuplog.Info("default", "name", r.Name)
+
+	if r.Spec.BackupRetention == 0 {
+		r.Spec.BackupRetention = DefaultBackupRetention
+	}
+	if r.Spec.JobBackoffLimit == nil {
+		backoffLimit := DefaultJobBackoffLimit
+		r.Spec.JobBackoffLimit = &backoffLimit
+	}
+	if r.Spec.Mode == "" {
+		r.Spec.Mode = DefaultMode
+	}
+	if r.Spec.SchedulingMode == "" {
+		r.Spec.SchedulingMode = DefaultSchedulingMode
+	}
+	if r.Spec.BackupType == "" {
+		r.Spec.BackupType = DefaultBackupType
+	}
+	if r.Spec.BackupWindow != nil && r.Spec.BackupWindow.Timezone == "" {
+		r.Spec.BackupWindow.Timezone = DefaultTimezone
+	}
+	if identity := r.Spec.StorageDestination.WorkloadIdentity; identity != nil && identity.ExpirationSeconds == nil {
+		expirationSeconds := DefaultTokenExpirationSeconds
+		identity.ExpirationSeconds = &expirationSeconds
+	}
+	if r.Spec.Exec != nil && r.Spec.Exec.Timeout == nil {
+		r.Spec.Exec.Timeout = &metav1.Duration{Duration: DefaultExecTimeout}
+	}
+	if r.Spec.WaitForStorage != nil && *r.Spec.WaitForStorage && r.Spec.StorageWaitTimeout == nil {
+		r.Spec.StorageWaitTimeout = &metav1.Duration{Duration: DefaultStorageWaitTimeout}
+	}
+	if r.Spec.TLSConfig != nil && r.Spec.TLSConfig.SSLMode == "" {
+		r.Spec.TLSConfig.SSLMode = DefaultSSLMode
+	}
+	if r.Spec.PreferRole != nil && r.Spec.PreferRole.LabelKey == "" {
+		r.Spec.PreferRole.LabelKey = DefaultRoleLabelKey
+	}
+	if r.Spec.Encryption != nil && r.Spec.Encryption.Key == "" {
+		r.Spec.Encryption.Key = DefaultEncryptionKey
+	}
+	if r.Spec.Manifest != nil && r.Spec.Manifest.Path == "" {
+		r.Spec.Manifest.Path = DefaultManifestPath
+	}
+	if r.Spec.ReuploadFailedDestinations != nil && r.Spec.ReuploadFailedDestinations.MaxAttempts == 0 {
+		r.Spec.ReuploadFailedDestinations.MaxAttempts = DefaultReuploadAttempts
+	}
+	if r.Spec.IntegrityCheck != nil && r.Spec.IntegrityCheck.MaxArtifacts == 0 {
+		r.Spec.IntegrityCheck.MaxArtifacts = DefaultIntegrityCheckArtifacts
+	}
+	if incident := r.Spec.Incident; incident != nil {
+		if incident.Provider == "" {
+			incident.Provider = DefaultIncidentProvider
+		}
+		if incident.Key == "" {
+			incident.Key = DefaultIncidentKey
+		}
+		if incident.AfterFailures == 0 {
+			incident.AfterFailures = DefaultIncidentAfterFailures
+		}
+	}
+}
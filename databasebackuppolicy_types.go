@@ -0,0 +1,93 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatabaseBackupPolicySpec defines the desired state of DatabaseBackupPolicy
+type DatabaseBackupPolicySpec struct {
+	// NamespaceSelector selects the namespaces a DatabaseBackup is created in.
+	// An empty selector matches every namespace
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+
+	// BackupName is the name of the DatabaseBackup created in each
+	// namespace. Defaults to the policy's name
+	// +optional
+	BackupName string `json:"backupName,omitempty"`
+
+	// Template is the DatabaseBackup created in each selected namespace.
+	// The policy owns the fields the template sets, and clears them on each
+	// DatabaseBackup once the template stops setting them. Fields it never
+	// set can be set on the DatabaseBackups themselves
+	Template DatabaseBackupTemplate `json:"template"`
+}
+
+// DatabaseBackupTemplate describes the DatabaseBackups created by a policy
+type DatabaseBackupTemplate struct {
+	// Labels are set on each DatabaseBackup, e.g. to assign it to the
+	// controller instance whose --watch-selector matches
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are set on each DatabaseBackup
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Spec is the spec of each DatabaseBackup
+	Spec DatabaseBackupSpec `json:"spec"`
+}
+
+// DatabaseBackupPolicyStatus defines the observed state of DatabaseBackupPolicy
+type DatabaseBackupPolicyStatus struct {
+	// ObservedGeneration is the policy generation last applied
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Namespaces are the namespaces the policy has a DatabaseBackup in
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// MatchingNamespaces is the number of namespaces the selector matches
+	MatchingNamespaces int32 `json:"matchingNamespaces,omitempty"`
+
+	// Conflicts are namespaces whose DatabaseBackup of the same name is not
+	// managed by this policy and was left alone
+	Conflicts []string `json:"conflicts,omitempty"`
+
+	// Conditions represent the latest available observations of the policy's state
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+const (
+	// ConditionSelectorValid is false while NamespaceSelector can't be
+	// parsed. No DatabaseBackups are created or pruned until it is fixed
+	ConditionSelectorValid = "SelectorValid"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=dbpolicy
+// +kubebuilder:printcolumn:name="Namespaces",type="integer",JSONPath=".status.matchingNamespaces"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// DatabaseBackupPolicy creates a DatabaseBackup from a template in every
+// namespace matching a selector, and removes it from namespaces that stop
+// matching
+type DatabaseBackupPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatabaseBackupPolicySpec   `json:"spec,omitempty"`
+	Status DatabaseBackupPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// DatabaseBackupPolicyList contains a list of DatabaseBackupPolicy
+type DatabaseBackupPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatabaseBackupPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatabaseBackupPolicy{}, &DatabaseBackupPolicyList{})
+}
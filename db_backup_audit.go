@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=db.example.io,resources=backupauditlogs,verbs=get;list;watch;create
+
+// Helper function to build the audit entry for a run whose outcome has just
+// been recorded in status. manual says whether the run was triggered
+func newAuditEntry(dbBackup *dbbackupv1alpha1.DatabaseBackup, name, run string, manual bool) *dbbackupv1alpha1.BackupAuditLog {
+	mode := dbBackup.Spec.Mode
+	if mode == "" {
+		mode = "logical"
+	}
+
+	entry := &dbbackupv1alpha1.BackupAuditLog{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: dbBackup.Namespace,
+			Labels: map[string]string{
+				"app":           "db-backup-operator",
+				backupNameLabel: dbBackup.Name,
+			},
+		},
+		Spec: dbbackupv1alpha1.BackupAuditLogSpec{
+			BackupName:     dbBackup.Name,
+			Run:            run,
+			Trigger:        "Schedule",
+			SpecGeneration: dbBackup.Generation,
+			DatabaseType:   dbBackup.Spec.DatabaseType,
+			Mode:           mode,
+			StartTime:      dbBackup.Status.LastBackupStartTime,
+			CompletionTime: metav1.Now(),
+			Outcome:        dbBackup.Status.LastBackupStatus,
+			FailureCode:    dbBackup.Status.FailureCode,
+			FailureReason:  dbBackup.Status.FailureReason,
+		},
+	}
+	if manual {
+		entry.Spec.Trigger = "Manual"
+		entry.Spec.TriggerValue = dbBackup.Status.LastManualTrigger
+	}
+	return entry
+}
+
+// Helper function to write an audit entry owned by the DatabaseBackup and
+// refresh the storage usage trend computed from the entries. An entry that
+// already exists was written by an earlier attempt at the same reconcile
+// and is left as is
+func (r *DatabaseBackupReconciler) recordAuditEntry(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, entry *dbbackupv1alpha1.BackupAuditLog) error {
+	if err := ctrl.SetControllerReference(dbBackup, entry, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, entry); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return r.updateStorageUsageTrend(ctx, dbBackup, entry)
+}
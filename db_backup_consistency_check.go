@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// consistencyCheckContainer is the init container that checks the database
+// for corruption before it is dumped
+const consistencyCheckContainer = "consistency-check"
+
+// consistencyCheckCredentials reads the username and password of a database
+// resolved from DatabaseRef, the way the backup image does
+const consistencyCheckCredentials = `if [ -n "$DB_CREDENTIALS_DIR" ]; then DB_USER="$(cat "$DB_CREDENTIALS_DIR/username")"; DB_PASSWORD="$(cat "$DB_CREDENTIALS_DIR/password")"; fi; `
+
+// consistencyCheckCommands are the engine-appropriate consistency checks,
+// connecting the way the backup image does. Engines missing here don't
+// support PreBackupIntegrityCheck
+var consistencyCheckCommands = map[string]string{
+	"postgres": consistencyCheckCredentials + `export PGPASSWORD="${DB_PASSWORD:-$PGPASSWORD}"; exec pg_amcheck --all --heapallindexed ${DB_HOST:+--host "$DB_HOST"} ${DB_PORT:+--port "$DB_PORT"} ${DB_USER:+--username "$DB_USER"}`,
+	"mysql":    consistencyCheckCredentials + `export MYSQL_PWD="${DB_PASSWORD:-$MYSQL_PWD}"; exec mysqlcheck --all-databases --check ${DB_HOST:+--host "$DB_HOST"} ${DB_PORT:+--port "$DB_PORT"} ${DB_USER:+--user "$DB_USER"}`,
+}
+
+// Helper function to append an init container that runs the engine's
+// consistency check, so a corrupt database fails the backup instead of
+// being dumped. It must run after everything else configuring the backup
+// container, whose database connection settings and mounts it shares.
+func addConsistencyCheck(podSpec *corev1.PodSpec, dbBackup *dbbackupv1alpha1.DatabaseBackup) {
+	backup := &podSpec.Containers[0]
+
+	// PATH is the storage path for the backup image, not a search path
+	var env []corev1.EnvVar
+	for _, e := range backup.Env {
+		if e.Name != "PATH" {
+			env = append(env, e)
+		}
+	}
+
+	podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{
+		Name:            consistencyCheckContainer,
+		Image:           backup.Image,
+		Command:         []string{"/bin/sh", "-c", consistencyCheckCommands[dbBackup.Spec.DatabaseType]},
+		SecurityContext: backup.SecurityContext,
+		EnvFrom:         backup.EnvFrom,
+		Env:             env,
+		VolumeMounts:    backup.VolumeMounts,
+	})
+}
+
+// Helper function to add env vars that are only known when the Job is
+// created to the backup container, and to the consistency check so it
+// checks the same database the backup dumps
+func addConnectionEnv(podSpec *corev1.PodSpec, env ...corev1.EnvVar) {
+	podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, env...)
+	for i := range podSpec.InitContainers {
+		if podSpec.InitContainers[i].Name == consistencyCheckContainer {
+			podSpec.InitContainers[i].Env = append(podSpec.InitContainers[i].Env, env...)
+		}
+	}
+}
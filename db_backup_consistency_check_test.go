@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+func TestAddConsistencyCheck(t *testing.T) {
+	tests := []struct {
+		databaseType string
+		wantCheck    string
+	}{
+		{databaseType: "postgres", wantCheck: "exec pg_amcheck --all --heapallindexed"},
+		{databaseType: "mysql", wantCheck: "exec mysqlcheck --all-databases --check"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.databaseType, func(t *testing.T) {
+			check := true
+			dbBackup := &dbbackupv1alpha1.DatabaseBackup{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+				Spec: dbbackupv1alpha1.DatabaseBackupSpec{
+					DatabaseType:            tt.databaseType,
+					StorageDestination:      dbbackupv1alpha1.StorageDestinationSpec{Type: "pvc", PVCName: "backups"},
+					PreBackupIntegrityCheck: &check,
+				},
+			}
+			job, err := buildBackupJob(dbBackup, time.Date(2026, time.March, 1, 2, 0, 0, 0, time.UTC))
+			if err != nil {
+				t.Fatalf("buildBackupJob: %v", err)
+			}
+			addConnectionEnv(&job.Spec.Template.Spec, corev1.EnvVar{Name: "DB_HOST", Value: "10.0.0.5"})
+
+			podSpec := job.Spec.Template.Spec
+			// An init container failing a pod that is never restarted keeps
+			// the dump from running at all
+			if podSpec.RestartPolicy != corev1.RestartPolicyNever {
+				t.Errorf("restartPolicy = %s, want Never", podSpec.RestartPolicy)
+			}
+			if len(podSpec.InitContainers) != 1 || podSpec.InitContainers[0].Name != consistencyCheckContainer {
+				t.Fatalf("init containers = %+v, want only %s", podSpec.InitContainers, consistencyCheckContainer)
+			}
+			initContainer, backup := podSpec.InitContainers[0], podSpec.Containers[0]
+
+			if initContainer.Image != backup.Image {
+				t.Errorf("image = %s, want the backup image %s", initContainer.Image, backup.Image)
+			}
+			if len(initContainer.Command) != 3 || initContainer.Command[0] != "/bin/sh" || initContainer.Command[1] != "-c" {
+				t.Fatalf("command = %q, want a shell script", initContainer.Command)
+			}
+			if script := initContainer.Command[2]; !strings.Contains(script, tt.wantCheck) {
+				t.Errorf("script = %q, want it to run %q", script, tt.wantCheck)
+			}
+
+			// It checks the database the backup dumps, without the storage PATH
+			if env := findEnv(initContainer, "DB_HOST"); env == nil || env.Value != "10.0.0.5" {
+				t.Errorf("DB_HOST = %+v, want 10.0.0.5", env)
+			}
+			if env := findEnv(initContainer, "DB_TYPE"); env == nil || env.Value != tt.databaseType {
+				t.Errorf("DB_TYPE = %+v, want %s", env, tt.databaseType)
+			}
+			if env := findEnv(initContainer, "PATH"); env != nil {
+				t.Errorf("PATH = %+v, want the image's search path", env)
+			}
+			if len(initContainer.VolumeMounts) != len(backup.VolumeMounts) {
+				t.Errorf("mounts = %+v, want those of the backup container", initContainer.VolumeMounts)
+			}
+		})
+	}
+}
+
+func TestReconcileConsistencyCheckFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		checkExit  int32
+		wantCode   dbbackupv1alpha1.FailureCode
+		wantReason string
+	}{
+		{
+			name:       "check failed",
+			checkExit:  1,
+			wantCode:   dbbackupv1alpha1.FailureIntegrityCheckFailed,
+			wantReason: "Database consistency check failed",
+		},
+		{
+			name:       "check passed, backup failed",
+			checkExit:  0,
+			wantCode:   dbbackupv1alpha1.FailureJobFailed,
+			wantReason: "Backup job failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := true
+			next := metav1.NewTime(time.Now().Add(time.Hour))
+			dbBackup := &dbbackupv1alpha1.DatabaseBackup{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", UID: "backup-uid"},
+				Spec: dbbackupv1alpha1.DatabaseBackupSpec{
+					Schedule:                "0 * * * *",
+					DatabaseType:            "postgres",
+					StorageDestination:      dbbackupv1alpha1.StorageDestinationSpec{Type: "s3", Bucket: "backups"},
+					PreBackupIntegrityCheck: &check,
+				},
+				Status: dbbackupv1alpha1.DatabaseBackupStatus{
+					ActiveBackupJob:     "db-1",
+					ActiveBackupJobUID:  "job-uid",
+					LastBackupStatus:    "Running",
+					NextScheduledBackup: &next,
+				},
+			}
+			job := ownedJob("db-1", "job-uid", dbBackup)
+			job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+			pod := backupPod("db-1-a", "db-1", corev1.PodFailed, terminatedStatus(consistencyCheckContainer, tt.checkExit))
+			r := newTestReconciler(t, dbBackup, job, pod)
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "default"}}
+
+			if _, err := r.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("reconcile: %v", err)
+			}
+
+			var got dbbackupv1alpha1.DatabaseBackup
+			if err := r.Get(context.Background(), req.NamespacedName, &got); err != nil {
+				t.Fatalf("getting DatabaseBackup: %v", err)
+			}
+			if got.Status.LastBackupStatus != "Failed" || got.Status.FailureCode != tt.wantCode {
+				t.Errorf("status = %s/%s, want Failed/%s", got.Status.LastBackupStatus, got.Status.FailureCode, tt.wantCode)
+			}
+			if !strings.HasPrefix(got.Status.FailureReason, tt.wantReason) {
+				t.Errorf("FailureReason = %q, want it to start with %q", got.Status.FailureReason, tt.wantReason)
+			}
+			if got.Status.ActiveBackupJob != "" || got.Status.ConsecutiveFailures != 1 {
+				t.Errorf("active job = %q, consecutive failures = %d, want cleared and 1", got.Status.ActiveBackupJob, got.Status.ConsecutiveFailures)
+			}
+		})
+	}
+}
@@ -2,27 +2,186 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"math"
+	"net"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
 	"time"
 
 	"github.com/robfig/cron"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
 )
 
+// backupNameLabel is set on every backup Job to record the owning DatabaseBackup
+const backupNameLabel = "databasebackup.db.example.io/name"
+
+// resumeAnnotation clears an auto-suspended DatabaseBackup when set
+const resumeAnnotation = "db.example.io/resume"
+
+// cancelBackupAnnotation aborts the running backup (Job, exec or volume
+// snapshot) when set to "true". It is removed once acted on
+const cancelBackupAnnotation = "db.example.io/cancel-backup"
+
+// jobKindLabel marks owned Jobs that are not backup jobs (e.g. restore tests)
+const jobKindLabel = "db.example.io/job-kind"
+
+// backupTypeAnnotation records whether a backup Job took a full or incremental backup
+const backupTypeAnnotation = "db.example.io/backup-type"
+
+// specGenerationAnnotation records the DatabaseBackup generation a backup Job was built from
+const specGenerationAnnotation = "db.example.io/spec-generation"
+
+// watchedSecretLabel marks the Secrets the controller caches and watches. It
+// is set on every Secret the controller writes; source Secrets given the
+// label have their edits picked up right away rather than on the next
+// reconcile. Other Secrets are read from the API server
+const watchedSecretLabel = "db.example.io/watch"
+
+// SecretCacheSelector selects the Secrets the manager's cache should hold,
+// so the controller doesn't cache every Secret in the cluster
+func SecretCacheSelector() labels.Selector {
+	return labels.SelectorFromSet(labels.Set{watchedSecretLabel: "true"})
+}
+
+const (
+	// waitForDatabaseRequeue is how long to wait before re-checking a target
+	// database that is not ready yet
+	waitForDatabaseRequeue = 30 * time.Second
+
+	// waitForDependenciesRequeue is a fallback re-check interval while waiting
+	// on dependencies; dependency status changes also trigger a reconcile
+	waitForDependenciesRequeue = time.Minute
+
+	// waitForCancelledJobRequeue is how often a cancelled backup job is
+	// re-checked while its pods terminate
+	waitForCancelledJobRequeue = 2 * time.Second
+
+	// waitForSlotRequeue is how long to wait before retrying a backup held
+	// back by MaxConcurrentBackups
+	waitForSlotRequeue = 30 * time.Second
+
+	// forbiddenJobRequeue is how long to wait before retrying a backup Job
+	// the API server refused to create, e.g. over a ResourceQuota
+	forbiddenJobRequeue = 5 * time.Minute
+
+	// retentionRiskFraction is how far through BackupRetention the last
+	// successful backup may age before RetentionRisk is raised
+	retentionRiskFraction = 0.9
+
+	// databaseDialTimeout bounds the TCP reachability probe
+	databaseDialTimeout = 2 * time.Second
+)
+
+// DefaultRunningJobPollInterval is how often a running backup Job is
+// re-checked when RunningJobPollInterval is unset
+const DefaultRunningJobPollInterval = 15 * time.Second
+
+// DefaultMaxConcurrentReconciles is the number of parallel reconciles when
+// MaxConcurrentReconciles is unset
+const DefaultMaxConcurrentReconciles = 2
+
 // DatabaseBackupReconciler reconciles a DatabaseBackup object
 type DatabaseBackupReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// MaxConcurrentBackups caps the number of backup Jobs running at once
+	// across all DatabaseBackups. Zero means no limit. It is enforced by
+	// each replica on its own, see slotMu
+	MaxConcurrentBackups int
+
+	// RunningJobPollInterval bounds the requeue while a backup Job is
+	// running, as a fallback for missed Job events
+	RunningJobPollInterval time.Duration
+
+	// ImagePullSecret is a pull secret, typically in the operator's namespace,
+	// copied next to each DatabaseBackup and added to its backup pods. Unset
+	// when Name is empty
+	ImagePullSecret types.NamespacedName
+
+	// LogReader captures the logs of failed backup pods. Log capture is
+	// disabled when nil
+	LogReader PodLogReader
+
+	// FailureLogLines is how many log lines of a failed backup pod are kept
+	// in status. Zero uses DefaultFailureLogLines; negative disables capture
+	FailureLogLines int64
+
+	// Executor runs exec-mode backups. Exec mode is unavailable when nil
+	Executor PodExecutor
+
+	// Incidents opens and resolves incidents for DatabaseBackups with
+	// Incident set. Incidents are disabled when nil
+	Incidents IncidentNotifier
+
+	// DefaultStorageSecret holds the storage credentials of DatabaseBackups
+	// that name no storage secret or workload identity. An empty Namespace
+	// means the DatabaseBackup's own namespace. Unset when Name is empty
+	DefaultStorageSecret types.NamespacedName
+
+	// ConfigMap is the operator-wide ConfigMap whose globalPause key pauses
+	// every DatabaseBackup. Unset when Name is empty
+	ConfigMap types.NamespacedName
+
+	// Selector limits this controller to DatabaseBackups with matching
+	// labels, so several instances can each own a shard of them. All
+	// DatabaseBackups are reconciled when nil
+	Selector labels.Selector
+
+	// MaxConcurrentReconciles is the number of DatabaseBackups reconciled in
+	// parallel. Zero uses DefaultMaxConcurrentReconciles
+	MaxConcurrentReconciles int
+
+	// APIReader reads straight from the API server where a stale cache would
+	// let too many backups start, like a Job created moments ago by another
+	// reconcile not being counted yet. The cached client is used when nil
+	APIReader client.Reader
+
+	apiBreaker apiCircuitBreaker
+
+	// slotMu guards reservedSlots, the slots taken by reconciles that are
+	// still creating their Job, so parallel reconciles can't both take the
+	// last slot. Both are process-local: with sharding each replica enforces
+	// MaxConcurrentBackups on its own. Jobs of every shard are counted, but
+	// replicas can each take the last slot at the same moment
+	slotMu        sync.Mutex
+	reservedSlots int
+
+	execs      execTracker
+	execEvents chan event.GenericEvent
 }
 
 //+kubebuilder:rbac:groups=db.example.io,resources=databasebackups,verbs=get;list;watch;create;update;patch;delete
@@ -30,13 +189,42 @@ type DatabaseBackupReconciler struct {
 //+kubebuilder:rbac:groups=db.example.io,resources=databasebackups/finalizers,verbs=update
 //+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+func (r *DatabaseBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := tracer.Start(ctx, "Reconcile", trace.WithAttributes(
+		attribute.String("k8s.namespace.name", req.Namespace),
+		attribute.String("k8s.object.name", req.Name),
+	))
+	defer func() { endSpan(span, err) }()
+
+	// Don't touch the API server while the circuit breaker is open
+	if wait := r.apiBreaker.remaining(time.Now()); wait > 0 {
+		span.SetAttributes(attribute.Bool("circuit_breaker.open", true))
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	result, err = r.reconcileBackup(ctx, req)
+	if err != nil && isTransientAPIError(err) {
+		if backoff := r.apiBreaker.recordFailure(time.Now()); backoff > 0 {
+			log.FromContext(ctx).Info("API server unavailable, backing off", "backoff", backoff, "error", err.Error())
+			r.markAPIUnavailable(ctx, req, err)
+			return ctrl.Result{RequeueAfter: backoff}, nil
+		}
+		return result, err
+	}
 
-func (r *DatabaseBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.apiBreaker.recordSuccess()
+	return result, err
+}
+
+func (r *DatabaseBackupReconciler) reconcileBackup(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := log.FromContext(ctx).WithValues("databasebackup", req.NamespacedName)
 
 	// Fetch the DatabaseBackup instance
-	var dbBackup dbbackupv1alpha1.DatabaseBackup
-	if err := r.Get(ctx, req.NamespacedName, &dbBackup); err != nil {
+	var stored dbbackupv1alpha1.DatabaseBackup
+	if err := r.Get(ctx, req.NamespacedName, &stored); err != nil {
 		if errors.IsNotFound(err) {
 			// Object not found, could have been deleted
 			return ctrl.Result{}, nil
@@ -45,14 +233,262 @@ func (r *DatabaseBackupReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		log.Error(err, "Failed to get DatabaseBackup")
 		return ctrl.Result{}, err
 	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("db.type", stored.Spec.DatabaseType))
+
+	// Every log line of this reconcile, helpers included, carries the same
+	// identifying fields
+	log = log.WithValues("database_type", stored.Spec.DatabaseType)
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	// Leave DatabaseBackups owned by another controller instance alone, even
+	// when an owned object or a dependency enqueued them
+	if !r.ownsBackup(&stored) {
+		return ctrl.Result{}, nil
+	}
+
+	// Release running Jobs before a DatabaseBackup that orphans them goes away
+	if !stored.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(&stored, orphanJobsFinalizer) {
+			if err := r.finalizeOrphanJobs(ctx, &stored); err != nil {
+				log.Error(err, "Failed to orphan running backup jobs")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+	if updated, err := r.syncOrphanJobsFinalizer(ctx, &stored); err != nil {
+		log.Error(err, "Failed to update orphan-jobs finalizer")
+		return ctrl.Result{}, err
+	} else if updated {
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// The desired status is built on a copy and written in a single update,
+	// which is skipped when the copy ends up matching the stored status
+	dbBackup := stored.DeepCopy()
+	result, err := r.syncBackup(ctx, req, dbBackup)
+	dbBackup.Status.Summary = describeStatus(dbBackup)
+	if equality.Semantic.DeepEqual(stored.Status, dbBackup.Status) {
+		statusUpdatesSkippedTotal.Inc()
+		return result, err
+	}
+	if updateErr := r.Status().Update(ctx, dbBackup); updateErr != nil {
+		log.Error(updateErr, "Failed to update status")
+		if err == nil {
+			err = updateErr
+		}
+		return ctrl.Result{}, err
+	}
+
+	// A finished exec backup's result is kept until its outcome is stored,
+	// so a failed write records it again on the retry
+	if stored.Status.ActiveExec != "" && dbBackup.Status.ActiveExec == "" {
+		r.execs.drop(client.ObjectKeyFromObject(dbBackup))
+	}
+	return result, err
+}
+
+// Helper function to reconcile a DatabaseBackup, building its desired status
+// in dbBackup.Status for reconcileBackup to write. Writes to the object
+// itself keep the status built so far
+func (r *DatabaseBackupReconciler) syncBackup(ctx context.Context, req ctrl.Request, dbBackup *dbbackupv1alpha1.DatabaseBackup) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	// The API server is reachable again
+	if meta.IsStatusConditionTrue(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionAPIUnavailable) {
+		meta.SetStatusCondition(&dbBackup.Status.Conditions, metav1.Condition{
+			Type:               dbbackupv1alpha1.ConditionAPIUnavailable,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: dbBackup.Generation,
+			Reason:             "Recovered",
+			Message:            "API server requests are succeeding again",
+		})
+	}
 
 	// Initialize status if it doesn't exist
 	if dbBackup.Status.LastBackupStatus == "" {
 		dbBackup.Status.LastBackupStatus = "Pending"
-		if err := r.Status().Update(ctx, &dbBackup); err != nil {
-			log.Error(err, "Failed to update status")
+	}
+
+	// Check the configuration on request, ahead of any backup. This runs
+	// before validation so a broken spec still gets its results
+	if dbBackup.Annotations[preflightAnnotation] == "true" {
+		checks, err := r.runPreflight(ctx, dbBackup)
+		if err != nil {
+			log.Error(err, "Failed to run preflight checks")
+			return ctrl.Result{}, err
+		}
+		if err := r.removeAnnotation(ctx, dbBackup, preflightAnnotation); err != nil {
+			log.Error(err, "Failed to remove preflight annotation")
+			return ctrl.Result{}, err
+		}
+		recordPreflight(dbBackup, checks)
+		log.Info("Preflight checks finished", "passed", dbBackup.Status.Preflight.Passed)
+	}
+
+	// Reject specs that would produce an invalid backup job
+	if err := validateSpec(&dbBackup.Spec); err != nil {
+		log.Error(err, "Invalid DatabaseBackup spec")
+		dbBackup.Status.LastBackupStatus = "Error"
+		dbBackup.Status.FailureReason = fmt.Sprintf("Invalid spec: %v", err)
+		dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureInvalidSpec
+		return ctrl.Result{}, nil
+	}
+
+	// The spec is acceptable, so this generation is what the controller acts on
+	if dbBackup.Status.ObservedGeneration != dbBackup.Generation {
+		dbBackup.Status.ObservedGeneration = dbBackup.Generation
+	}
+
+	// Resume an auto-suspended backup once the user has edited the spec or
+	// asked for it explicitly
+	if suspended := meta.FindStatusCondition(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionAutoSuspended); suspended != nil && suspended.Status == metav1.ConditionTrue {
+		_, resume := dbBackup.Annotations[resumeAnnotation]
+		if resume || suspended.ObservedGeneration != dbBackup.Generation {
+			if resume {
+				if err := r.removeAnnotation(ctx, dbBackup, resumeAnnotation); err != nil {
+					log.Error(err, "Failed to remove resume annotation")
+					return ctrl.Result{}, err
+				}
+			}
+			log.Info("Resuming auto-suspended backups")
+			dbBackup.Status.ConsecutiveFailures = 0
+			meta.SetStatusCondition(&dbBackup.Status.Conditions, metav1.Condition{
+				Type:               dbbackupv1alpha1.ConditionAutoSuspended,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: dbBackup.Generation,
+				Reason:             "Resumed",
+				Message:            "Backups resumed",
+			})
+		}
+	}
+
+	// Reflect an operator-wide pause on every DatabaseBackup
+	paused, err := r.isGloballyPaused(ctx)
+	if err != nil {
+		log.Error(err, "Failed to check for a global pause")
+		return ctrl.Result{}, err
+	}
+	if setGloballyPaused(dbBackup, paused) {
+		log.Info("Global pause changed", "paused", paused)
+	}
+
+	// Keep the local copy of a cross-namespace storage secret in sync
+	if err := r.syncStorageSecret(ctx, r.withStorageDefaults(dbBackup)); err != nil {
+		log.Error(err, "Failed to sync storage secret")
+		dbBackup.Status.LastBackupStatus = "Error"
+		dbBackup.Status.FailureReason = fmt.Sprintf("Failed to sync storage secret: %v", err)
+		dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureStorageUnavailable
+		return ctrl.Result{}, err
+	}
+
+	// Likewise for the controller's image pull secret
+	if err := r.syncImagePullSecret(ctx, dbBackup); err != nil {
+		log.Error(err, "Failed to sync image pull secret")
+		dbBackup.Status.LastBackupStatus = "Error"
+		dbBackup.Status.FailureReason = fmt.Sprintf("Failed to sync image pull secret: %v", err)
+		dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureJobCreateFailed
+		return ctrl.Result{}, err
+	}
+
+	// A backup can't connect without the full client certificate
+	if dbBackup.Spec.TLSConfig != nil {
+		if err := r.validateTLSSecret(ctx, dbBackup); err != nil {
+			log.Error(err, "Invalid database TLS secret")
+			dbBackup.Status.LastBackupStatus = "Error"
+			dbBackup.Status.FailureReason = fmt.Sprintf("Invalid database TLS config: %v", err)
+			dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureInvalidSpec
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// Uploads would fail TLS verification without the storage CA
+	if dbBackup.Spec.StorageCABundleSecret != "" {
+		if err := r.validateStorageCABundle(ctx, dbBackup); err != nil {
+			log.Error(err, "Invalid storage CA bundle secret")
+			dbBackup.Status.LastBackupStatus = "Error"
+			dbBackup.Status.FailureReason = fmt.Sprintf("Invalid storage CA bundle: %v", err)
+			dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureInvalidSpec
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// Look up connection details from the referenced database resource
+	if dbBackup.Spec.DatabaseRef != nil {
+		resolved, err := r.resolveDatabaseRef(ctx, dbBackup)
+		if err != nil {
+			log.Error(err, "Failed to resolve database reference")
+			dbBackup.Status.LastBackupStatus = "Error"
+			dbBackup.Status.FailureReason = fmt.Sprintf("Failed to resolve databaseRef: %v", err)
+			dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureDatabaseRefUnresolved
+			return ctrl.Result{RequeueAfter: waitForDatabaseRequeue}, nil
+		}
+		if !equality.Semantic.DeepEqual(dbBackup.Status.ResolvedDatabase, resolved) {
+			dbBackup.Status.ResolvedDatabase = resolved
+		}
+	} else if dbBackup.Status.ResolvedDatabase != nil {
+		dbBackup.Status.ResolvedDatabase = nil
+	}
+
+	// Hand scheduling over to a native CronJob when asked to
+	if isNativeCronJobMode(dbBackup) {
+		return r.reconcileCronJob(ctx, dbBackup)
+	}
+	// Clean up after a switch back from NativeCronJob scheduling
+	if err := r.deleteBackupCronJob(ctx, dbBackup); err != nil {
+		log.Error(err, "Failed to delete backup CronJob")
+		return ctrl.Result{}, err
+	}
+
+	// Queue a backup requested through the backup-now annotation
+	if acceptManualTrigger(dbBackup) {
+		log.Info("Manual backup requested", "trigger", dbBackup.Status.LastManualTrigger)
+	}
+
+	// A dependency cycle would leave every backup in it waiting forever
+	if len(dbBackup.Spec.DependsOn) > 0 {
+		cycle, err := r.findDependencyCycle(ctx, dbBackup)
+		if err != nil {
+			log.Error(err, "Failed to check backup dependencies")
+			return ctrl.Result{}, err
+		}
+		if cycle != nil {
+			log.Info("Backup dependency cycle detected", "cycle", cycle)
+			dbBackup.Status.LastBackupStatus = "Error"
+			dbBackup.Status.FailureReason = fmt.Sprintf("Dependency cycle detected: %s", strings.Join(cycle, " -> "))
+			dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureDependencyCycle
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// Reconcile the active job from the Jobs we actually own, so a Job created
+	// before a crash (but never recorded in status) is adopted rather than duplicated
+	if err := r.syncActiveJob(ctx, dbBackup); err != nil {
+		log.Error(err, "Failed to reconcile active backup job")
+		return ctrl.Result{}, err
+	}
+
+	// Abort the running backup when asked to. The annotation is removed
+	// first so it fires once, whether or not a backup is running
+	if dbBackup.Annotations[cancelBackupAnnotation] == "true" {
+		if err := r.removeAnnotation(ctx, dbBackup, cancelBackupAnnotation); err != nil {
+			log.Error(err, "Failed to remove cancel-backup annotation")
 			return ctrl.Result{}, err
 		}
+		switch {
+		case dbBackup.Status.ActiveBackupJob != "":
+			if err := r.cancelBackupJob(ctx, dbBackup); err != nil {
+				log.Error(err, "Failed to cancel backup job")
+				return ctrl.Result{}, err
+			}
+		case dbBackup.Status.ActiveExec != "":
+			r.cancelExecBackup(ctx, dbBackup)
+		case dbBackup.Status.ActiveSnapshot != "":
+			if err := r.cancelSnapshot(ctx, dbBackup); err != nil {
+				log.Error(err, "Failed to cancel volume snapshot")
+				return ctrl.Result{}, err
+			}
+		}
 	}
 
 	// Check if there's an active backup job
@@ -69,158 +505,1801 @@ func (r *DatabaseBackupReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			return ctrl.Result{}, err
 		}
 
+		// Restart a run built from an outdated spec when asked to
+		if err == nil && !isJobComplete(&job) && dbBackup.Spec.CancelOnSpecChange && isJobOutdated(&job, dbBackup) {
+			log.Info("Spec changed during backup, cancelling job", "job_name", job.Name)
+			// Foreground deletion keeps the Job around until its pods have
+			// terminated, so the replacement can wait for their cleanup
+			if err := r.Delete(ctx, &job, client.PropagationPolicy(metav1.DeletePropagationForeground)); client.IgnoreNotFound(err) != nil {
+				log.Error(err, "Failed to cancel outdated backup job")
+				return ctrl.Result{}, err
+			}
+			if err := r.cancelTargetJobs(ctx, dbBackup); err != nil {
+				log.Error(err, "Failed to cancel consistency group target jobs")
+				return ctrl.Result{}, err
+			}
+			r.Recorder.Eventf(dbBackup, corev1.EventTypeNormal, "BackupCancelled",
+				"Cancelled backup job %s after a spec change, restarting with the new spec", job.Name)
+
+			// A cancelled triggered backup is still owed to whoever triggered it
+			if dbBackup.Status.ManualBackupRun == job.Name {
+				dbBackup.Status.ManualBackupRun = ""
+				dbBackup.Status.ManualBackupPending = true
+			}
+			now := metav1.Now()
+			dbBackup.Status.ActiveBackupJob = ""
+			dbBackup.Status.ActiveBackupJobUID = ""
+			dbBackup.Status.CancellingJob = job.Name
+			recordBackupProgress(dbBackup, nil)
+			dbBackup.Status.LastBackupStatus = "Cancelled"
+			dbBackup.Status.NextScheduledBackup = &now
+			return ctrl.Result{RequeueAfter: time.Second}, nil
+		}
+
+		// A pod stuck pulling its image would keep the backup running forever
+		if err == nil && !isJobComplete(&job) {
+			reason, pullErr := r.findImagePullFailure(ctx, &job, time.Now())
+			if pullErr != nil {
+				log.Error(pullErr, "Failed to check backup pods for image pull failures")
+				return ctrl.Result{}, pullErr
+			}
+			if reason != "" {
+				if err := r.failImagePull(ctx, dbBackup, &job, reason); err != nil {
+					log.Error(err, "Failed to record image pull failure")
+					return ctrl.Result{}, err
+				}
+				return ctrl.Result{RequeueAfter: waitForCancelledJobRequeue}, nil
+			}
+		}
+
+		// Record when the backup pod actually started running
+		if err == nil && dbBackup.Status.LastBackupStartTime == nil {
+			if startTime := jobStartTime(&job); startTime != nil {
+				dbBackup.Status.LastBackupStartTime = startTime
+			}
+		}
+
+		// Surface progress mid-run for images that report it
+		if err == nil && !isJobComplete(&job) && dbBackup.Spec.ReportProgress && r.Executor != nil {
+			progress, progressErr := r.readBackupProgress(ctx, &job)
+			if progressErr != nil {
+				log.Error(progressErr, "Failed to read backup progress")
+			} else {
+				recordBackupProgress(dbBackup, progress)
+			}
+		}
+
+		// A consistency group finishes with its last target
+		targetsRunning := false
+		var failedTargets []string
+		if (errors.IsNotFound(err) || isJobComplete(&job)) && len(dbBackup.Status.Targets) > 0 {
+			var syncErr error
+			if targetsRunning, failedTargets, syncErr = r.syncTargets(ctx, dbBackup); syncErr != nil {
+				log.Error(syncErr, "Failed to check consistency group targets")
+				return ctrl.Result{}, syncErr
+			}
+			if err == nil && targetsRunning {
+				log.V(1).Info("Backup job finished, waiting for consistency group targets", "job_name", job.Name)
+			}
+		}
+
 		// If job is completed or not found, clear the active job field
-		if errors.IsNotFound(err) || isJobComplete(&job) {
-			// If job completed successfully, update last successful backup time
-			if err == nil && isJobSuccessful(&job) {
+		if errors.IsNotFound(err) || (isJobComplete(&job) && !targetsRunning) {
+			// Record how each destination fared, since one failed upload must
+			// not be hidden behind the others succeeding
+			var report *backupReport
+			if err == nil && needsBackupReport(dbBackup) {
+				var reportErr error
+				if report, reportErr = r.readBackupReport(ctx, &job); reportErr != nil {
+					log.Error(reportErr, "Failed to read backup report")
+					return ctrl.Result{}, reportErr
+				}
+			}
+			var failedDestinations []string
+			if err == nil && hasMultipleDestinations(dbBackup) {
+				failedDestinations = recordDestinationStatuses(dbBackup, report, isJobSuccessful(&job))
+			}
+
+			// Some tools exit 0 on partial failures, so a success marker in
+			// the logs may be required as well
+			var markerErr error
+			if err == nil && isJobSuccessful(&job) && dbBackup.Spec.SuccessLogPattern != "" {
+				markerErr = r.checkSuccessMarker(ctx, dbBackup, &job)
+			}
+
+			// If job completed successfully, update last successful backup time,
+			// unless some destination never received the backup
+			if markerErr != nil {
+				dbBackup.Status.LastBackupStatus = "Failed"
+				dbBackup.Status.FailureReason = markerErr.Error()
+				dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureJobFailed
+				recordBackupFailure(dbBackup)
+			} else if err == nil && isJobSuccessful(&job) && len(failedTargets) > 0 {
+				dbBackup.Status.LastBackupStatus = "Failed"
+				dbBackup.Status.FailureReason = describeFailedTargets(failedTargets)
+				dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureJobFailed
+				recordBackupFailure(dbBackup)
+			} else if err == nil && isJobSuccessful(&job) && len(failedDestinations) > 0 {
+				dbBackup.Status.LastBackupStatus = "PartiallyFailed"
+				dbBackup.Status.FailureReason = describeFailedDestinations(failedDestinations)
+				dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureUploadFailed
+				recordBackupFailure(dbBackup)
+				if queueReupload(dbBackup, report, failedDestinations) {
+					r.Recorder.Eventf(dbBackup, corev1.EventTypeWarning, "ReuploadQueued",
+						"Re-uploading %s to %v instead of running the backup again", report.StagedArtifact, failedDestinations)
+				}
+			} else if err == nil && isJobSuccessful(&job) {
 				now := metav1.Now()
 				dbBackup.Status.LastSuccessfulBackup = &now
 				dbBackup.Status.LastBackupStatus = "Succeeded"
 				dbBackup.Status.FailureReason = ""
+				dbBackup.Status.FailureCode = ""
+				dbBackup.Status.LastFailureLog = ""
+				dbBackup.Status.ConsecutiveFailures = 0
+				checkBackupSLO(dbBackup, &job)
+				if report != nil && report.AvailableBackups != nil {
+					dbBackup.Status.AvailableBackups = report.AvailableBackups
+				}
+
+				// Record the artifact's fingerprint. Skipped runs keep the previous one
+				if dbBackup.Spec.RecordChecksum && report != nil && report.ArtifactSHA256 != "" {
+					if sha256Pattern.MatchString(report.ArtifactSHA256) {
+						dbBackup.Status.LastArtifactSHA256 = report.ArtifactSHA256
+					} else {
+						log.Info("Ignoring malformed artifact checksum", "checksum", report.ArtifactSHA256)
+					}
+				}
+
+				// The image didn't write an artifact if the content is unchanged
+				if dbBackup.Spec.SkipIfUnchanged && report != nil && report.Checksum != "" {
+					if report.Checksum == dbBackup.Status.LastBackupChecksum {
+						dbBackup.Status.LastBackupStatus = "SkippedUnchanged"
+					}
+					dbBackup.Status.LastBackupChecksum = report.Checksum
+				}
+
+				// The key id and location belong to the artifact restores
+				// use, which a skipped run didn't replace
+				if dbBackup.Status.LastBackupStatus != "SkippedUnchanged" {
+					if keyID, ok := job.Annotations[encryptionKeyIDAnnotation]; ok {
+						dbBackup.Status.LastBackupKeyID = keyID
+					}
+					if report != nil && report.Location != "" {
+						dbBackup.Status.LastBackupLocation = report.Location
+					}
+				}
+
+				// A successful full backup becomes the base for later incrementals
+				if dbBackup.Spec.BackupType == "incremental" && job.Annotations[backupTypeAnnotation] == "full" &&
+					dbBackup.Status.LastBackupStatus != "SkippedUnchanged" {
+					dbBackup.Status.BaseBackupRef = job.Name
+				}
+
+				// Skipped runs wrote no new artifact to point at. A failure
+				// doesn't hold up recording the backup, syncBackupMetadata
+				// retries it
+				if dbBackup.Spec.PublishMetadata && dbBackup.Status.LastBackupStatus != "SkippedUnchanged" {
+					r.recordMetadataPublished(ctx, dbBackup, r.publishBackupMetadata(ctx, dbBackup, report, now.Time))
+				}
 			} else if err == nil && isJobFailed(&job) {
 				dbBackup.Status.LastBackupStatus = "Failed"
 				dbBackup.Status.FailureReason = "Backup job failed, check job logs for details"
+				dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureJobFailed
+				// The storage wait runs first, so the consistency check
+				// never ran if it failed
+				storageFailure := false
+				if dbBackup.Spec.WaitForStorage != nil && *dbBackup.Spec.WaitForStorage {
+					failed, err := r.isInitContainerFailure(ctx, &job, storageWaitContainer)
+					if err != nil {
+						log.Error(err, "Failed to check for storage wait failure")
+					} else if failed {
+						storageFailure = true
+						dbBackup.Status.FailureReason = "Storage did not become available before the storage wait timed out"
+						dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureStorageUnavailable
+					}
+				}
+				if !storageFailure && dbBackup.Spec.PreBackupIntegrityCheck != nil && *dbBackup.Spec.PreBackupIntegrityCheck {
+					corrupt, err := r.isInitContainerFailure(ctx, &job, consistencyCheckContainer)
+					if err != nil {
+						log.Error(err, "Failed to check for integrity check failure")
+					} else if corrupt {
+						dbBackup.Status.FailureReason = "Database consistency check failed before the backup, check the consistency-check container logs"
+						dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureIntegrityCheckFailed
+					}
+				}
+				if err := r.captureFailureLog(ctx, dbBackup, &job); err != nil {
+					log.Error(err, "Failed to capture logs of failed backup pod")
+				}
+				recordBackupFailure(dbBackup)
+			}
+
+			// The log is uploaded for failed backups too
+			if dbBackup.Spec.StoreLogs != nil && *dbBackup.Spec.StoreLogs {
+				dbBackup.Status.LastBackupLogLocation = ""
+				if report != nil {
+					dbBackup.Status.LastBackupLogLocation = report.LogLocation
+				}
+			}
+
+			if dbBackup.Spec.AuditLog {
+				entry := newAuditEntry(dbBackup, dbBackup.Status.ActiveBackupJob, dbBackup.Status.ActiveBackupJob,
+					dbBackup.Status.ManualBackupRun == dbBackup.Status.ActiveBackupJob)
+				entry.Spec.BackupType = job.Annotations[backupTypeAnnotation]
+				entry.Spec.SpecGeneration = jobSpecGeneration(&job, dbBackup)
+				entry.Spec.Destinations = dbBackup.Status.Destinations
+				if report != nil {
+					entry.Spec.SizeBytes = report.SizeBytes
+					entry.Spec.ArtifactSHA256 = report.ArtifactSHA256
+					entry.Spec.LogLocation = report.LogLocation
+				}
+				if err := r.recordAuditEntry(ctx, dbBackup, entry); err != nil {
+					log.Error(err, "Failed to write audit log entry")
+					return ctrl.Result{}, err
+				}
 			}
 
+			log.Info("Backup job finished", "job_name", dbBackup.Status.ActiveBackupJob, "result", dbBackup.Status.LastBackupStatus)
+
 			// Clear active job field
+			finishManualBackup(dbBackup, dbBackup.Status.ActiveBackupJob)
 			dbBackup.Status.ActiveBackupJob = ""
-			if err := r.Status().Update(ctx, &dbBackup); err != nil {
-				log.Error(err, "Failed to update status after job completion")
+			dbBackup.Status.ActiveBackupJobUID = ""
+			recordBackupProgress(dbBackup, nil)
+		}
+	}
+
+	// Check if there's an active volume snapshot
+	if dbBackup.Status.ActiveSnapshot != "" {
+		if err := r.syncActiveSnapshot(ctx, dbBackup); err != nil {
+			log.Error(err, "Failed to check active volume snapshot")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Check if an exec backup has finished
+	execPod, execManual := dbBackup.Status.ActiveExec, dbBackup.Status.ManualBackupRun == dbBackup.Status.ActiveExec
+	if dbBackup.Status.ActiveExec != "" && r.syncActiveExec(dbBackup) {
+		// The same pod runs every exec backup, so entries are named after the run's start
+		if dbBackup.Spec.AuditLog {
+			name := dbBackup.Name + "-exec"
+			if start := dbBackup.Status.LastBackupStartTime; start != nil {
+				name = fmt.Sprintf("%s-exec-%s", dbBackup.Name, start.UTC().Format("20060102150405"))
+			}
+			if err := r.recordAuditEntry(ctx, dbBackup, newAuditEntry(dbBackup, name, execPod, execManual)); err != nil {
+				log.Error(err, "Failed to write audit log entry")
 				return ctrl.Result{}, err
 			}
 		}
 	}
 
+	// Page on-call about repeated failures, and stand down after a success
+	incidentRequeue, err := r.syncIncident(ctx, dbBackup)
+	if err != nil {
+		log.Error(err, "Failed to sync incident")
+		return ctrl.Result{}, err
+	}
+
+	// Retry backup metadata that couldn't be published
+	metadataRequeue, err := r.syncBackupMetadata(ctx, dbBackup)
+	if err != nil {
+		log.Error(err, "Failed to sync backup metadata")
+		return ctrl.Result{}, err
+	}
+
+	// Run any due restore test and track the running one
+	restoreTestRequeue, err := r.reconcileRestoreTest(ctx, dbBackup)
+	if err != nil {
+		log.Error(err, "Failed to reconcile restore test")
+		return ctrl.Result{}, err
+	}
+
+	// Retry failed destinations from the staged artifact
+	reuploadRequeue, err := r.reconcileReupload(ctx, dbBackup)
+	if err != nil {
+		log.Error(err, "Failed to reconcile re-upload")
+		return ctrl.Result{}, err
+	}
+
+	// Verify stored artifacts on their own schedule
+	integrityCheckRequeue, err := r.reconcileIntegrityCheck(ctx, dbBackup)
+	if err != nil {
+		log.Error(err, "Failed to reconcile integrity check")
+		return ctrl.Result{}, err
+	}
+
+	// Warn before retention cleanup leaves no valid backup
+	retentionRiskChanged, retentionRiskRequeue := checkRetentionRisk(dbBackup, time.Now())
+	if retentionRiskChanged {
+		if meta.IsStatusConditionTrue(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionRetentionRisk) {
+			condition := meta.FindStatusCondition(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionRetentionRisk)
+			r.Recorder.Event(dbBackup, corev1.EventTypeWarning, dbbackupv1alpha1.ConditionRetentionRisk, condition.Message)
+		}
+	}
+
+	// Warn about other DatabaseBackups backing up the same database at
+	// around the same time
+	overlapChanged, err := r.checkScheduleOverlap(ctx, dbBackup, time.Now())
+	if err != nil {
+		log.Error(err, "Failed to check for overlapping schedules")
+		return ctrl.Result{}, err
+	}
+	if overlapChanged {
+		if meta.IsStatusConditionTrue(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionPotentialConflict) {
+			condition := meta.FindStatusCondition(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionPotentialConflict)
+			r.Recorder.Event(dbBackup, corev1.EventTypeWarning, dbbackupv1alpha1.ConditionPotentialConflict, condition.Message)
+		}
+	}
+
 	// Calculate next run based on cron schedule
 	schedule, err := cron.ParseStandard(dbBackup.Spec.Schedule)
 	if err != nil {
 		log.Error(err, "Failed to parse schedule", "schedule", dbBackup.Spec.Schedule)
 		dbBackup.Status.LastBackupStatus = "Error"
-		dbBackup.Status.FailureReason = fmt.Sprintf("Invalid schedule: %v", err)
-		if err := r.Status().Update(ctx, &dbBackup); err != nil {
-			return ctrl.Result{}, err
-		}
+		dbBackup.Status.FailureReason = describeScheduleError(dbBackup.Spec.Schedule, err)
+		dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureInvalidSchedule
+		setScheduleValid(dbBackup, err)
 		return ctrl.Result{}, nil
 	}
 
+	// Describe the schedule so users can check it matches their intent
+	setScheduleValid(dbBackup, nil)
+	dbBackup.Status.ScheduleDescription = describeSchedule(dbBackup.Spec.Schedule)
+
 	// Calculate next scheduled run
-	nextRun := schedule.Next(time.Now())
+	nextRun := nextScheduledRun(schedule, dbBackup, time.Now())
 	nextRunMetaTime := metav1.NewTime(nextRun)
 	
-	// Update next scheduled backup if it's changed
-	if dbBackup.Status.NextScheduledBackup == nil || 
-		!dbBackup.Status.NextScheduledBackup.Equal(&nextRunMetaTime) {
+	// Update next scheduled backup if it's changed. A slot that is already due
+	// is kept so the backup for it is still launched (and named after it)
+	if dbBackup.Status.NextScheduledBackup == nil ||
+		(!isTimeToBackup(dbBackup.Status.NextScheduledBackup) && !dbBackup.Status.NextScheduledBackup.Equal(&nextRunMetaTime)) {
 		dbBackup.Status.NextScheduledBackup = &nextRunMetaTime
-		if err := r.Status().Update(ctx, &dbBackup); err != nil {
-			log.Error(err, "Failed to update next scheduled backup time")
-			return ctrl.Result{}, err
-		}
 	}
 
+	// Skip a run missed by more than the starting deadline rather than running it late
+	if missed := dbBackup.Status.NextScheduledBackup; skipMissedRun(schedule, dbBackup, time.Now()) {
+		missedBy := time.Since(missed.Time)
+		log.Info("Missed scheduled backup beyond starting deadline, skipping", "scheduled", missed.Time, "missed_by", missedBy)
+		r.Recorder.Eventf(dbBackup, corev1.EventTypeWarning, "MissedSchedule",
+			"Skipped backup scheduled for %s, missed by %s (starting deadline %ds)",
+			missed.UTC().Format(time.RFC3339), missedBy.Round(time.Second), *dbBackup.Spec.StartingDeadlineSeconds)
+	}
+
+	// Show the runs after that too, for planning around them
+	dbBackup.Status.UpcomingBackups = upcomingBackups(schedule, dbBackup, dbBackup.Status.NextScheduledBackup.Time)
+
 	// If no active backup job and it's time to run one
-	if dbBackup.Status.ActiveBackupJob == "" && isTimeToBackup(dbBackup.Status.NextScheduledBackup) {
-		// Create a backup job
-		job, err := r.createBackupJob(ctx, &dbBackup)
-		if err != nil {
-			log.Error(err, "Failed to create backup job")
-			dbBackup.Status.LastBackupStatus = "Error"
-			dbBackup.Status.FailureReason = fmt.Sprintf("Failed to create backup job: %v", err)
-			if updateErr := r.Status().Update(ctx, &dbBackup); updateErr != nil {
-				log.Error(updateErr, "Failed to update status after job creation failure")
+	if dbBackup.Status.ActiveBackupJob == "" && dbBackup.Status.ActiveSnapshot == "" && dbBackup.Status.ActiveExec == "" &&
+		(isTimeToBackup(dbBackup.Status.NextScheduledBackup) || dbBackup.Status.ManualBackupPending) {
+		// Triggered backups run now rather than in the scheduled slot
+		scheduledTime := dbBackup.Status.NextScheduledBackup.Time
+		manual := dbBackup.Status.ManualBackupPending && !isTimeToBackup(dbBackup.Status.NextScheduledBackup)
+		if manual {
+			scheduledTime = time.Now()
+		}
+
+		// Give a cancelled job's pods their grace period to clean up partial
+		// uploads before starting its replacement
+		if dbBackup.Status.CancellingJob != "" {
+			var cancelled batchv1.Job
+			err := r.Get(ctx, types.NamespacedName{Name: dbBackup.Status.CancellingJob, Namespace: dbBackup.Namespace}, &cancelled)
+			if err == nil {
+				log.V(1).Info("Waiting for cancelled backup job to terminate", "job_name", cancelled.Name)
+				return ctrl.Result{RequeueAfter: waitForCancelledJobRequeue}, nil
 			}
-			return ctrl.Result{}, err
+			if !errors.IsNotFound(err) {
+				log.Error(err, "Failed to check cancelled backup job")
+				return ctrl.Result{}, err
+			}
+			dbBackup.Status.CancellingJob = ""
 		}
 
-		// Update status with active job
-		dbBackup.Status.ActiveBackupJob = job.Name
-		dbBackup.Status.LastBackupStatus = "Running"
-		if err := r.Status().Update(ctx, &dbBackup); err != nil {
-			log.Error(err, "Failed to update status with active job")
-			return ctrl.Result{}, err
+		// Let a running re-upload finish before the staged artifact is superseded
+		if dbBackup.Status.ActiveReuploadJob != "" {
+			log.V(1).Info("Waiting for re-upload to finish", "job_name", dbBackup.Status.ActiveReuploadJob)
+			return ctrl.Result{RequeueAfter: reuploadPollInterval}, nil
 		}
 
-		// Calculate next run
-		nextRun = schedule.Next(time.Now())
-		dbBackup.Status.NextScheduledBackup = &metav1.Time{Time: nextRun}
-		if err := r.Status().Update(ctx, &dbBackup); err != nil {
-			log.Error(err, "Failed to update next scheduled backup time")
-			return ctrl.Result{}, err
+		// Hold every backup during a global pause. Slots missed meanwhile are
+		// dropped, unless a starting deadline decides whether they still run,
+		// so clearing the pause doesn't start them all at once
+		if paused {
+			log.V(1).Info("Backups globally paused, deferring backup")
+			if !manual && dbBackup.Spec.StartingDeadlineSeconds == nil {
+				dbBackup.Status.NextScheduledBackup = &metav1.Time{Time: nextScheduledRun(schedule, dbBackup, time.Now())}
+			}
+			return ctrl.Result{}, nil
+		}
+
+		// Don't launch more doomed jobs once auto-suspended
+		if meta.IsStatusConditionTrue(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionAutoSuspended) {
+			log.V(1).Info("Backups auto-suspended after repeated failures, skipping")
+			return ctrl.Result{RequeueAfter: incidentRequeue}, nil
+		}
+
+		// Never start a scheduled backup outside the configured window
+		if dbBackup.Spec.BackupWindow != nil && !manual {
+			delay, err := backupWindowDelay(dbBackup.Spec.BackupWindow, time.Now())
+			if err != nil {
+				log.Error(err, "Invalid backup window")
+				dbBackup.Status.LastBackupStatus = "Error"
+				dbBackup.Status.FailureReason = fmt.Sprintf("Invalid backup window: %v", err)
+				dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureInvalidBackupWindow
+				return ctrl.Result{}, nil
+			}
+			if delay > 0 {
+				log.V(1).Info("Outside backup window, deferring backup", "opens_in", delay)
+				return ctrl.Result{RequeueAfter: delay}, nil
+			}
+		}
+
+		// Wait for the backups this one depends on to succeed first
+		if len(dbBackup.Spec.DependsOn) > 0 {
+			pending, missing, err := r.pendingDependencies(ctx, dbBackup)
+			if err != nil {
+				log.Error(err, "Failed to check backup dependencies")
+				return ctrl.Result{}, err
+			}
+			missingChanged := setDependencyMissing(dbBackup, missing)
+			if missingChanged && len(missing) > 0 {
+				r.Recorder.Eventf(dbBackup, corev1.EventTypeWarning, dbbackupv1alpha1.ConditionDependencyMissing,
+					"Waiting on DatabaseBackups %s, which don't exist", strings.Join(missing, ", "))
+			}
+			if len(pending) > 0 {
+				log.V(1).Info("Waiting for dependencies, deferring backup", "pending", pending, "missing", missing)
+				dbBackup.Status.LastBackupStatus = "WaitingForDependencies"
+				return ctrl.Result{RequeueAfter: waitForDependenciesRequeue}, nil
+			}
+		}
+
+		// Hold off until the target database can actually be backed up
+		if dbBackup.Spec.WaitForReady != nil && *dbBackup.Spec.WaitForReady {
+			ready, reason, err := r.isGroupReady(ctx, dbBackup)
+			if err != nil {
+				log.Error(err, "Failed to check target database readiness")
+				return ctrl.Result{}, err
+			}
+			if !ready {
+				log.V(1).Info("Target database not ready, deferring backup", "reason", reason)
+				dbBackup.Status.LastBackupStatus = "WaitingForDatabase"
+				return ctrl.Result{RequeueAfter: waitForDatabaseRequeue}, nil
+			}
+		}
+
+		// Refuse to back up a database larger than the cost guardrail allows
+		if dbBackup.Spec.MaxDatabaseSizeBytes != nil {
+			size, exceeded, err := r.exceedsSizeLimit(ctx, dbBackup)
+			if err != nil {
+				log.Error(err, "Failed to check target database size")
+				return ctrl.Result{}, err
+			}
+			if exceeded {
+				message := fmt.Sprintf("Database volume is %d bytes, over maxDatabaseSizeBytes (%d)", size, *dbBackup.Spec.MaxDatabaseSizeBytes)
+				log.Info("Database over size limit, refusing backup", "size", size, "limit", *dbBackup.Spec.MaxDatabaseSizeBytes)
+				r.Recorder.Event(dbBackup, corev1.EventTypeWarning, "SizeLimitExceeded", message)
+				dbBackup.Status.LastBackupStatus = "SizeLimitExceeded"
+				dbBackup.Status.FailureReason = message
+				dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureSizeLimitExceeded
+				if manual {
+					rejectManualBackup(dbBackup, "SizeLimitExceeded", message)
+				} else {
+					dbBackup.Status.NextScheduledBackup = &metav1.Time{Time: nextRunAfterSlot(schedule, dbBackup, scheduledTime, time.Now())}
+				}
+				return ctrl.Result{RequeueAfter: time.Until(dbBackup.Status.NextScheduledBackup.Time)}, nil
+			}
+		}
+
+		// Wait for a free slot under the controller-wide concurrency limit
+		if r.MaxConcurrentBackups > 0 && !isSnapshotMode(dbBackup) && !isExecMode(dbBackup) {
+			reserved, err := r.reserveBackupSlot(ctx, dbBackup)
+			if err != nil {
+				log.Error(err, "Failed to check for a free backup slot")
+				return ctrl.Result{}, err
+			}
+			if !reserved {
+				dbBackup.Status.LastBackupStatus = "WaitingForSlot"
+				return ctrl.Result{RequeueAfter: waitForSlotRequeue}, nil
+			}
+			// The Job counts as active once created, which happens before
+			// this reconcile returns
+			defer r.releaseBackupSlot()
+		}
+
+		// The next run is computed from the slot being started rather than
+		// from now, so delayed starts don't shift the schedule. It is stored
+		// in the same status write that records the start, so the slot is
+		// handled as soon as its backup exists and a caught-up slot can't be
+		// started again by a later reconcile
+		nextRun = nextRunAfterSlot(schedule, dbBackup, scheduledTime, time.Now())
+
+		if isExecMode(dbBackup) {
+			// Run the backup inside the target pod instead of a new one
+			podName, err := r.startExecBackup(ctx, dbBackup)
+			if err != nil {
+				log.Error(err, "Failed to start exec backup")
+				dbBackup.Status.LastBackupStatus = "Error"
+				dbBackup.Status.FailureReason = fmt.Sprintf("Failed to start exec backup: %v", err)
+				dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureJobCreateFailed
+				return ctrl.Result{}, err
+			}
+
+			// Update status with active exec
+			now := metav1.Now()
+			dbBackup.Status.ActiveExec = podName
+			dbBackup.Status.NextScheduledBackup = &metav1.Time{Time: nextRun}
+			dbBackup.Status.LastBackupStartTime = &now
+			dbBackup.Status.LastBackupStatus = "Running"
+			if dbBackup.Status.ManualBackupPending {
+				startManualBackup(dbBackup, podName)
+			}
+		} else if isSnapshotMode(dbBackup) {
+			// Snapshot the database volume instead of running a dump job
+			snapshot, err := r.createVolumeSnapshot(ctx, dbBackup, scheduledTime, manual)
+			if err != nil {
+				log.Error(err, "Failed to create volume snapshot")
+				dbBackup.Status.LastBackupStatus = "Error"
+				dbBackup.Status.FailureReason = fmt.Sprintf("Failed to create volume snapshot: %v", err)
+				dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureSnapshotCreateFailed
+				return ctrl.Result{}, err
+			}
+
+			// Update status with active snapshot
+			dbBackup.Status.ActiveSnapshot = snapshot.Name
+			dbBackup.Status.NextScheduledBackup = &metav1.Time{Time: nextRun}
+			dbBackup.Status.LastBackupStartTime = &snapshot.CreationTimestamp
+			dbBackup.Status.LastBackupStatus = "Running"
+			if dbBackup.Status.ManualBackupPending {
+				startManualBackup(dbBackup, snapshot.Name)
+			}
+		} else {
+			// Create a backup job
+			job, err := r.createBackupJob(ctx, dbBackup, scheduledTime, manual)
+			if errors.IsForbidden(err) {
+				// Retrying right away can't succeed before quota is freed or
+				// RBAC fixed, so the slot is retried after a longer backoff
+				log.Error(err, "Backup job creation forbidden, backing off", "backoff", forbiddenJobRequeue)
+				dbBackup.Status.LastBackupStatus = "Error"
+				dbBackup.Status.FailureReason = fmt.Sprintf("Failed to create backup job: %v", err)
+				dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureJobCreateFailed
+				if isQuotaExceeded(err) {
+					r.Recorder.Event(dbBackup, corev1.EventTypeWarning, "QuotaExceeded", err.Error())
+					dbBackup.Status.LastBackupStatus = "QuotaExceeded"
+					dbBackup.Status.FailureReason = err.Error()
+					dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureQuotaExceeded
+				}
+				return ctrl.Result{RequeueAfter: forbiddenJobRequeue}, nil
+			}
+			if err != nil {
+				log.Error(err, "Failed to create backup job")
+				dbBackup.Status.LastBackupStatus = "Error"
+				dbBackup.Status.FailureReason = fmt.Sprintf("Failed to create backup job: %v", err)
+				dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureJobCreateFailed
+				return ctrl.Result{}, err
+			}
+
+			// Launch the rest of the consistency group alongside it
+			dbBackup.Status.Targets = nil
+			if len(dbBackup.Spec.Targets) > 0 {
+				if err := r.createTargetJobs(ctx, dbBackup, job, scheduledTime); err != nil {
+					log.Error(err, "Failed to create consistency group target jobs")
+					dbBackup.Status.LastBackupStatus = "Error"
+					dbBackup.Status.FailureReason = fmt.Sprintf("Failed to create target job: %v", err)
+					dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureJobCreateFailed
+					return ctrl.Result{}, err
+				}
+			}
+
+			log.Info("Started backup job", "job_name", job.Name, "phase", "Running")
+
+			// Update status with active job. A new artifact supersedes any
+			// pending re-upload, whose staged artifact the new Job removes
+			if pending := dbBackup.Status.PendingReupload; pending != nil {
+				r.Recorder.Eventf(dbBackup, corev1.EventTypeNormal, "ReuploadSuperseded",
+					"Dropping the re-upload of %s to %v, backup job %s replaces it", pending.Artifact, pending.Destinations, job.Name)
+			}
+			dbBackup.Status.ActiveBackupJob = job.Name
+			dbBackup.Status.ActiveBackupJobUID = job.UID
+			dbBackup.Status.NextScheduledBackup = &metav1.Time{Time: nextRun}
+			dbBackup.Status.PendingReupload = nil
+			if rendered, err := renderJob(job); err != nil {
+				log.Error(err, "Failed to render backup job")
+			} else {
+				dbBackup.Status.LastRenderedJob = rendered
+			}
+			dbBackup.Status.LastBackupStartTime = nil
+			dbBackup.Status.LastBackupStatus = "Running"
+			if dbBackup.Status.ManualBackupPending {
+				startManualBackup(dbBackup, job.Name)
+			}
+		}
+	}
+
+	// Requeue based on next scheduled backup
+	var requeueAfter time.Duration
+	if dbBackup.Status.NextScheduledBackup != nil {
+		requeueAfter = time.Until(dbBackup.Status.NextScheduledBackup.Time)
+		if requeueAfter < 0 {
+			requeueAfter = time.Second // Requeue immediately if we're past the scheduled time
+		}
+	} else {
+		requeueAfter = time.Minute // Default requeue time if next backup time is not set
+	}
+
+	// Wake up for the next restore test if it comes first
+	if restoreTestRequeue > 0 && requeueAfter > restoreTestRequeue {
+		requeueAfter = restoreTestRequeue
+	}
+
+	// Check back on a pending re-upload
+	if reuploadRequeue > 0 && requeueAfter > reuploadRequeue {
+		requeueAfter = reuploadRequeue
+	}
+
+	// Likewise for the next integrity check
+	if integrityCheckRequeue > 0 && requeueAfter > integrityCheckRequeue {
+		requeueAfter = integrityCheckRequeue
+	}
+
+	// Retry an incident the provider didn't accept
+	if incidentRequeue > 0 && requeueAfter > incidentRequeue {
+		requeueAfter = incidentRequeue
+	}
+
+	// Retry publishing backup metadata
+	if metadataRequeue > 0 && requeueAfter > metadataRequeue {
+		requeueAfter = metadataRequeue
+	}
+
+	// Wake up when the last good backup starts nearing expiry
+	if retentionRiskRequeue > 0 && requeueAfter > retentionRiskRequeue {
+		requeueAfter = retentionRiskRequeue
+	}
+
+	// Poll a running job so status catches up even if a Job event is missed
+	if dbBackup.Status.ActiveBackupJob != "" {
+		pollInterval := r.RunningJobPollInterval
+		if pollInterval <= 0 {
+			pollInterval = DefaultRunningJobPollInterval
+		}
+		if requeueAfter > pollInterval {
+			requeueAfter = pollInterval
+		}
+	}
+
+	// Poll a running exec backup in case its completion event was dropped
+	if dbBackup.Status.ActiveExec != "" && requeueAfter > execPollInterval {
+		requeueAfter = execPollInterval
+	}
+
+	// Poll an in-progress snapshot until it is ready
+	if dbBackup.Status.ActiveSnapshot != "" && requeueAfter > snapshotPollInterval {
+		requeueAfter = snapshotPollInterval
+	}
+
+	log.V(1).Info("Reconciled", "phase", dbBackup.Status.LastBackupStatus, "job_name", dbBackup.Status.ActiveBackupJob,
+		"next_run", dbBackup.Status.NextScheduledBackup, "result", requeueAfter.String())
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// Helper function to remove an annotation with a patch that touches nothing
+// else. The patch returns the stored status, so the status built so far is
+// put back for the final write
+func (r *DatabaseBackupReconciler) removeAnnotation(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, key string) error {
+	status := dbBackup.Status.DeepCopy()
+	patch := client.MergeFrom(dbBackup.DeepCopy())
+	delete(dbBackup.Annotations, key)
+	if err := r.Patch(ctx, dbBackup, patch); err != nil {
+		return err
+	}
+	dbBackup.Status = *status
+	return nil
+}
+
+// Helper function to record the APIUnavailable condition once the circuit
+// breaker opens. This is best effort since the API server is struggling;
+// only the condition is touched so schedule state is preserved.
+func (r *DatabaseBackupReconciler) markAPIUnavailable(ctx context.Context, req ctrl.Request, apiErr error) {
+	var dbBackup dbbackupv1alpha1.DatabaseBackup
+	if err := r.Get(ctx, req.NamespacedName, &dbBackup); err != nil {
+		return
+	}
+	meta.SetStatusCondition(&dbBackup.Status.Conditions, metav1.Condition{
+		Type:               dbbackupv1alpha1.ConditionAPIUnavailable,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: dbBackup.Generation,
+		Reason:             "TransientAPIErrors",
+		Message:            fmt.Sprintf("Backing off after repeated API server errors: %v", apiErr),
+	})
+	if err := r.Status().Update(ctx, &dbBackup); err != nil {
+		log.FromContext(ctx).V(1).Info("Failed to record API unavailable condition", "error", err.Error())
+	}
+}
+
+// reservedVolumeNames are the volumes createBackupJob manages itself
+var reservedVolumeNames = map[string]bool{
+	"backup-storage":      true,
+	"storage-credentials": true,
+	"storage-token":       true,
+	"encryption-key":      true,
+	"backup-progress":     true,
+	"db-credentials":      true,
+	"db-tls":              true,
+	"staging":             true,
+	"storage-ca":          true,
+	"pipeline":            true,
+}
+
+// fileExtensionPattern matches one or more dot-separated suffixes such as .sql.gz
+var fileExtensionPattern = regexp.MustCompile(`^(\.[A-Za-z0-9]+)+$`)
+
+// bandwidthLimitPattern matches a size per second such as 50MB/s or 512KiB/s
+var bandwidthLimitPattern = regexp.MustCompile(`^([0-9]+(\.[0-9]+)?)([KMGT]i?)?B/s$`)
+
+// maxBackupWorkers bounds the workers a single backup pod may run
+const maxBackupWorkers = 32
+
+// Limits on BackupTags, matching what S3 object tagging accepts
+const (
+	maxBackupTags        = 10
+	maxBackupTagKeyLen   = 128
+	maxBackupTagValueLen = 256
+)
+
+// Helper function to validate the parts of a spec the CRD schema can't express
+func validateSpec(spec *dbbackupv1alpha1.DatabaseBackupSpec) error {
+	volumeNames := map[string]bool{}
+	for _, volume := range spec.ExtraVolumes {
+		if reservedVolumeNames[volume.Name] ||
+			strings.HasPrefix(volume.Name, "backup-storage-") || strings.HasPrefix(volume.Name, "storage-credentials-") {
+			return fmt.Errorf("extra volume name %q is reserved", volume.Name)
+		}
+		if volumeNames[volume.Name] {
+			return fmt.Errorf("duplicate extra volume name %q", volume.Name)
+		}
+		volumeNames[volume.Name] = true
+	}
+	for _, mount := range spec.ExtraVolumeMounts {
+		if !volumeNames[mount.Name] {
+			return fmt.Errorf("extra volume mount %q does not reference an extra volume", mount.Name)
+		}
+	}
+	if spec.NamingTemplate != "" {
+		sample := artifactNameData{
+			Name:         "example",
+			Namespace:    "default",
+			DatabaseType: spec.DatabaseType,
+			Date:         "20060102",
+			Timestamp:    "20060102150405",
+		}
+		if _, err := renderArtifactName(spec.NamingTemplate, sample); err != nil {
+			return fmt.Errorf("invalid naming template: %w", err)
+		}
+	}
+	if spec.FileExtension != "" && !fileExtensionPattern.MatchString(spec.FileExtension) {
+		return fmt.Errorf("invalid fileExtension %q, expected dot-separated suffixes such as .sql.gz", spec.FileExtension)
+	}
+	if immutability := spec.StorageDestination.Immutability; immutability != nil && immutability.RetainUntilDuration.Duration < 24*time.Hour {
+		return fmt.Errorf("immutability retainUntilDuration must be at least 24h")
+	}
+	if endpoint := spec.StorageDestination.Endpoint; endpoint != "" {
+		u, err := url.Parse(endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("storage endpoint %q must be an http or https URL", endpoint)
+		}
+	}
+	if spec.BackupType == "incremental" && spec.DatabaseType != "postgres" {
+		return fmt.Errorf("incremental backups are only supported for postgres")
+	}
+	if spec.StorageDestination.SecretName != "" && spec.StorageDestination.WorkloadIdentity != nil {
+		return fmt.Errorf("storage secretName and workloadIdentity are mutually exclusive")
+	}
+	if spec.Parallelism != nil && spec.Completions != nil && *spec.Completions < *spec.Parallelism {
+		return fmt.Errorf("completions (%d) must be at least parallelism (%d)", *spec.Completions, *spec.Parallelism)
+	}
+	if spec.DatabaseRef != nil && len(spec.DatabaseSelector.MatchLabels) == 0 && len(spec.DatabaseSelector.MatchExpressions) == 0 {
+		switch {
+		case spec.Mode == "exec" || spec.Mode == "snapshot":
+			return fmt.Errorf("%s mode requires databaseSelector", spec.Mode)
+		case spec.WaitForReady != nil && *spec.WaitForReady:
+			return fmt.Errorf("waitForReady requires databaseSelector")
+		case spec.MaxDatabaseSizeBytes != nil:
+			return fmt.Errorf("maxDatabaseSizeBytes requires databaseSelector")
+		}
+	}
+	if spec.DatabaseRef != nil && spec.PreferRole != nil {
+		return fmt.Errorf("databaseRef and preferRole are mutually exclusive")
+	}
+	if spec.ShardedBackup && (spec.Completions == nil || *spec.Completions <= 1) {
+		return fmt.Errorf("shardedBackup requires completions greater than 1")
+	}
+	if limit := spec.BandwidthLimit; limit != "" {
+		match := bandwidthLimitPattern.FindStringSubmatch(limit)
+		if match == nil {
+			return fmt.Errorf("bandwidth limit %q must be a size per second, e.g. 50MB/s", limit)
+		}
+		if rate, _ := strconv.ParseFloat(match[1], 64); rate == 0 {
+			return fmt.Errorf("bandwidth limit %q must be greater than zero", limit)
+		}
+	}
+	if len(spec.Targets) > 0 {
+		if err := validateTargets(spec); err != nil {
+			return err
+		}
+	}
+	if spec.ReuploadFailedDestinations != nil {
+		switch {
+		case len(spec.StorageDestinations) == 0:
+			return fmt.Errorf("reuploadFailedDestinations requires storageDestinations")
+		case spec.Mode == "snapshot" || spec.Mode == "exec":
+			return fmt.Errorf("reuploadFailedDestinations is not supported in %s mode", spec.Mode)
+		case spec.StreamToStorage != nil && *spec.StreamToStorage:
+			return fmt.Errorf("reuploadFailedDestinations needs a staged artifact, which streamToStorage doesn't write")
+		}
+	}
+	if spec.PreBackupIntegrityCheck != nil && *spec.PreBackupIntegrityCheck {
+		if _, ok := consistencyCheckCommands[spec.DatabaseType]; !ok {
+			return fmt.Errorf("preBackupIntegrityCheck is not supported for %s", spec.DatabaseType)
+		}
+		if spec.Mode == "snapshot" || spec.Mode == "exec" {
+			return fmt.Errorf("preBackupIntegrityCheck is not supported in %s mode", spec.Mode)
+		}
+	}
+	if spec.Workers < 0 || spec.Workers > maxBackupWorkers {
+		return fmt.Errorf("workers (%d) must be between 1 and %d", spec.Workers, maxBackupWorkers)
+	}
+	if spec.Workers > 0 && spec.Mode == "snapshot" {
+		return fmt.Errorf("workers is not supported in snapshot mode")
+	}
+	if spec.PipelineMode != nil && *spec.PipelineMode {
+		switch {
+		case spec.Mode == "snapshot" || spec.Mode == "exec":
+			return fmt.Errorf("pipelineMode is not supported in %s mode", spec.Mode)
+		case spec.StreamToStorage != nil && *spec.StreamToStorage:
+			return fmt.Errorf("pipelineMode and streamToStorage are mutually exclusive")
+		case spec.ReuploadFailedDestinations != nil:
+			return fmt.Errorf("pipelineMode and reuploadFailedDestinations are mutually exclusive")
+		}
+	}
+	if spec.StorageCABundleSecret != "" && (spec.Mode == "snapshot" || spec.Mode == "exec") {
+		return fmt.Errorf("storageCABundleSecret is not supported in %s mode", spec.Mode)
+	}
+	if spec.StoreLogs != nil && *spec.StoreLogs && (spec.Mode == "snapshot" || spec.Mode == "exec") {
+		return fmt.Errorf("storeLogs is not supported in %s mode", spec.Mode)
+	}
+	if spec.SuccessLogPattern != "" {
+		if spec.Mode == "snapshot" || spec.Mode == "exec" {
+			return fmt.Errorf("successLogPattern is not supported in %s mode", spec.Mode)
+		}
+		if _, err := regexp.Compile(spec.SuccessLogPattern); err != nil {
+			return fmt.Errorf("invalid successLogPattern: %w", err)
+		}
+	}
+	if spec.StreamToStorage != nil && *spec.StreamToStorage {
+		if spec.Mode == "snapshot" {
+			return fmt.Errorf("streamToStorage is not supported in snapshot mode")
+		}
+		for _, dest := range append([]dbbackupv1alpha1.StorageDestinationSpec{spec.StorageDestination}, spec.StorageDestinations...) {
+			if dest.Type == "pvc" {
+				return fmt.Errorf("streamToStorage requires object storage, not a pvc destination")
+			}
+		}
+	}
+	if len(spec.BackupTags) > maxBackupTags {
+		return fmt.Errorf("at most %d backup tags are allowed, got %d", maxBackupTags, len(spec.BackupTags))
+	}
+	for key, value := range spec.BackupTags {
+		if key == "" || len(key) > maxBackupTagKeyLen {
+			return fmt.Errorf("backup tag key %q must be 1 to %d characters", key, maxBackupTagKeyLen)
+		}
+		if len(value) > maxBackupTagValueLen {
+			return fmt.Errorf("backup tag %q value must be at most %d characters", key, maxBackupTagValueLen)
+		}
+	}
+	if err := validateLifecycleRules(spec); err != nil {
+		return err
+	}
+	if len(spec.IncludeTables) > 0 && len(spec.ExcludeTables) > 0 {
+		return fmt.Errorf("includeTables and excludeTables are mutually exclusive")
+	}
+	for _, table := range append(append([]string{}, spec.IncludeTables...), spec.ExcludeTables...) {
+		if table == "" || strings.Contains(table, ",") {
+			return fmt.Errorf("invalid table name %q", table)
+		}
+	}
+	if spec.Mode == "exec" && spec.Exec == nil {
+		return fmt.Errorf("exec is required when mode is exec")
+	}
+	if spec.SchedulingMode == "NativeCronJob" {
+		if err := validateNativeCronJobSpec(spec); err != nil {
+			return err
+		}
+	}
+	if spec.DNSPolicy == corev1.DNSNone && spec.DNSConfig == nil {
+		return fmt.Errorf("dnsConfig is required when dnsPolicy is None")
+	}
+	if spec.Manifest != nil && (path.IsAbs(spec.Manifest.Path) || strings.HasPrefix(path.Clean(spec.Manifest.Path), "..")) {
+		return fmt.Errorf("manifest path %q must be relative to the destination path", spec.Manifest.Path)
+	}
+	if spec.IntegrityCheck != nil {
+		if _, err := cron.ParseStandard(spec.IntegrityCheck.Schedule); err != nil {
+			return fmt.Errorf("invalid integrity check schedule: %w", err)
+		}
+	}
+	return validateDestinations(spec)
+}
+
+// Helper function to find a cycle in the DependsOn graph reachable from
+// dbBackup. Returns the names along the cycle, or nil if there is none.
+func (r *DatabaseBackupReconciler) findDependencyCycle(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) ([]string, error) {
+	path := []string{dbBackup.Name}
+	onPath := map[string]bool{dbBackup.Name: true}
+	visited := map[string]bool{}
+
+	var visit func(dependsOn []string) ([]string, error)
+	visit = func(dependsOn []string) ([]string, error) {
+		for _, name := range dependsOn {
+			if onPath[name] {
+				return append(append([]string{}, path...), name), nil
+			}
+			if visited[name] {
+				continue
+			}
+			visited[name] = true
+
+			var dep dbbackupv1alpha1.DatabaseBackup
+			if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: dbBackup.Namespace}, &dep); err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+
+			path = append(path, name)
+			onPath[name] = true
+			if cycle, err := visit(dep.Spec.DependsOn); cycle != nil || err != nil {
+				return cycle, err
+			}
+			path = path[:len(path)-1]
+			delete(onPath, name)
+		}
+		return nil, nil
+	}
+	return visit(dbBackup.Spec.DependsOn)
+}
+
+// Helper function to list the dependencies that have not succeeded since
+// dbBackup's own last successful backup, and those of them that don't exist
+func (r *DatabaseBackupReconciler) pendingDependencies(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) ([]string, []string, error) {
+	var pending, missing []string
+	for _, name := range dbBackup.Spec.DependsOn {
+		var dep dbbackupv1alpha1.DatabaseBackup
+		if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: dbBackup.Namespace}, &dep); err != nil {
+			if errors.IsNotFound(err) {
+				pending = append(pending, name)
+				missing = append(missing, name)
+				continue
+			}
+			return nil, nil, err
+		}
+
+		depSuccess := dep.Status.LastSuccessfulBackup
+		ownSuccess := dbBackup.Status.LastSuccessfulBackup
+		if depSuccess == nil || (ownSuccess != nil && !depSuccess.After(ownSuccess.Time)) {
+			pending = append(pending, name)
+		}
+	}
+	return pending, missing, nil
+}
+
+// Helper function to set the DependencyMissing condition from the
+// dependencies that don't exist. Returns true if the condition changed
+func setDependencyMissing(dbBackup *dbbackupv1alpha1.DatabaseBackup, missing []string) bool {
+	condition := metav1.Condition{
+		Type:               dbbackupv1alpha1.ConditionDependencyMissing,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: dbBackup.Generation,
+		Reason:             "DependenciesFound",
+		Message:            "Every DatabaseBackup in dependsOn exists",
+	}
+	if len(missing) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "DependencyNotFound"
+		condition.Message = fmt.Sprintf("DatabaseBackups %s in dependsOn don't exist, backups wait until they do", strings.Join(missing, ", "))
+	}
+
+	existing := meta.FindStatusCondition(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionDependencyMissing)
+	if existing != nil && existing.Status == condition.Status && existing.Message == condition.Message {
+		return false
+	}
+	meta.SetStatusCondition(&dbBackup.Status.Conditions, condition)
+	return true
+}
+
+// Helper function to map a changed DatabaseBackup to the ones that depend on it
+func (r *DatabaseBackupReconciler) findDependentBackups(obj client.Object) []reconcile.Request {
+	var backups dbbackupv1alpha1.DatabaseBackupList
+	if err := r.List(context.Background(), &backups, client.InNamespace(obj.GetNamespace()), r.shardSelector()); err != nil {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, dbBackup := range backups.Items {
+		for _, name := range dbBackup.Spec.DependsOn {
+			if name == obj.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+					Name:      dbBackup.Name,
+					Namespace: dbBackup.Namespace,
+				}})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// Helper function to list the Jobs controlled by a DatabaseBackup
+func (r *DatabaseBackupReconciler) listOwnedJobs(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) ([]batchv1.Job, error) {
+	var jobList batchv1.JobList
+	if err := r.List(ctx, &jobList,
+		client.InNamespace(dbBackup.Namespace),
+		client.MatchingLabels{backupNameLabel: dbBackup.Name},
+	); err != nil {
+		return nil, err
+	}
+
+	var owned []batchv1.Job
+	for _, job := range jobList.Items {
+		if _, ok := job.Labels[jobKindLabel]; ok {
+			continue
+		}
+		if metav1.IsControlledBy(&job, dbBackup) {
+			owned = append(owned, job)
+		}
+	}
+	return owned, nil
+}
+
+// Helper function to read straight from the API server, bypassing the cache.
+// Falls back to the client when no APIReader is set
+func (r *DatabaseBackupReconciler) apiReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// Helper function to count running backup Jobs across all namespaces. Target
+// Jobs back up a database like the primary Job and take a slot each
+func (r *DatabaseBackupReconciler) countActiveBackupJobs(ctx context.Context) (int, error) {
+	var jobList batchv1.JobList
+	if err := r.apiReader().List(ctx, &jobList, client.HasLabels{backupNameLabel}); err != nil {
+		return 0, err
+	}
+
+	active := 0
+	for i := range jobList.Items {
+		if kind, ok := jobList.Items[i].Labels[jobKindLabel]; ok && kind != "target" {
+			continue
+		}
+		if !isJobComplete(&jobList.Items[i]) {
+			active++
+		}
+	}
+	return active, nil
+}
+
+// Helper function to take a slot under MaxConcurrentBackups for a backup
+// about to create its Job. Slots reserved by other reconciles count as
+// taken until they release them, and free slots are left to waiting backups
+// of a higher priority. Returns false when no slot is free
+func (r *DatabaseBackupReconciler) reserveBackupSlot(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (bool, error) {
+	r.slotMu.Lock()
+	defer r.slotMu.Unlock()
+
+	active, err := r.countActiveBackupJobs(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to count active backup jobs: %w", err)
+	}
+	preferred, err := r.countHigherPriorityWaiting(ctx, dbBackup)
+	if err != nil {
+		return false, fmt.Errorf("failed to count waiting backups: %w", err)
+	}
+	if active+r.reservedSlots+preferred >= r.MaxConcurrentBackups {
+		log.FromContext(ctx).V(1).Info("Concurrent backup limit reached, deferring backup", "active", active, "reserved", r.reservedSlots,
+			"higher_priority_waiting", preferred, "limit", r.MaxConcurrentBackups)
+		return false, nil
+	}
+	r.reservedSlots++
+	return true, nil
+}
+
+// Helper function to give back a slot taken by reserveBackupSlot
+func (r *DatabaseBackupReconciler) releaseBackupSlot() {
+	r.slotMu.Lock()
+	defer r.slotMu.Unlock()
+	r.reservedSlots--
+}
+
+// Helper function to count the DatabaseBackups waiting for a backup slot
+// with a higher priority than the given one
+func (r *DatabaseBackupReconciler) countHigherPriorityWaiting(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (int, error) {
+	var backupList dbbackupv1alpha1.DatabaseBackupList
+	if err := r.List(ctx, &backupList, r.shardSelector()); err != nil {
+		return 0, err
+	}
+
+	waiting := 0
+	now := time.Now()
+	for i := range backupList.Items {
+		other := &backupList.Items[i]
+		if other.Spec.Priority > dbBackup.Spec.Priority && isWaitingForSlot(other, now) {
+			waiting++
+		}
+	}
+	return waiting, nil
+}
+
+// Helper function to check if a DatabaseBackup is still waiting for a backup
+// slot. WaitingForSlot is only cleared once the backup starts, so a backup
+// that stopped being due meanwhile (paused, suspended, outside its window or
+// past its starting deadline) still shows it and mustn't hold slots back
+func isWaitingForSlot(dbBackup *dbbackupv1alpha1.DatabaseBackup, now time.Time) bool {
+	status := &dbBackup.Status
+	if status.LastBackupStatus != "WaitingForSlot" || !dbBackup.DeletionTimestamp.IsZero() ||
+		status.ActiveBackupJob != "" || status.ActiveSnapshot != "" || status.ActiveExec != "" ||
+		meta.IsStatusConditionTrue(status.Conditions, dbbackupv1alpha1.ConditionAutoSuspended) ||
+		meta.IsStatusConditionTrue(status.Conditions, dbbackupv1alpha1.ConditionGloballyPaused) {
+		return false
+	}
+	if status.ManualBackupPending {
+		return true
+	}
+
+	next := status.NextScheduledBackup
+	if next == nil || next.Time.After(now) {
+		return false
+	}
+	if deadline := dbBackup.Spec.StartingDeadlineSeconds; deadline != nil && now.Sub(next.Time) > time.Duration(*deadline)*time.Second {
+		return false
+	}
+	if dbBackup.Spec.BackupWindow != nil {
+		if delay, err := backupWindowDelay(dbBackup.Spec.BackupWindow, now); err != nil || delay > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Helper function to bring ActiveBackupJob in line with the owned Jobs.
+// A running Job missing from status is adopted; a Job referenced by status
+// that no longer exists, or was replaced by another Job of the same name,
+// is cleared.
+func (r *DatabaseBackupReconciler) syncActiveJob(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) error {
+	log := log.FromContext(ctx)
+
+	jobs, err := r.listOwnedJobs(ctx, dbBackup)
+	if err != nil {
+		return err
+	}
+
+	if dbBackup.Status.ActiveBackupJob != "" {
+		var active *batchv1.Job
+		for i := range jobs {
+			if jobs[i].Name == dbBackup.Status.ActiveBackupJob {
+				active = &jobs[i]
+				break
+			}
+		}
+
+		switch {
+		case active != nil && dbBackup.Status.ActiveBackupJobUID == "":
+			// Recorded before UIDs were tracked
+			dbBackup.Status.ActiveBackupJobUID = active.UID
+			return nil
+		case active != nil && active.UID == dbBackup.Status.ActiveBackupJobUID:
+			return nil
+		case active != nil:
+			log.Info("Active backup job was replaced by another job of the same name, clearing it",
+				"job_name", active.Name, "uid", dbBackup.Status.ActiveBackupJobUID, "new_uid", active.UID)
+		default:
+			log.Info("Active backup job no longer exists, clearing it", "job_name", dbBackup.Status.ActiveBackupJob)
+		}
+		finishManualBackup(dbBackup, dbBackup.Status.ActiveBackupJob)
+		dbBackup.Status.ActiveBackupJob = ""
+		dbBackup.Status.ActiveBackupJobUID = ""
+	}
+
+	// Adopt the newest owned Job that is still running
+	var orphan *batchv1.Job
+	for i := range jobs {
+		if isJobComplete(&jobs[i]) || jobs[i].DeletionTimestamp != nil {
+			continue
+		}
+		if orphan == nil || orphan.CreationTimestamp.Before(&jobs[i].CreationTimestamp) {
+			orphan = &jobs[i]
+		}
+	}
+	if orphan == nil {
+		return nil
+	}
+
+	log.Info("Adopting orphaned backup job", "job_name", orphan.Name)
+	dbBackup.Status.ActiveBackupJob = orphan.Name
+	dbBackup.Status.ActiveBackupJobUID = orphan.UID
+	dbBackup.Status.LastBackupStartTime = nil
+	dbBackup.Status.LastBackupStatus = "Running"
+	return nil
+}
+
+// Helper function to list the pods matched by DatabaseSelector
+func (r *DatabaseBackupReconciler) findTargetPods(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) ([]corev1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&dbBackup.Spec.DatabaseSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database selector: %w", err)
+	}
+
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList,
+		client.InNamespace(dbBackup.Namespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	); err != nil {
+		return nil, err
+	}
+	return podList.Items, nil
+}
+
+// Helper function to pick the pod to back up from the selected pods: a ready
+// pod with the preferred role if there is one, otherwise any ready pod unless
+// the role is required
+func selectTargetPod(pods []corev1.Pod, prefer *dbbackupv1alpha1.PreferRoleSpec) (*corev1.Pod, error) {
+	var fallback *corev1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if !isPodReady(pod) {
+			continue
+		}
+		if prefer == nil {
+			return pod, nil
+		}
+		labelKey := prefer.LabelKey
+		if labelKey == "" {
+			labelKey = dbbackupv1alpha1.DefaultRoleLabelKey
+		}
+		if pod.Labels[labelKey] == prefer.Value {
+			return pod, nil
+		}
+		if fallback == nil {
+			fallback = pod
+		}
+	}
+
+	switch {
+	case prefer != nil && prefer.Required:
+		return nil, fmt.Errorf("no ready pod matching the database selector has role %s", prefer.Value)
+	case fallback == nil:
+		return nil, fmt.Errorf("no ready pod matches the database selector")
+	}
+	return fallback, nil
+}
+
+// Helper function to get the address backup pods reach a database pod at.
+// Pods with a hostname and subdomain, as StatefulSet pods behind a headless
+// Service have, keep their DNS name across restarts; other pods are reached
+// at their current IP
+func podAddress(pod *corev1.Pod) string {
+	if pod.Spec.Hostname != "" && pod.Spec.Subdomain != "" {
+		return fmt.Sprintf("%s.%s.%s.svc", pod.Spec.Hostname, pod.Spec.Subdomain, pod.Namespace)
+	}
+	return pod.Status.PodIP
+}
+
+// Helper function to check whether a target database pod is ready to be
+// backed up. It returns a human-readable reason when it is not.
+func (r *DatabaseBackupReconciler) isDatabaseReady(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (bool, string, error) {
+	pods, err := r.findTargetPods(ctx, dbBackup)
+	if err != nil {
+		return false, "", err
+	}
+	if len(pods) == 0 {
+		return false, "no pods match the database selector", nil
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		if !isPodReady(pod) {
+			continue
+		}
+		if dbBackup.Spec.DatabasePort == 0 {
+			return true, "", nil
+		}
+		addr := net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(dbBackup.Spec.DatabasePort)))
+		conn, err := net.DialTimeout("tcp", addr, databaseDialTimeout)
+		if err != nil {
+			continue
+		}
+		conn.Close()
+		return true, "", nil
+	}
+	return false, "no ready database pod is reachable", nil
+}
+
+// Helper function to check if a pod has the Ready condition
+func isPodReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// Helper function to get the spec generation a job was built from. Jobs that
+// predate the generation annotation report the current generation
+func jobSpecGeneration(job *batchv1.Job, dbBackup *dbbackupv1alpha1.DatabaseBackup) int64 {
+	generation, err := strconv.ParseInt(job.Annotations[specGenerationAnnotation], 10, 64)
+	if err != nil {
+		return dbBackup.Generation
+	}
+	return generation
+}
+
+// Helper function to check if a job was built from an older spec generation.
+// Jobs that predate the generation annotation are never considered outdated.
+func isJobOutdated(job *batchv1.Job, dbBackup *dbbackupv1alpha1.DatabaseBackup) bool {
+	generation, err := strconv.ParseInt(job.Annotations[specGenerationAnnotation], 10, 64)
+	return err == nil && generation < dbBackup.Generation
+}
+
+// Helper function to check if a job is complete
+func isJobComplete(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// Helper function to check if a job is successful
+func isJobSuccessful(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobComplete && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// Helper function to check if a job has failed
+func isJobFailed(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// Helper function to get the time a job's pod started running. Returns nil
+// while the job has not had a pod run yet (e.g. it is still unschedulable).
+func jobStartTime(job *batchv1.Job) *metav1.Time {
+	if job.Status.StartTime == nil {
+		return nil
+	}
+	running := job.Status.Ready != nil && *job.Status.Ready > 0
+	if !running && job.Status.Succeeded == 0 && job.Status.Failed == 0 {
+		return nil
+	}
+	startTime := *job.Status.StartTime
+	return &startTime
+}
+
+// Helper function to get how long until the backup window opens. Returns
+// zero when now is inside the window.
+func backupWindowDelay(window *dbbackupv1alpha1.BackupWindowSpec, now time.Time) (time.Duration, error) {
+	loc := time.UTC
+	if window.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(window.Timezone); err != nil {
+			return 0, err
+		}
+	}
+	start, err := time.Parse("15:04", window.Start)
+	if err != nil {
+		return 0, fmt.Errorf("invalid start %q: %w", window.Start, err)
+	}
+	end, err := time.Parse("15:04", window.End)
+	if err != nil {
+		return 0, fmt.Errorf("invalid end %q: %w", window.End, err)
+	}
+
+	local := now.In(loc)
+	minuteOfDay := local.Hour()*60 + local.Minute()
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := end.Hour()*60 + end.Minute()
+
+	var inWindow bool
+	switch {
+	case startMinute == endMinute:
+		inWindow = true // Window covers the whole day
+	case startMinute < endMinute:
+		inWindow = minuteOfDay >= startMinute && minuteOfDay < endMinute
+	default:
+		inWindow = minuteOfDay >= startMinute || minuteOfDay < endMinute // Window spans midnight
+	}
+	if inWindow {
+		return 0, nil
+	}
+
+	opening := time.Date(local.Year(), local.Month(), local.Day(), start.Hour(), start.Minute(), 0, 0, loc)
+	if !opening.After(local) {
+		opening = opening.AddDate(0, 0, 1)
+	}
+	return opening.Sub(local), nil
+}
+
+// Helper function to count a failed backup, auto-suspending the
+// DatabaseBackup once AutoSuspendAfterFailures is reached
+func recordBackupFailure(dbBackup *dbbackupv1alpha1.DatabaseBackup) {
+	dbBackup.Status.ConsecutiveFailures++
+
+	threshold := dbBackup.Spec.AutoSuspendAfterFailures
+	if threshold > 0 && dbBackup.Status.ConsecutiveFailures >= threshold {
+		meta.SetStatusCondition(&dbBackup.Status.Conditions, metav1.Condition{
+			Type:               dbbackupv1alpha1.ConditionAutoSuspended,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: dbBackup.Generation,
+			Reason:             "ConsecutiveFailures",
+			Message: fmt.Sprintf("Suspended after %d consecutive failures: %s. Edit the spec or set the %s annotation to resume",
+				dbBackup.Status.ConsecutiveFailures, dbBackup.Status.FailureReason, resumeAnnotation),
+		})
+	}
+}
+
+// Helper function to delete the active backup Job on request and record
+// the run as Cancelled. Cancelled runs are not failures, so they don't count
+// towards auto-suspend
+func (r *DatabaseBackupReconciler) cancelBackupJob(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) error {
+	log := log.FromContext(ctx)
+	jobName := dbBackup.Status.ActiveBackupJob
+
+	// Foreground deletion keeps the Job around until its pods have
+	// terminated, so the next backup can wait for their cleanup
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: jobName, Namespace: dbBackup.Namespace}}
+	if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationForeground)); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	if err := r.cancelTargetJobs(ctx, dbBackup); err != nil {
+		return err
+	}
+	log.Info("Cancelled backup job on request", "job_name", jobName)
+	r.Recorder.Eventf(dbBackup, corev1.EventTypeNormal, "BackupCancelled", "Cancelled backup job %s on request", jobName)
+
+	dbBackup.Status.LastBackupStatus = "Cancelled"
+	finishManualBackup(dbBackup, jobName)
+	dbBackup.Status.ActiveBackupJob = ""
+	dbBackup.Status.ActiveBackupJobUID = ""
+	dbBackup.Status.CancellingJob = jobName
+	recordBackupProgress(dbBackup, nil)
+	return nil
+}
+
+// Helper function to compare a successful job's duration against the backup
+// SLO, setting the SLOBreached condition and counting breaches
+func checkBackupSLO(dbBackup *dbbackupv1alpha1.DatabaseBackup, job *batchv1.Job) {
+	slo := dbBackup.Spec.BackupSLO
+	if slo == nil || dbBackup.Status.LastBackupStartTime == nil || job.Status.CompletionTime == nil {
+		return
+	}
+
+	duration := job.Status.CompletionTime.Sub(dbBackup.Status.LastBackupStartTime.Time)
+	condition := metav1.Condition{
+		Type:               dbbackupv1alpha1.ConditionSLOBreached,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: dbBackup.Generation,
+		Reason:             "WithinSLO",
+		Message:            fmt.Sprintf("Backup took %s (limit %s)", duration.Round(time.Second), slo.MaxDuration.Duration),
+	}
+	if duration > slo.MaxDuration.Duration {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "MaxDurationExceeded"
+		sloBreachesTotal.WithLabelValues(dbBackup.Namespace, dbBackup.Name).Inc()
+	}
+	meta.SetStatusCondition(&dbBackup.Status.Conditions, condition)
+}
+
+// Helper function to set the RetentionRisk condition from the age of the last
+// successful backup. Returns true if the condition changed, and how long
+// until it trips when it hasn't yet
+func checkRetentionRisk(dbBackup *dbbackupv1alpha1.DatabaseBackup, now time.Time) (bool, time.Duration) {
+	// Without any successful backup there is nothing left to lose
+	lastSuccess := dbBackup.Status.LastSuccessfulBackup
+	if lastSuccess == nil {
+		return false, 0
+	}
+
+	retention, expires := backupRetention(dbBackup)
+	threshold := time.Duration(float64(retention) * retentionRiskFraction)
+	age := now.Sub(lastSuccess.Time)
+	expiresIn := retention - age
+
+	condition := metav1.Condition{
+		Type:               dbbackupv1alpha1.ConditionRetentionRisk,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: dbBackup.Generation,
+		Reason:             "RecentBackup",
+		Message:            fmt.Sprintf("Last successful backup is %s old (retention %s)", age.Round(time.Minute), retention),
+	}
+	var recheckIn time.Duration
+	switch {
+	case !expires:
+		// Backups that are never deleted can't age out, which also clears
+		// a risk flagged before retention was turned off
+		condition.Reason = "NoRetention"
+		condition.Message = "Backups are kept indefinitely, so the last successful backup can't age out"
+	case expiresIn <= 0:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "LastBackupExpired"
+		condition.Message = fmt.Sprintf("Last successful backup is %s old, past retention (%s); retention cleanup may leave no valid backup",
+			age.Round(time.Minute), retention)
+	case age >= threshold:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "LastBackupExpiring"
+		condition.Message = fmt.Sprintf("Last successful backup falls out of retention in %s with no newer successful backup",
+			expiresIn.Round(time.Minute))
+	default:
+		recheckIn = threshold - age
+	}
+
+	// Only transitions count, so the message's age doesn't churn status
+	existing := meta.FindStatusCondition(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionRetentionRisk)
+	if existing != nil && existing.Status == condition.Status && existing.Reason == condition.Reason {
+		return false, recheckIn
+	}
+	meta.SetStatusCondition(&dbBackup.Status.Conditions, condition)
+	return true, recheckIn
+}
+
+// Helper function to get the next run after now, offset by the object's jitter
+func nextScheduledRun(schedule cron.Schedule, dbBackup *dbbackupv1alpha1.DatabaseBackup, now time.Time) time.Time {
+	if dbBackup.Spec.JitterSeconds <= 0 {
+		return schedule.Next(now)
+	}
+
+	// A tick that has already passed may still have its jittered run ahead
+	tick := schedule.Next(now.Add(-time.Duration(dbBackup.Spec.JitterSeconds) * time.Second))
+	for {
+		next := schedule.Next(tick)
+		run := tick.Add(scheduleJitter(string(dbBackup.UID), dbBackup.Spec.JitterSeconds, next.Sub(tick)))
+		if run.After(now) {
+			return run
+		}
+		tick = next
+	}
+}
+
+// Helper function to move NextScheduledBackup past a run missed by more than
+// the starting deadline. Returns true only if the next run actually moved
+func skipMissedRun(schedule cron.Schedule, dbBackup *dbbackupv1alpha1.DatabaseBackup, now time.Time) bool {
+	deadline := dbBackup.Spec.StartingDeadlineSeconds
+	missed := dbBackup.Status.NextScheduledBackup
+	if deadline == nil || missed == nil || now.Sub(missed.Time) <= time.Duration(*deadline)*time.Second {
+		return false
+	}
+	next := metav1.NewTime(nextScheduledRun(schedule, dbBackup, now))
+	if next.Equal(missed) {
+		return false
+	}
+	dbBackup.Status.NextScheduledBackup = &next
+	return true
+}
+
+// Helper function to get the run following the slot that just started. If
+// starting it took so long that later slots passed too, one of them is
+// caught up and the rest are skipped.
+func nextRunAfterSlot(schedule cron.Schedule, dbBackup *dbbackupv1alpha1.DatabaseBackup, slot, now time.Time) time.Time {
+	next := nextScheduledRun(schedule, dbBackup, slot)
+	for !next.After(now) {
+		following := nextScheduledRun(schedule, dbBackup, next)
+		if following.After(now) {
+			// Catch up only the most recent missed slot
+			return next
+		}
+		next = following
+	}
+	return next
+}
+
+// upcomingBackupCount is how many run times UpcomingBackups lists
+const upcomingBackupCount = 5
+
+// Helper function to list the upcoming run times starting at next
+func upcomingBackups(schedule cron.Schedule, dbBackup *dbbackupv1alpha1.DatabaseBackup, next time.Time) []metav1.Time {
+	upcoming := make([]metav1.Time, 0, upcomingBackupCount)
+	for len(upcoming) < upcomingBackupCount {
+		upcoming = append(upcoming, metav1.NewTime(next))
+		next = nextScheduledRun(schedule, dbBackup, next)
+	}
+	return upcoming
+}
+
+// Helper function to derive a stable offset from the object's UID. The offset
+// is below jitterSeconds and below the interval to the following run, so a
+// jittered run never slips past the next tick.
+func scheduleJitter(uid string, jitterSeconds int32, interval time.Duration) time.Duration {
+	bound := time.Duration(jitterSeconds) * time.Second
+	if interval <= bound {
+		bound = interval - time.Second
+	}
+	if bound <= 0 {
+		return 0
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(uid))
+	return time.Duration(hash.Sum64()%uint64(bound/time.Second)) * time.Second
+}
+
+// Helper function to check if it's time to run a backup
+func isTimeToBackup(nextScheduled *metav1.Time) bool {
+	if nextScheduled == nil {
+		return true // If next scheduled time is not set, run a backup
+	}
+	return time.Now().After(nextScheduled.Time) || time.Now().Equal(nextScheduled.Time)
+}
+
+// Helper function to create a backup job
+// The Job name is derived from the scheduled slot, or from the trigger for a
+// manual backup, so retried reconciles for the same slot or trigger resolve
+// to the same Job instead of creating a duplicate.
+func (r *DatabaseBackupReconciler) createBackupJob(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, scheduledTime time.Time, manual bool) (_ *batchv1.Job, err error) {
+	ctx, span := tracer.Start(ctx, "CreateBackupJob", trace.WithAttributes(
+		attribute.String("k8s.namespace.name", dbBackup.Namespace),
+		attribute.String("k8s.object.name", dbBackup.Name),
+		attribute.String("db.type", dbBackup.Spec.DatabaseType),
+	))
+	defer func() { endSpan(span, err) }()
+
+	job, err := buildBackupJob(r.withStorageDefaults(dbBackup), scheduledTime)
+	if err != nil {
+		return nil, err
+	}
+	if manual {
+		job.Name = manualRunName(dbBackup)
+	}
+	r.addImagePullSecret(&job.Spec.Template.Spec, dbBackup)
+
+	// Point the image at the pod with the preferred role
+	if dbBackup.Spec.PreferRole != nil {
+		pods, err := r.findTargetPods(ctx, dbBackup)
+		if err != nil {
+			return nil, err
+		}
+		pod, err := selectTargetPod(pods, dbBackup.Spec.PreferRole)
+		if err != nil {
+			return nil, err
+		}
+		// The cached pod may predate a restart that changed its IP
+		var current corev1.Pod
+		if err := r.apiReader().Get(ctx, client.ObjectKeyFromObject(pod), &current); err != nil {
+			return nil, fmt.Errorf("failed to get target pod %s: %w", pod.Name, err)
 		}
+		host := podAddress(&current)
+		if host == "" {
+			return nil, fmt.Errorf("target pod %s has no IP yet", pod.Name)
+		}
+		addConnectionEnv(&job.Spec.Template.Spec,
+			corev1.EnvVar{
+				Name:  "TARGET_POD",
+				Value: current.Name,
+			},
+			corev1.EnvVar{
+				Name:  "DB_HOST",
+				Value: host,
+			},
+		)
 	}
 
-	// Requeue based on next scheduled backup
-	var requeueAfter time.Duration
-	if dbBackup.Status.NextScheduledBackup != nil {
-		requeueAfter = time.Until(dbBackup.Status.NextScheduledBackup.Time)
-		if requeueAfter < 0 {
-			requeueAfter = time.Second // Requeue immediately if we're past the scheduled time
+	// Encrypt with the current key, recording which one for restores
+	if dbBackup.Spec.Encryption != nil {
+		keyID, err := r.currentEncryptionKeyID(ctx, dbBackup)
+		if err != nil {
+			return nil, err
 		}
-	} else {
-		requeueAfter = time.Minute // Default requeue time if next backup time is not set
+		addEncryptionKey(&job.Spec.Template.Spec, dbBackup, keyID)
+		job.Annotations[encryptionKeyIDAnnotation] = keyID
 	}
 
-	return ctrl.Result{RequeueAfter: requeueAfter}, nil
-}
+	// Upload from a sidecar while the dump is still being written
+	addUploadPipeline(&job.Spec.Template.Spec, dbBackup)
 
-// Helper function to check if a job is complete
-func isJobComplete(job *batchv1.Job) bool {
-	for _, c := range job.Status.Conditions {
-		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
-			return true
-		}
+	if err := ctrl.SetControllerReference(dbBackup, job, r.Scheme); err != nil {
+		return nil, err
 	}
-	return false
-}
 
-// Helper function to check if a job is successful
-func isJobSuccessful(job *batchv1.Job) bool {
-	for _, c := range job.Status.Conditions {
-		if c.Type == batchv1.JobComplete && c.Status == corev1.ConditionTrue {
-			return true
+	if err := r.Create(ctx, job); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return nil, err
 		}
-	}
-	return false
-}
 
-// Helper function to check if a job has failed
-func isJobFailed(job *batchv1.Job) bool {
-	for _, c := range job.Status.Conditions {
-		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
-			return true
+		// A previous reconcile already created the Job for this slot, adopt it
+		var existing batchv1.Job
+		if err := r.Get(ctx, client.ObjectKeyFromObject(job), &existing); err != nil {
+			return nil, err
+		}
+		if !metav1.IsControlledBy(&existing, dbBackup) {
+			return nil, fmt.Errorf("job %s already exists and is not owned by this DatabaseBackup", job.Name)
 		}
+		if existing.DeletionTimestamp != nil {
+			return nil, fmt.Errorf("job %s is still being deleted", job.Name)
+		}
+		return &existing, nil
 	}
-	return false
+
+	return job, nil
 }
 
-// Helper function to check if it's time to run a backup
-func isTimeToBackup(nextScheduled *metav1.Time) bool {
-	if nextScheduled == nil {
-		return true // If next scheduled time is not set, run a backup
-	}
-	return time.Now().After(nextScheduled.Time) || time.Now().Equal(nextScheduled.Time)
+// Helper function to check if a Forbidden error comes from a ResourceQuota
+// rather than RBAC
+func isQuotaExceeded(err error) bool {
+	return errors.IsForbidden(err) && strings.Contains(err.Error(), "exceeded quota")
 }
 
-// Helper function to create a backup job
-func (r *DatabaseBackupReconciler) createBackupJob(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (*batchv1.Job, error) {
+// Helper function to build the backup Job for a scheduled slot, without creating it
+func buildBackupJob(dbBackup *dbbackupv1alpha1.DatabaseBackup, scheduledTime time.Time) (*batchv1.Job, error) {
 	backupImage := getBackupImage(dbBackup.Spec.DatabaseType)
-	
+
+	backoffLimit := dbbackupv1alpha1.DefaultJobBackoffLimit
+	if dbBackup.Spec.JobBackoffLimit != nil {
+		backoffLimit = *dbBackup.Spec.JobBackoffLimit
+	}
+
+	// Incremental backups fall back to a full backup until a base exists
+	backupType := "full"
+	if dbBackup.Spec.BackupType == "incremental" && dbBackup.Status.BaseBackupRef != "" {
+		backupType = "incremental"
+	}
+
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-%s", dbBackup.Name, time.Now().Format("20060102150405")),
+			Name:      backupJobName(dbBackup, scheduledTime),
 			Namespace: dbBackup.Namespace,
 			Labels: map[string]string{
-				"app":                    "db-backup-operator",
-				"databasebackup.db.example.io/name": dbBackup.Name,
+				"app":           "db-backup-operator",
+				backupNameLabel: dbBackup.Name,
+			},
+			Annotations: map[string]string{
+				backupTypeAnnotation:     backupType,
+				specGenerationAnnotation: strconv.FormatInt(dbBackup.Generation, 10),
 			},
 		},
 		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			Parallelism:             dbBackup.Spec.Parallelism,
+			Completions:             dbBackup.Spec.Completions,
+			TTLSecondsAfterFinished: dbBackup.Spec.JobTTLSecondsAfterFinished,
 			Template: corev1.PodTemplateSpec{
 				Spec: corev1.PodSpec{
-					RestartPolicy: corev1.RestartPolicyNever,
+					RestartPolicy:                 corev1.RestartPolicyNever,
+					PriorityClassName:             dbBackup.Spec.PriorityClassName,
+					PreemptionPolicy:              dbBackup.Spec.PreemptionPolicy,
+					TerminationGracePeriodSeconds: dbBackup.Spec.TerminationGracePeriodSeconds,
+					SchedulerName:                 dbBackup.Spec.SchedulerName,
+					ImagePullSecrets:              dbBackup.Spec.ImagePullSecrets,
+					DNSPolicy:                     dbBackup.Spec.DNSPolicy,
+					DNSConfig:                     dbBackup.Spec.DNSConfig,
+					HostAliases:                   dbBackup.Spec.HostAliases,
+					SecurityContext:               dbBackup.Spec.PodSecurityContext,
 					Containers: []corev1.Container{
 						{
-							Name:  "backup",
-							Image: backupImage,
+							Name:            "backup",
+							Image:           backupImage,
+							Command:         dbBackup.Spec.Command,
+							Args:            dbBackup.Spec.Args,
+							SecurityContext: dbBackup.Spec.ContainerSecurityContext,
+							// Env always wins over EnvFrom on duplicate names, so the
+							// operator's own variables can't be overridden here
+							EnvFrom: dbBackup.Spec.EnvFrom,
 							Env: []corev1.EnvVar{
 								{
 									Name:  "DB_TYPE",
@@ -238,6 +2317,14 @@ func (r *DatabaseBackupReconciler) createBackupJob(ctx context.Context, dbBackup
 									Name:  "PATH",
 									Value: dbBackup.Spec.StorageDestination.Path,
 								},
+								{
+									Name:  "BACKUP_TYPE",
+									Value: backupType,
+								},
+								// Pod metadata for tagging artifacts
+								downwardAPIEnv("POD_NAME", "metadata.name"),
+								downwardAPIEnv("POD_NAMESPACE", "metadata.namespace"),
+								downwardAPIEnv("NODE_NAME", "spec.nodeName"),
 							},
 						},
 					},
@@ -246,38 +2333,322 @@ func (r *DatabaseBackupReconciler) createBackupJob(ctx context.Context, dbBackup
 		},
 	}
 
-	// If using PVC for storage, add volume and volume mount
-	if dbBackup.Spec.StorageDestination.Type == "pvc" && dbBackup.Spec.StorageDestination.PVCName != "" {
-		job.Spec.Template.Spec.Volumes = []corev1.Volume{
-			{
-				Name: "backup-storage",
-				VolumeSource: corev1.VolumeSource{
-					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-						ClaimName: dbBackup.Spec.StorageDestination.PVCName,
-					},
+	// Merge user labels (e.g. batch queue labels) without touching our own
+	for key, value := range dbBackup.Spec.JobLabels {
+		if _, ok := job.Labels[key]; !ok {
+			job.Labels[key] = value
+		}
+	}
+
+	// Name the artifact from the user's template
+	if dbBackup.Spec.NamingTemplate != "" {
+		artifactName, err := renderArtifactName(dbBackup.Spec.NamingTemplate, artifactNameData{
+			Name:         dbBackup.Name,
+			Namespace:    dbBackup.Namespace,
+			DatabaseType: dbBackup.Spec.DatabaseType,
+			Date:         scheduledTime.UTC().Format("20060102"),
+			Timestamp:    scheduledTime.UTC().Format("20060102150405"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid naming template: %w", err)
+		}
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "ARTIFACT_NAME",
+			Value: artifactName,
+		})
+	}
+
+	// Keep artifact names predictable for catalogs keyed off the suffix
+	job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+		Name:  "FILE_EXTENSION",
+		Value: artifactExtension(dbBackup),
+	})
+
+	// Ask the image to object-lock the artifact. Cleanup must then skip
+	// artifacts that are still locked rather than erroring on them
+	if immutability := dbBackup.Spec.StorageDestination.Immutability; immutability != nil {
+		lockDays := int64(math.Ceil(immutability.RetainUntilDuration.Hours() / 24))
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env,
+			corev1.EnvVar{
+				Name:  "OBJECT_LOCK_DAYS",
+				Value: strconv.FormatInt(lockDays, 10),
+			},
+			corev1.EnvVar{
+				Name:  "CLEANUP_SKIP_LOCKED",
+				Value: "true",
+			},
+		)
+	}
+
+	// Have the image record the backup in the destination's manifest
+	if manifest := dbBackup.Spec.Manifest; manifest != nil {
+		manifestPath := manifest.Path
+		if manifestPath == "" {
+			manifestPath = dbbackupv1alpha1.DefaultManifestPath
+		}
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env,
+			corev1.EnvVar{
+				Name:  "UPDATE_MANIFEST",
+				Value: "true",
+			},
+			corev1.EnvVar{
+				Name:  "MANIFEST_PATH",
+				Value: path.Join(dbBackup.Spec.StorageDestination.Path, manifestPath),
+			},
+		)
+	}
+
+	// Stream the dump straight to object storage. Validation guarantees no
+	// PVC destination, so nothing is mounted to stage it on
+	if dbBackup.Spec.StreamToStorage != nil && *dbBackup.Spec.StreamToStorage {
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "STREAM",
+			Value: "true",
+		})
+	}
+
+	// Tag the artifact for cataloging. Map keys encode sorted, so the
+	// value is stable across reconciles
+	if len(dbBackup.Spec.BackupTags) > 0 {
+		tags, err := json.Marshal(dbBackup.Spec.BackupTags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode backup tags: %w", err)
+		}
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "BACKUP_TAGS",
+			Value: string(tags),
+		})
+	}
+
+	// Have cleanup apply the lifecycle rules instead of the retention window
+	if len(dbBackup.Spec.LifecycleRules) > 0 {
+		rules, err := json.Marshal(dbBackup.Spec.LifecycleRules)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode lifecycle rules: %w", err)
+		}
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "LIFECYCLE_RULES",
+			Value: string(rules),
+		})
+	}
+
+	// Throttle the upload
+	if dbBackup.Spec.BandwidthLimit != "" {
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "RATE_LIMIT",
+			Value: dbBackup.Spec.BandwidthLimit,
+		})
+	}
+
+	// Parallelize the dump within the pod
+	if dbBackup.Spec.Workers > 0 {
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "WORKERS",
+			Value: strconv.Itoa(int(dbBackup.Spec.Workers)),
+		})
+	}
+
+	// Upload the complete log next to the artifact
+	if dbBackup.Spec.StoreLogs != nil && *dbBackup.Spec.StoreLogs {
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "STORE_LOGS",
+			Value: "true",
+		})
+	}
+
+	// Back up only part of the database
+	if len(dbBackup.Spec.IncludeTables) > 0 {
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "INCLUDE_TABLES",
+			Value: strings.Join(dbBackup.Spec.IncludeTables, ","),
+		})
+	}
+	if len(dbBackup.Spec.ExcludeTables) > 0 {
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "EXCLUDE_TABLES",
+			Value: strings.Join(dbBackup.Spec.ExcludeTables, ","),
+		})
+	}
+
+	// Ask the image to fingerprint the artifact
+	if dbBackup.Spec.RecordChecksum {
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "RECORD_CHECKSUM",
+			Value: "sha256",
+		})
+	}
+
+	// Let the image skip the artifact when the content matches the last backup.
+	// Without a previous checksum the backup always runs
+	if dbBackup.Spec.SkipIfUnchanged {
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "SKIP_IF_UNCHANGED",
+			Value: "true",
+		})
+		if dbBackup.Status.LastBackupChecksum != "" {
+			job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+				Name:  "PREVIOUS_CHECKSUM",
+				Value: dbBackup.Status.LastBackupChecksum,
+			})
+		}
+	}
+
+	// Incremental backups need to know which base backup they build on
+	if backupType == "incremental" {
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "BASE_BACKUP_REF",
+			Value: dbBackup.Status.BaseBackupRef,
+		})
+	}
+
+	// Indexed completion gives each shard pod a stable index
+	if dbBackup.Spec.ShardedBackup {
+		completionMode := batchv1.IndexedCompletion
+		job.Spec.CompletionMode = &completionMode
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name: "JOB_COMPLETION_INDEX",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: fmt.Sprintf("metadata.annotations['%s']", batchv1.JobCompletionIndexAnnotation),
 				},
 			},
+		})
+	}
+
+	// Sharded backups tell each pod which shard it is. The completion index
+	// annotation is only populated in Indexed completion mode, which needs
+	// an explicit completion count, so without Completions there is one
+	// shard per parallel pod
+	if dbBackup.Spec.Parallelism != nil || dbBackup.Spec.Completions != nil {
+		shardCount := int32(1)
+		switch {
+		case dbBackup.Spec.Completions != nil:
+			shardCount = *dbBackup.Spec.Completions
+		case dbBackup.Spec.Parallelism != nil:
+			shardCount = *dbBackup.Spec.Parallelism
 		}
-		job.Spec.Template.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{
-			{
-				Name:      "backup-storage",
-				MountPath: "/backups",
+		completionMode := batchv1.IndexedCompletion
+		job.Spec.CompletionMode = &completionMode
+		job.Spec.Completions = &shardCount
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env,
+			corev1.EnvVar{
+				Name: "SHARD_INDEX",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: fmt.Sprintf("metadata.annotations['%s']", batchv1.JobCompletionIndexAnnotation),
+					},
+				},
 			},
+			corev1.EnvVar{
+				Name:  "SHARD_COUNT",
+				Value: strconv.Itoa(int(shardCount)),
+			},
+		)
+	}
+
+	addStorageVolumes(&job.Spec.Template.Spec, dbBackup)
+
+	// Authenticate to the database with a client certificate
+	if dbBackup.Spec.TLSConfig != nil {
+		addDatabaseTLS(&job.Spec.Template.Spec, dbBackup.Spec.TLSConfig)
+	}
+
+	// Connect to the database resolved from DatabaseRef
+	if dbBackup.Spec.DatabaseRef != nil {
+		if dbBackup.Status.ResolvedDatabase == nil {
+			return nil, fmt.Errorf("databaseRef has not been resolved yet")
+		}
+		addDatabaseConnection(&job.Spec.Template.Spec, dbBackup.Status.ResolvedDatabase)
+	}
+
+	// Hold the backup until storage is reachable
+	if dbBackup.Spec.WaitForStorage != nil && *dbBackup.Spec.WaitForStorage {
+		addStorageWait(&job.Spec.Template.Spec, dbBackup)
+	}
+
+	// Describe every destination to images that upload to several at once
+	if hasMultipleDestinations(dbBackup) {
+		if err := addDestinationsConfig(&job.Spec.Template.Spec, dbBackup); err != nil {
+			return nil, fmt.Errorf("failed to encode storage destinations: %w", err)
 		}
 	}
 
+	// Keep the artifact around for re-uploads to destinations that fail
+	if dbBackup.Spec.ReuploadFailedDestinations != nil {
+		addStagingVolume(&job.Spec.Template.Spec, dbBackup, job.Name)
+		addSupersededStagedArtifact(&job.Spec.Template.Spec, dbBackup)
+	}
+
+	// Let the image report progress while it runs
+	if dbBackup.Spec.ReportProgress {
+		addProgressFile(&job.Spec.Template.Spec)
+	}
+
+	// Append user-supplied volumes after the ones managed above
+	job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, dbBackup.Spec.ExtraVolumes...)
+	job.Spec.Template.Spec.Containers[0].VolumeMounts = append(
+		job.Spec.Template.Spec.Containers[0].VolumeMounts,
+		dbBackup.Spec.ExtraVolumeMounts...,
+	)
+
+	// Refuse to dump a corrupt database
+	if dbBackup.Spec.PreBackupIntegrityCheck != nil && *dbBackup.Spec.PreBackupIntegrityCheck {
+		addConsistencyCheck(&job.Spec.Template.Spec, dbBackup)
+	}
+
+	return job, nil
+}
+
+// Helper function to add the storage endpoint settings, volumes, credentials
+// and their mounts to the first container of a pod that reads or writes backups
+func addStorageVolumes(podSpec *corev1.PodSpec, dbBackup *dbbackupv1alpha1.DatabaseBackup) {
+	// Point the image at a non-default object storage endpoint
+	if endpoint := dbBackup.Spec.StorageDestination.Endpoint; endpoint != "" {
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, corev1.EnvVar{
+			Name:  "STORAGE_ENDPOINT",
+			Value: endpoint,
+		})
+	}
+	if pathStyle := dbBackup.Spec.StorageDestination.UsePathStyle; pathStyle != nil {
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, corev1.EnvVar{
+			Name:  "S3_PATH_STYLE",
+			Value: strconv.FormatBool(*pathStyle),
+		})
+	}
+
+	// Trust the private CA of the storage endpoint
+	if dbBackup.Spec.StorageCABundleSecret != "" {
+		addStorageCABundle(podSpec, dbBackup.Spec.StorageCABundleSecret)
+	}
+
+	// If using PVC for storage, add volume and volume mount
+	if dbBackup.Spec.StorageDestination.Type == "pvc" && dbBackup.Spec.StorageDestination.PVCName != "" {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "backup-storage",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: dbBackup.Spec.StorageDestination.PVCName,
+				},
+			},
+		})
+		podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      "backup-storage",
+			MountPath: "/backups",
+		})
+	}
+
 	// If storage credentials are provided, add secret volume
 	if dbBackup.Spec.StorageDestination.SecretName != "" {
-		job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, corev1.Volume{
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
 			Name: "storage-credentials",
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: dbBackup.Spec.StorageDestination.SecretName,
+					SecretName: storageSecretName(dbBackup),
 				},
 			},
 		})
-		job.Spec.Template.Spec.Containers[0].VolumeMounts = append(
-			job.Spec.Template.Spec.Containers[0].VolumeMounts,
+		podSpec.Containers[0].VolumeMounts = append(
+			podSpec.Containers[0].VolumeMounts,
 			corev1.VolumeMount{
 				Name:      "storage-credentials",
 				MountPath: "/credentials",
@@ -285,15 +2656,284 @@ func (r *DatabaseBackupReconciler) createBackupJob(ctx context.Context, dbBackup
 		)
 	}
 
-	if err := ctrl.SetControllerReference(dbBackup, job, r.Scheme); err != nil {
-		return nil, err
+	// If workload identity is used, mount a bound token instead of static credentials
+	if identity := dbBackup.Spec.StorageDestination.WorkloadIdentity; identity != nil {
+		expirationSeconds := dbbackupv1alpha1.DefaultTokenExpirationSeconds
+		if identity.ExpirationSeconds != nil {
+			expirationSeconds = *identity.ExpirationSeconds
+		}
+		podSpec.ServiceAccountName = identity.ServiceAccountName
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: "storage-token",
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					Sources: []corev1.VolumeProjection{
+						{
+							ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+								Audience:          identity.Audience,
+								ExpirationSeconds: &expirationSeconds,
+								Path:              "token",
+							},
+						},
+					},
+				},
+			},
+		})
+		container := &podSpec.Containers[0]
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "storage-token",
+			MountPath: "/var/run/secrets/storage",
+			ReadOnly:  true,
+		})
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "STORAGE_TOKEN_FILE",
+			Value: "/var/run/secrets/storage/token",
+		})
 	}
+}
 
-	if err := r.Create(ctx, job); err != nil {
-		return nil, err
+// artifactNameData holds the fields available to NamingTemplate
+type artifactNameData struct {
+	Name         string
+	Namespace    string
+	DatabaseType string
+	Date         string
+	Timestamp    string
+}
+
+// Helper function to render a NamingTemplate into an artifact name
+func renderArtifactName(namingTemplate string, data artifactNameData) (string, error) {
+	tmpl, err := template.New("artifact").Option("missingkey=error").Parse(namingTemplate)
+	if err != nil {
+		return "", err
+	}
+	var name strings.Builder
+	if err := tmpl.Execute(&name, data); err != nil {
+		return "", err
 	}
+	if name.Len() == 0 {
+		return "", fmt.Errorf("template renders an empty name")
+	}
+	return name.String(), nil
+}
 
-	return job, nil
+// Helper function to get the suffix of artifact names, from FileExtension
+// or else from the database type and encryption
+func artifactExtension(dbBackup *dbbackupv1alpha1.DatabaseBackup) string {
+	if dbBackup.Spec.FileExtension != "" {
+		return dbBackup.Spec.FileExtension
+	}
+
+	var extension string
+	switch dbBackup.Spec.DatabaseType {
+	case "postgres", "mysql":
+		extension = ".sql.gz"
+	case "mongodb":
+		extension = ".archive.gz"
+	case "sqlite":
+		extension = ".db.gz"
+	default:
+		extension = ".gz"
+	}
+	if dbBackup.Spec.Encryption != nil {
+		extension += ".enc"
+	}
+	return extension
+}
+
+// Helper function to get the deterministic Job name for a scheduled slot.
+// Cron schedules have minute granularity, so the slot is truncated to the minute.
+func backupJobName(dbBackup *dbbackupv1alpha1.DatabaseBackup, scheduledTime time.Time) string {
+	return fmt.Sprintf("%s-%s", dbBackup.Name, scheduledTime.UTC().Format("200601021504"))
+}
+
+// Helper function to check if the storage secret lives in another namespace
+func isCrossNamespaceSecret(dbBackup *dbbackupv1alpha1.DatabaseBackup) bool {
+	dest := dbBackup.Spec.StorageDestination
+	return dest.SecretName != "" && dest.SecretNamespace != "" && dest.SecretNamespace != dbBackup.Namespace
+}
+
+// Helper function to get the name of the storage secret mounted into backup pods
+func storageSecretName(dbBackup *dbbackupv1alpha1.DatabaseBackup) string {
+	if isCrossNamespaceSecret(dbBackup) {
+		return fmt.Sprintf("%s-storage-credentials", dbBackup.Name)
+	}
+	return dbBackup.Spec.StorageDestination.SecretName
+}
+
+// Helper function to get a copy of a DatabaseBackup without storage
+// credentials pointed at the controller's default storage secret. Only the
+// copy is changed, so the default never ends up in a status or spec update
+// of the DatabaseBackup itself. Pass it to whatever builds pods or Secrets
+func (r *DatabaseBackupReconciler) withStorageDefaults(dbBackup *dbbackupv1alpha1.DatabaseBackup) *dbbackupv1alpha1.DatabaseBackup {
+	dest := dbBackup.Spec.StorageDestination
+	if r.DefaultStorageSecret.Name == "" || dest.SecretName != "" || dest.WorkloadIdentity != nil || dest.Type == "pvc" {
+		return dbBackup
+	}
+	defaulted := dbBackup.DeepCopy()
+	defaulted.Spec.StorageDestination.SecretName = r.DefaultStorageSecret.Name
+	defaulted.Spec.StorageDestination.SecretNamespace = r.DefaultStorageSecret.Namespace
+	return defaulted
+}
+
+// Helper function to copy a cross-namespace storage secret into the
+// DatabaseBackup's namespace, updating the copy whenever the source changes
+func (r *DatabaseBackupReconciler) syncStorageSecret(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) error {
+	if !isCrossNamespaceSecret(dbBackup) {
+		return nil
+	}
+
+	var source corev1.Secret
+	sourceName := types.NamespacedName{
+		Name:      dbBackup.Spec.StorageDestination.SecretName,
+		Namespace: dbBackup.Spec.StorageDestination.SecretNamespace,
+	}
+	if err := r.apiReader().Get(ctx, sourceName, &source); err != nil {
+		return fmt.Errorf("failed to get secret %s: %w", sourceName, err)
+	}
+
+	return r.applySecretCopy(ctx, dbBackup, storageSecretName(dbBackup), &source)
+}
+
+// Helper function to get the name of the image pull secret copy in a DatabaseBackup's namespace
+func imagePullSecretCopyName(dbBackup *dbbackupv1alpha1.DatabaseBackup) string {
+	return fmt.Sprintf("%s-image-pull", dbBackup.Name)
+}
+
+// Helper function to copy the controller's image pull secret into the
+// DatabaseBackup's namespace, updating the copy whenever the source changes
+func (r *DatabaseBackupReconciler) syncImagePullSecret(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) error {
+	if r.ImagePullSecret.Name == "" {
+		return nil
+	}
+
+	var source corev1.Secret
+	if err := r.apiReader().Get(ctx, r.ImagePullSecret, &source); err != nil {
+		return fmt.Errorf("failed to get secret %s: %w", r.ImagePullSecret, err)
+	}
+
+	return r.applySecretCopy(ctx, dbBackup, imagePullSecretCopyName(dbBackup), &source)
+}
+
+// Helper function to create or update a Secret owned by the DatabaseBackup
+// holding the type and data of source. A Secret of that name the
+// DatabaseBackup doesn't own is never adopted or overwritten. The copy is read
+// past the cache, which misses copies written before they were labelled
+func (r *DatabaseBackupReconciler) applySecretCopy(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, name string, source *corev1.Secret) error {
+	secretCopy := &corev1.Secret{}
+	err := r.apiReader().Get(ctx, types.NamespacedName{Name: name, Namespace: dbBackup.Namespace}, secretCopy)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	if exists && !metav1.IsControlledBy(secretCopy, dbBackup) {
+		r.Recorder.Eventf(dbBackup, corev1.EventTypeWarning, "SecretConflict", "Secret %s exists and isn't owned by this DatabaseBackup, not overwriting it", name)
+		return fmt.Errorf("secret %s exists and isn't owned by this DatabaseBackup", name)
+	}
+	if !exists {
+		secretCopy.ObjectMeta = metav1.ObjectMeta{Name: name, Namespace: dbBackup.Namespace}
+	}
+
+	before := secretCopy.DeepCopy()
+	if secretCopy.Labels == nil {
+		secretCopy.Labels = map[string]string{}
+	}
+	secretCopy.Labels[backupNameLabel] = dbBackup.Name
+	secretCopy.Labels[watchedSecretLabel] = "true"
+	secretCopy.Type = source.Type
+	secretCopy.Data = source.Data
+	if err := ctrl.SetControllerReference(dbBackup, secretCopy, r.Scheme); err != nil {
+		return err
+	}
+
+	if !exists {
+		return r.Create(ctx, secretCopy)
+	}
+	if equality.Semantic.DeepEqual(before, secretCopy) {
+		return nil
+	}
+	return r.Update(ctx, secretCopy)
+}
+
+// Helper function to reference the copied image pull secret from a backup pod
+func (r *DatabaseBackupReconciler) addImagePullSecret(podSpec *corev1.PodSpec, dbBackup *dbbackupv1alpha1.DatabaseBackup) {
+	if r.ImagePullSecret.Name == "" {
+		return
+	}
+	podSpec.ImagePullSecrets = append(podSpec.ImagePullSecrets, corev1.LocalObjectReference{
+		Name: imagePullSecretCopyName(dbBackup),
+	})
+}
+
+// Helper function to map a changed Secret to the DatabaseBackups that copy it
+func (r *DatabaseBackupReconciler) findBackupsForSecret(obj client.Object) []reconcile.Request {
+	var backups dbbackupv1alpha1.DatabaseBackupList
+	if err := r.List(context.Background(), &backups, r.shardSelector()); err != nil {
+		return nil
+	}
+
+	// Every DatabaseBackup copies the image pull secret, key rotations must
+	// reach the CronJob template of NativeCronJob backups, and a fixed TLS
+	// or storage CA secret should clear the error it caused
+	isImagePullSecret := r.ImagePullSecret.Name != "" &&
+		obj.GetName() == r.ImagePullSecret.Name && obj.GetNamespace() == r.ImagePullSecret.Namespace
+
+	var requests []reconcile.Request
+	for _, dbBackup := range backups.Items {
+		defaulted := r.withStorageDefaults(&dbBackup)
+		dest := defaulted.Spec.StorageDestination
+		isEncryptionKey := dbBackup.Spec.Encryption != nil && isNativeCronJobMode(&dbBackup) &&
+			dbBackup.Spec.Encryption.SecretName == obj.GetName() && dbBackup.Namespace == obj.GetNamespace()
+		isTLSSecret := dbBackup.Spec.TLSConfig != nil &&
+			dbBackup.Spec.TLSConfig.SecretName == obj.GetName() && dbBackup.Namespace == obj.GetNamespace()
+		isStorageCABundle := dbBackup.Spec.StorageCABundleSecret == obj.GetName() && dbBackup.Namespace == obj.GetNamespace()
+		if isImagePullSecret || isEncryptionKey || isTLSSecret || isStorageCABundle || (isCrossNamespaceSecret(defaulted) && dest.SecretName == obj.GetName() && dest.SecretNamespace == obj.GetNamespace()) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      dbBackup.Name,
+				Namespace: dbBackup.Namespace,
+			}})
+		}
+	}
+	return requests
+}
+
+// Helper function to check if a DatabaseBackup belongs to this controller's shard
+func (r *DatabaseBackupReconciler) ownsBackup(obj client.Object) bool {
+	return r.Selector == nil || r.Selector.Matches(labels.Set(obj.GetLabels()))
+}
+
+// Helper function to filter events to this controller's shard. An update
+// passes when either side belongs to it, so both the shard a DatabaseBackup
+// moves out of and the one it moves into see the label change
+func (r *DatabaseBackupReconciler) shardPredicate() predicate.Funcs {
+	funcs := predicate.NewPredicateFuncs(r.ownsBackup)
+	funcs.UpdateFunc = func(e event.UpdateEvent) bool {
+		return r.ownsBackup(e.ObjectOld) || r.ownsBackup(e.ObjectNew)
+	}
+	return funcs
+}
+
+// Helper function to limit DatabaseBackup lists to this controller's shard
+func (r *DatabaseBackupReconciler) shardSelector() client.MatchingLabelsSelector {
+	if r.Selector == nil {
+		return client.MatchingLabelsSelector{Selector: labels.Everything()}
+	}
+	return client.MatchingLabelsSelector{Selector: r.Selector}
+}
+
+// Status returns a status writer that traces each write
+func (r *DatabaseBackupReconciler) Status() client.StatusWriter {
+	return tracedStatusWriter{r.Client.Status()}
+}
+
+// Helper function to build an env var filled in from a pod field
+func downwardAPIEnv(name, fieldPath string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: fieldPath},
+		},
+	}
 }
 
 // Helper function to get the appropriate backup image based on DB type
@@ -305,6 +2945,8 @@ func getBackupImage(dbType string) string {
 		return "ghcr.io/example/mysql-backup:latest"
 	case "mongodb":
 		return "ghcr.io/example/mongodb-backup:latest"
+	case "sqlite":
+		return "ghcr.io/example/sqlite-backup:latest"
 	default:
 		return "ghcr.io/example/generic-backup:latest"
 	}
@@ -312,8 +2954,44 @@ func getBackupImage(dbType string) string {
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *DatabaseBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.execEvents = make(chan event.GenericEvent, 64)
+
+	// Job names are derived from the slot, so parallel reconciles of
+	// different objects never race on the same Job
+	maxConcurrentReconciles := r.MaxConcurrentReconciles
+	if maxConcurrentReconciles == 0 {
+		maxConcurrentReconciles = DefaultMaxConcurrentReconciles
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&dbbackupv1alpha1.DatabaseBackup{}).
+		// Skip the reconciles our own status writes would trigger. Annotation
+		// changes still count since they carry user requests (e.g. resume),
+		// and label changes since they move a DatabaseBackup between shards
+		For(&dbbackupv1alpha1.DatabaseBackup{}, builder.WithPredicates(
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}, predicate.LabelChangedPredicate{}),
+			r.shardPredicate(),
+		)).
 		Owns(&batchv1.Job{}).
+		Owns(&batchv1.CronJob{}).
+		Owns(&corev1.Secret{}).
+		// Only Secrets with watchedSecretLabel are cached, see SecretCacheSelector
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.findBackupsForSecret),
+		).
+		Watches(
+			&source.Kind{Type: &dbbackupv1alpha1.DatabaseBackup{}},
+			handler.EnqueueRequestsFromMapFunc(r.findDependentBackups),
+		).
+		Watches(
+			&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.findBackupsForConfigMap),
+		).
+		// Finished exec backups report back through this channel
+		Watches(
+			&source.Channel{Source: r.execEvents},
+			&handler.EnqueueRequestForObject{},
+		).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
 		Complete(r)
 }
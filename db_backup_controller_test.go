@@ -0,0 +1,1789 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+func TestCheckRetentionRisk(t *testing.T) {
+	now := time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC)
+	lastSuccess := func(age time.Duration) *metav1.Time {
+		ts := metav1.NewTime(now.Add(-age))
+		return &ts
+	}
+
+	tests := []struct {
+		name        string
+		spec        dbbackupv1alpha1.DatabaseBackupSpec
+		status      dbbackupv1alpha1.DatabaseBackupStatus
+		wantChanged bool
+		wantRecheck time.Duration
+		// wantStatus is empty when no condition is expected
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:        "no successful backup",
+			spec:        dbbackupv1alpha1.DatabaseBackupSpec{BackupRetention: 100},
+			wantChanged: false,
+		},
+		{
+			name:        "recent backup",
+			spec:        dbbackupv1alpha1.DatabaseBackupSpec{BackupRetention: 100},
+			status:      dbbackupv1alpha1.DatabaseBackupStatus{LastSuccessfulBackup: lastSuccess(10 * time.Hour)},
+			wantChanged: true,
+			wantRecheck: 80 * time.Hour,
+			wantStatus:  metav1.ConditionFalse,
+			wantReason:  "RecentBackup",
+		},
+		{
+			name:        "backup expiring",
+			spec:        dbbackupv1alpha1.DatabaseBackupSpec{BackupRetention: 100},
+			status:      dbbackupv1alpha1.DatabaseBackupStatus{LastSuccessfulBackup: lastSuccess(95 * time.Hour)},
+			wantChanged: true,
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  "LastBackupExpiring",
+		},
+		{
+			name:        "backup expired",
+			spec:        dbbackupv1alpha1.DatabaseBackupSpec{BackupRetention: 100},
+			status:      dbbackupv1alpha1.DatabaseBackupStatus{LastSuccessfulBackup: lastSuccess(120 * time.Hour)},
+			wantChanged: true,
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  "LastBackupExpired",
+		},
+		{
+			name: "lifecycle rules delete",
+			spec: dbbackupv1alpha1.DatabaseBackupSpec{
+				LifecycleRules: []dbbackupv1alpha1.LifecycleRule{
+					{AfterHours: 24, Action: dbbackupv1alpha1.LifecycleTransition, StorageClass: "GLACIER"},
+					{AfterHours: 100, Action: dbbackupv1alpha1.LifecycleDelete},
+				},
+			},
+			status:      dbbackupv1alpha1.DatabaseBackupStatus{LastSuccessfulBackup: lastSuccess(95 * time.Hour)},
+			wantChanged: true,
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  "LastBackupExpiring",
+		},
+		{
+			name: "backups kept indefinitely clear a flagged risk",
+			spec: dbbackupv1alpha1.DatabaseBackupSpec{
+				LifecycleRules: []dbbackupv1alpha1.LifecycleRule{
+					{AfterHours: 24, Action: dbbackupv1alpha1.LifecycleTransition, StorageClass: "GLACIER"},
+				},
+			},
+			status: dbbackupv1alpha1.DatabaseBackupStatus{
+				LastSuccessfulBackup: lastSuccess(500 * time.Hour),
+				Conditions: []metav1.Condition{{
+					Type:   dbbackupv1alpha1.ConditionRetentionRisk,
+					Status: metav1.ConditionTrue,
+					Reason: "LastBackupExpired",
+				}},
+			},
+			wantChanged: true,
+			wantStatus:  metav1.ConditionFalse,
+			wantReason:  "NoRetention",
+		},
+		{
+			name: "unchanged condition",
+			spec: dbbackupv1alpha1.DatabaseBackupSpec{BackupRetention: 100},
+			status: dbbackupv1alpha1.DatabaseBackupStatus{
+				LastSuccessfulBackup: lastSuccess(96 * time.Hour),
+				Conditions: []metav1.Condition{{
+					Type:   dbbackupv1alpha1.ConditionRetentionRisk,
+					Status: metav1.ConditionTrue,
+					Reason: "LastBackupExpiring",
+				}},
+			},
+			wantChanged: false,
+			wantStatus:  metav1.ConditionTrue,
+			wantReason:  "LastBackupExpiring",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbBackup := &dbbackupv1alpha1.DatabaseBackup{Spec: tt.spec, Status: tt.status}
+			changed, recheckIn := checkRetentionRisk(dbBackup, now)
+			if changed != tt.wantChanged {
+				t.Errorf("changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if recheckIn != tt.wantRecheck {
+				t.Errorf("recheckIn = %s, want %s", recheckIn, tt.wantRecheck)
+			}
+
+			condition := meta.FindStatusCondition(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionRetentionRisk)
+			if tt.wantStatus == "" {
+				if condition != nil {
+					t.Errorf("unexpected condition %+v", condition)
+				}
+				return
+			}
+			if condition == nil {
+				t.Fatalf("no %s condition", dbbackupv1alpha1.ConditionRetentionRisk)
+			}
+			if condition.Status != tt.wantStatus || condition.Reason != tt.wantReason {
+				t.Errorf("condition = %s/%s, want %s/%s", condition.Status, condition.Reason, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
+func waitingBackup(name string, priority int32, mutate func(*dbbackupv1alpha1.DatabaseBackup)) *dbbackupv1alpha1.DatabaseBackup {
+	due := metav1.NewTime(time.Now().Add(-time.Minute))
+	dbBackup := &dbbackupv1alpha1.DatabaseBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       dbbackupv1alpha1.DatabaseBackupSpec{Schedule: "0 * * * *", Priority: priority},
+		Status: dbbackupv1alpha1.DatabaseBackupStatus{
+			LastBackupStatus:    "WaitingForSlot",
+			NextScheduledBackup: &due,
+		},
+	}
+	if mutate != nil {
+		mutate(dbBackup)
+	}
+	return dbBackup
+}
+
+func TestIsWaitingForSlot(t *testing.T) {
+	int64Ptr := func(i int64) *int64 { return &i }
+
+	tests := []struct {
+		name   string
+		mutate func(*dbbackupv1alpha1.DatabaseBackup)
+		want   bool
+	}{
+		{name: "due and waiting", want: true},
+		{
+			name:   "not waiting",
+			mutate: func(b *dbbackupv1alpha1.DatabaseBackup) { b.Status.LastBackupStatus = "Running" },
+			want:   false,
+		},
+		{
+			name:   "already running",
+			mutate: func(b *dbbackupv1alpha1.DatabaseBackup) { b.Status.ActiveBackupJob = "backup-1" },
+			want:   false,
+		},
+		{
+			name: "being deleted",
+			mutate: func(b *dbbackupv1alpha1.DatabaseBackup) {
+				deleted := metav1.Now()
+				b.DeletionTimestamp = &deleted
+			},
+			want: false,
+		},
+		{
+			name: "auto-suspended",
+			mutate: func(b *dbbackupv1alpha1.DatabaseBackup) {
+				meta.SetStatusCondition(&b.Status.Conditions, metav1.Condition{
+					Type:   dbbackupv1alpha1.ConditionAutoSuspended,
+					Status: metav1.ConditionTrue,
+					Reason: "TooManyFailures",
+				})
+			},
+			want: false,
+		},
+		{
+			name: "next run ahead",
+			mutate: func(b *dbbackupv1alpha1.DatabaseBackup) {
+				next := metav1.NewTime(time.Now().Add(time.Hour))
+				b.Status.NextScheduledBackup = &next
+			},
+			want: false,
+		},
+		{
+			name: "manual backup pending",
+			mutate: func(b *dbbackupv1alpha1.DatabaseBackup) {
+				b.Status.NextScheduledBackup = nil
+				b.Status.ManualBackupPending = true
+			},
+			want: true,
+		},
+		{
+			name:   "past starting deadline",
+			mutate: func(b *dbbackupv1alpha1.DatabaseBackup) { b.Spec.StartingDeadlineSeconds = int64Ptr(30) },
+			want:   false,
+		},
+		{
+			name:   "within starting deadline",
+			mutate: func(b *dbbackupv1alpha1.DatabaseBackup) { b.Spec.StartingDeadlineSeconds = int64Ptr(300) },
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isWaitingForSlot(waitingBackup("db", 0, tt.mutate), time.Now()); got != tt.want {
+				t.Errorf("isWaitingForSlot = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountHigherPriorityWaiting(t *testing.T) {
+	running := func(b *dbbackupv1alpha1.DatabaseBackup) { b.Status.ActiveBackupJob = "backup-1" }
+
+	tests := []struct {
+		name     string
+		priority int32
+		others   []runtime.Object
+		selector labels.Selector
+		want     int
+	}{
+		{name: "nothing else waiting", priority: 5, want: 0},
+		{
+			name:     "higher priority waiting",
+			priority: 5,
+			others: []runtime.Object{
+				waitingBackup("high-a", 10, nil),
+				waitingBackup("high-b", 6, nil),
+			},
+			want: 2,
+		},
+		{
+			name:     "same or lower priority waiting",
+			priority: 5,
+			others: []runtime.Object{
+				waitingBackup("same", 5, nil),
+				waitingBackup("low", 1, nil),
+			},
+			want: 0,
+		},
+		{
+			name:     "higher priority no longer waiting",
+			priority: 5,
+			others: []runtime.Object{
+				waitingBackup("high-running", 10, running),
+				waitingBackup("high-waiting", 10, nil),
+			},
+			want: 1,
+		},
+		{
+			name:     "higher priority in another shard",
+			priority: 5,
+			others: []runtime.Object{
+				waitingBackup("high-here", 10, func(b *dbbackupv1alpha1.DatabaseBackup) { b.Labels = map[string]string{"shard": "a"} }),
+				waitingBackup("high-elsewhere", 10, func(b *dbbackupv1alpha1.DatabaseBackup) { b.Labels = map[string]string{"shard": "b"} }),
+			},
+			selector: labels.SelectorFromSet(labels.Set{"shard": "a"}),
+			want:     1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler(t, tt.others...)
+			r.Selector = tt.selector
+			got, err := r.countHigherPriorityWaiting(context.Background(), waitingBackup("db", tt.priority, nil))
+			if err != nil {
+				t.Fatalf("countHigherPriorityWaiting: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("countHigherPriorityWaiting = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// statusCountingClient counts the status updates sent through it
+type statusCountingClient struct {
+	client.Client
+	updates int
+}
+
+func (c *statusCountingClient) Status() client.StatusWriter {
+	return &countingStatusWriter{StatusWriter: c.Client.Status(), client: c}
+}
+
+type countingStatusWriter struct {
+	client.StatusWriter
+	client *statusCountingClient
+}
+
+func (w *countingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	w.client.updates++
+	return w.StatusWriter.Update(ctx, obj, opts...)
+}
+
+func TestReconcileSkipsUnchangedStatus(t *testing.T) {
+	dbBackup := &dbbackupv1alpha1.DatabaseBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+		Spec: dbbackupv1alpha1.DatabaseBackupSpec{
+			DatabaseType: "postgres",
+			Schedule:     "0 2 * * *",
+			StorageDestination: dbbackupv1alpha1.StorageDestinationSpec{
+				Type:   "s3",
+				Bucket: "backups",
+			},
+		},
+	}
+	r := newTestReconciler(t, dbBackup)
+	counting := &statusCountingClient{Client: r.Client}
+	r.Client = counting
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "default"}}
+
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("first reconcile: %v", err)
+	}
+	if counting.updates != 1 {
+		t.Fatalf("first reconcile sent %d status updates, want 1", counting.updates)
+	}
+
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("second reconcile: %v", err)
+	}
+	if counting.updates != 1 {
+		t.Errorf("second reconcile sent %d status updates, want none", counting.updates-1)
+	}
+}
+
+func TestReserveBackupSlot(t *testing.T) {
+	r := newTestReconciler(t)
+	r.MaxConcurrentBackups = 1
+	ctx := context.Background()
+
+	reserve := func(name string) bool {
+		t.Helper()
+		reserved, err := r.reserveBackupSlot(ctx, waitingBackup(name, 0, nil))
+		if err != nil {
+			t.Fatalf("reserveBackupSlot: %v", err)
+		}
+		return reserved
+	}
+	if !reserve("a") {
+		t.Fatal("first reservation refused with a slot free")
+	}
+	// The reservation holds the slot before any Job exists
+	if reserve("b") {
+		t.Fatal("second reservation granted past the limit")
+	}
+	r.releaseBackupSlot()
+	if !reserve("b") {
+		t.Fatal("reservation refused after the slot was released")
+	}
+}
+
+func TestConcurrentReconcilesAtLimit(t *testing.T) {
+	due := func(b *dbbackupv1alpha1.DatabaseBackup) {
+		b.Spec.DatabaseType = "postgres"
+		b.Spec.StorageDestination = dbbackupv1alpha1.StorageDestinationSpec{Type: "s3", Bucket: "backups"}
+		b.Status.LastBackupStatus = "Pending"
+	}
+	r := newTestReconciler(t, waitingBackup("a", 0, due), waitingBackup("b", 0, due))
+	r.MaxConcurrentBackups = 1
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"a", "b"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}}
+			if _, err := r.Reconcile(context.Background(), req); err != nil {
+				t.Errorf("reconcile %s: %v", name, err)
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	var jobs batchv1.JobList
+	if err := r.List(context.Background(), &jobs, client.HasLabels{backupNameLabel}); err != nil {
+		t.Fatalf("listing jobs: %v", err)
+	}
+	if len(jobs.Items) != 1 {
+		t.Fatalf("started %d backup jobs, want 1", len(jobs.Items))
+	}
+
+	var waiting int
+	for _, name := range []string{"a", "b"} {
+		var dbBackup dbbackupv1alpha1.DatabaseBackup
+		if err := r.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, &dbBackup); err != nil {
+			t.Fatalf("getting %s: %v", name, err)
+		}
+		if dbBackup.Status.LastBackupStatus == "WaitingForSlot" {
+			waiting++
+		}
+	}
+	if waiting != 1 {
+		t.Errorf("%d backups waiting for a slot, want 1", waiting)
+	}
+}
+
+func TestSkipMissedRun(t *testing.T) {
+	int64Ptr := func(i int64) *int64 { return &i }
+	hourly := mustParseSchedule(t, "0 * * * *")
+	now := time.Date(2026, time.January, 1, 12, 10, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		deadline *int64
+		missed   time.Time
+		want     bool
+		wantNext time.Time
+	}{
+		{name: "no deadline", missed: now.Add(-10 * time.Minute), want: false, wantNext: now.Add(-10 * time.Minute)},
+		{name: "within the deadline", deadline: int64Ptr(900), missed: now.Add(-10 * time.Minute), want: false, wantNext: now.Add(-10 * time.Minute)},
+		{name: "beyond the deadline", deadline: int64Ptr(300), missed: now.Add(-10 * time.Minute), want: true, wantNext: now.Add(50 * time.Minute)},
+		{name: "not yet due", deadline: int64Ptr(300), missed: now.Add(50 * time.Minute), want: false, wantNext: now.Add(50 * time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missed := metav1.NewTime(tt.missed)
+			dbBackup := &dbbackupv1alpha1.DatabaseBackup{
+				Spec:   dbbackupv1alpha1.DatabaseBackupSpec{StartingDeadlineSeconds: tt.deadline},
+				Status: dbbackupv1alpha1.DatabaseBackupStatus{NextScheduledBackup: &missed},
+			}
+			if got := skipMissedRun(hourly, dbBackup, now); got != tt.want {
+				t.Errorf("skipMissedRun = %v, want %v", got, tt.want)
+			}
+			if next := dbBackup.Status.NextScheduledBackup.Time; !next.Equal(tt.wantNext) {
+				t.Errorf("NextScheduledBackup = %s, want %s", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestMissedScheduleDeadline(t *testing.T) {
+	int64Ptr := func(i int64) *int64 { return &i }
+
+	tests := []struct {
+		name       string
+		deadline   *int64
+		wantJob    bool
+		wantEvents int
+	}{
+		{name: "no deadline", wantJob: true},
+		{name: "missed within the deadline", deadline: int64Ptr(300), wantJob: true},
+		{name: "missed beyond the deadline", deadline: int64Ptr(30), wantEvents: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbBackup := waitingBackup("db", 0, func(b *dbbackupv1alpha1.DatabaseBackup) {
+				b.Spec.DatabaseType = "postgres"
+				b.Spec.StorageDestination = dbbackupv1alpha1.StorageDestinationSpec{Type: "s3", Bucket: "backups"}
+				b.Spec.StartingDeadlineSeconds = tt.deadline
+				b.Status.LastBackupStatus = "Pending"
+			})
+			r := newTestReconciler(t, dbBackup)
+			recorder := r.Recorder.(*record.FakeRecorder)
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "default"}}
+
+			// A second reconcile must not report the same skip again
+			for i := 0; i < 2; i++ {
+				if _, err := r.Reconcile(context.Background(), req); err != nil {
+					t.Fatalf("reconcile: %v", err)
+				}
+			}
+
+			var jobs batchv1.JobList
+			if err := r.List(context.Background(), &jobs, client.HasLabels{backupNameLabel}); err != nil {
+				t.Fatalf("listing jobs: %v", err)
+			}
+			if got := len(jobs.Items) > 0; got != tt.wantJob {
+				t.Errorf("job started = %v, want %v", got, tt.wantJob)
+			}
+
+			missedEvents := 0
+			for len(recorder.Events) > 0 {
+				if strings.Contains(<-recorder.Events, "MissedSchedule") {
+					missedEvents++
+				}
+			}
+			if missedEvents != tt.wantEvents {
+				t.Errorf("%d MissedSchedule events, want %d", missedEvents, tt.wantEvents)
+			}
+		})
+	}
+}
+
+// Helper function to build a running backup Job controlled by dbBackup
+func ownedJob(name string, uid types.UID, dbBackup *dbbackupv1alpha1.DatabaseBackup) *batchv1.Job {
+	controller := true
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: dbBackup.Namespace,
+			UID:       uid,
+			Labels:    map[string]string{backupNameLabel: dbBackup.Name},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: dbbackupv1alpha1.GroupVersion.String(),
+				Kind:       "DatabaseBackup",
+				Name:       dbBackup.Name,
+				UID:        dbBackup.UID,
+				Controller: &controller,
+			}},
+		},
+	}
+}
+
+func TestReconcileAdoptsOrphanedJob(t *testing.T) {
+	// Due, so a Job would be created if the orphan weren't adopted
+	dbBackup := waitingBackup("db", 0, func(b *dbbackupv1alpha1.DatabaseBackup) {
+		b.UID = "backup-uid"
+		b.Spec.DatabaseType = "postgres"
+		b.Spec.StorageDestination = dbbackupv1alpha1.StorageDestinationSpec{Type: "s3", Bucket: "backups"}
+		b.Status.LastBackupStatus = "Pending"
+	})
+	r := newTestReconciler(t, dbBackup, ownedJob("db-orphan", "orphan-uid", dbBackup))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "default"}}
+
+	for i := 0; i < 2; i++ {
+		if _, err := r.Reconcile(context.Background(), req); err != nil {
+			t.Fatalf("reconcile: %v", err)
+		}
+	}
+
+	var got dbbackupv1alpha1.DatabaseBackup
+	if err := r.Get(context.Background(), req.NamespacedName, &got); err != nil {
+		t.Fatalf("getting DatabaseBackup: %v", err)
+	}
+	if got.Status.ActiveBackupJob != "db-orphan" || got.Status.ActiveBackupJobUID != "orphan-uid" {
+		t.Errorf("active job = %s (%s), want the orphan db-orphan (orphan-uid)", got.Status.ActiveBackupJob, got.Status.ActiveBackupJobUID)
+	}
+	if got.Status.LastBackupStatus != "Running" {
+		t.Errorf("LastBackupStatus = %q, want Running", got.Status.LastBackupStatus)
+	}
+
+	var jobs batchv1.JobList
+	if err := r.List(context.Background(), &jobs, client.HasLabels{backupNameLabel}); err != nil {
+		t.Fatalf("listing jobs: %v", err)
+	}
+	if len(jobs.Items) != 1 {
+		t.Errorf("%d backup jobs, want only the adopted one", len(jobs.Items))
+	}
+}
+
+func TestCreateBackupJobIdempotent(t *testing.T) {
+	slot := time.Date(2026, time.January, 1, 2, 0, 0, 0, time.UTC)
+	newBackup := func(uid types.UID) *dbbackupv1alpha1.DatabaseBackup {
+		return waitingBackup("db", 0, func(b *dbbackupv1alpha1.DatabaseBackup) {
+			b.UID = uid
+			b.Spec.DatabaseType = "postgres"
+			b.Spec.StorageDestination = dbbackupv1alpha1.StorageDestinationSpec{Type: "s3", Bucket: "backups"}
+		})
+	}
+	ctx := context.Background()
+
+	t.Run("same slot twice", func(t *testing.T) {
+		dbBackup := newBackup("backup-uid")
+		r := newTestReconciler(t, dbBackup)
+		first, err := r.createBackupJob(ctx, dbBackup, slot, false)
+		if err != nil {
+			t.Fatalf("first createBackupJob: %v", err)
+		}
+		// The second create hits AlreadyExists and adopts the first Job
+		second, err := r.createBackupJob(ctx, dbBackup, slot, false)
+		if err != nil {
+			t.Fatalf("second createBackupJob: %v", err)
+		}
+		if second.Name != first.Name || second.ResourceVersion != first.ResourceVersion {
+			t.Errorf("second create returned %s@%s, want the existing %s@%s", second.Name, second.ResourceVersion, first.Name, first.ResourceVersion)
+		}
+
+		var jobs batchv1.JobList
+		if err := r.List(ctx, &jobs, client.HasLabels{backupNameLabel}); err != nil {
+			t.Fatalf("listing jobs: %v", err)
+		}
+		if len(jobs.Items) != 1 {
+			t.Errorf("%d backup jobs, want 1", len(jobs.Items))
+		}
+	})
+
+	t.Run("same slot reconciled twice", func(t *testing.T) {
+		dbBackup := newBackup("backup-uid")
+		dbBackup.Status.LastBackupStatus = "Pending"
+		r := newTestReconciler(t, dbBackup.DeepCopy())
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "default"}}
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("first reconcile: %v", err)
+		}
+
+		// Lose the status write, so the second reconcile sees the slot as due
+		var stored dbbackupv1alpha1.DatabaseBackup
+		if err := r.Get(ctx, req.NamespacedName, &stored); err != nil {
+			t.Fatalf("getting DatabaseBackup: %v", err)
+		}
+		stored.Status = dbBackup.Status
+		if err := r.Client.Status().Update(ctx, &stored); err != nil {
+			t.Fatalf("resetting status: %v", err)
+		}
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("second reconcile: %v", err)
+		}
+
+		var jobs batchv1.JobList
+		if err := r.List(ctx, &jobs, client.HasLabels{backupNameLabel}); err != nil {
+			t.Fatalf("listing jobs: %v", err)
+		}
+		if len(jobs.Items) != 1 {
+			t.Fatalf("%d backup jobs, want 1", len(jobs.Items))
+		}
+		if err := r.Get(ctx, req.NamespacedName, &stored); err != nil {
+			t.Fatalf("getting DatabaseBackup: %v", err)
+		}
+		if stored.Status.ActiveBackupJob != jobs.Items[0].Name {
+			t.Errorf("ActiveBackupJob = %q, want %q", stored.Status.ActiveBackupJob, jobs.Items[0].Name)
+		}
+	})
+
+	t.Run("slot taken by another owner", func(t *testing.T) {
+		previous := newBackup("previous-uid")
+		r := newTestReconciler(t, previous)
+		if _, err := r.createBackupJob(ctx, previous, slot, false); err != nil {
+			t.Fatalf("createBackupJob: %v", err)
+		}
+		// A recreated DatabaseBackup of the same name must not adopt it
+		if _, err := r.createBackupJob(ctx, newBackup("backup-uid"), slot, false); err == nil {
+			t.Error("createBackupJob adopted a Job owned by another DatabaseBackup")
+		}
+	})
+}
+
+func TestShardFiltering(t *testing.T) {
+	inShard := map[string]string{"shard": "a"}
+	otherShard := map[string]string{"shard": "b"}
+	backup := func(name string, l map[string]string) *dbbackupv1alpha1.DatabaseBackup {
+		return &dbbackupv1alpha1.DatabaseBackup{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: l}}
+	}
+
+	tests := []struct {
+		name     string
+		selector labels.Selector
+		labels   map[string]string
+		want     bool
+	}{
+		{name: "unsharded, labeled", labels: otherShard, want: true},
+		{name: "unsharded, unlabeled", want: true},
+		{name: "in the shard", selector: labels.SelectorFromSet(inShard), labels: inShard, want: true},
+		{name: "in another shard", selector: labels.SelectorFromSet(inShard), labels: otherShard, want: false},
+		{name: "unlabeled with a shard", selector: labels.SelectorFromSet(inShard), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbBackup := backup("db", tt.labels)
+			r := newTestReconciler(t, dbBackup)
+			r.Selector = tt.selector
+
+			p := r.shardPredicate()
+			if got := p.Create(event.CreateEvent{Object: dbBackup}); got != tt.want {
+				t.Errorf("create passes = %v, want %v", got, tt.want)
+			}
+			if got := p.Delete(event.DeleteEvent{Object: dbBackup}); got != tt.want {
+				t.Errorf("delete passes = %v, want %v", got, tt.want)
+			}
+			if got := p.Generic(event.GenericEvent{Object: dbBackup}); got != tt.want {
+				t.Errorf("generic passes = %v, want %v", got, tt.want)
+			}
+
+			var backups dbbackupv1alpha1.DatabaseBackupList
+			if err := r.List(context.Background(), &backups, r.shardSelector()); err != nil {
+				t.Fatalf("listing DatabaseBackups: %v", err)
+			}
+			if got := len(backups.Items) == 1; got != tt.want {
+				t.Errorf("listed by shardSelector = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Both shards see a DatabaseBackup move between them
+	r := newTestReconciler(t)
+	r.Selector = labels.SelectorFromSet(inShard)
+	p := r.shardPredicate()
+	for _, update := range []event.UpdateEvent{
+		{ObjectOld: backup("db", inShard), ObjectNew: backup("db", otherShard)},
+		{ObjectOld: backup("db", otherShard), ObjectNew: backup("db", inShard)},
+	} {
+		if !p.Update(update) {
+			t.Errorf("update from %v to %v filtered out", update.ObjectOld.GetLabels(), update.ObjectNew.GetLabels())
+		}
+	}
+	if p.Update(event.UpdateEvent{ObjectOld: backup("db", otherShard), ObjectNew: backup("db", otherShard)}) {
+		t.Error("update within another shard passed")
+	}
+}
+
+func TestBackupWindowDelay(t *testing.T) {
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	window := func(start, end, tz string) *dbbackupv1alpha1.BackupWindowSpec {
+		return &dbbackupv1alpha1.BackupWindowSpec{Start: start, End: end, Timezone: tz}
+	}
+
+	tests := []struct {
+		name    string
+		window  *dbbackupv1alpha1.BackupWindowSpec
+		now     time.Time
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "in window", window: window("01:00", "05:00", ""), now: at(time.January, 10, 2, 0), want: 0},
+		{name: "at the start", window: window("01:00", "05:00", ""), now: at(time.January, 10, 1, 0), want: 0},
+		{name: "at the end", window: window("01:00", "05:00", ""), now: at(time.January, 10, 5, 0), want: 20 * time.Hour},
+		{name: "before the window", window: window("01:00", "05:00", ""), now: at(time.January, 10, 0, 30), want: 30 * time.Minute},
+		{name: "after the window", window: window("01:00", "05:00", ""), now: at(time.January, 10, 6, 0), want: 19 * time.Hour},
+		{name: "across midnight, before midnight", window: window("22:00", "02:00", ""), now: at(time.January, 10, 23, 0), want: 0},
+		{name: "across midnight, after midnight", window: window("22:00", "02:00", ""), now: at(time.January, 10, 1, 0), want: 0},
+		{name: "across midnight, closed", window: window("22:00", "02:00", ""), now: at(time.January, 10, 12, 0), want: 10 * time.Hour},
+		{name: "whole day", window: window("00:00", "00:00", ""), now: at(time.January, 10, 12, 0), want: 0},
+		// 02:00 in New York, UTC-5 in winter
+		{name: "behind UTC, open", window: window("01:00", "05:00", "America/New_York"), now: at(time.January, 10, 7, 0), want: 0},
+		// 21:00 the evening before in New York
+		{name: "behind UTC, closed", window: window("01:00", "05:00", "America/New_York"), now: at(time.January, 10, 2, 0), want: 4 * time.Hour},
+		// 03:00 the next day in Tokyo, UTC+9
+		{name: "ahead of UTC, open", window: window("01:00", "05:00", "Asia/Tokyo"), now: at(time.January, 10, 18, 0), want: 0},
+		// 09:00 in Tokyo
+		{name: "ahead of UTC, closed", window: window("01:00", "05:00", "Asia/Tokyo"), now: at(time.January, 10, 0, 0), want: 16 * time.Hour},
+		// Clocks in New York skip from 02:00 to 03:00 EDT overnight, so the
+		// window opens an hour sooner than the wall clocks suggest
+		{name: "across a DST change", window: window("03:00", "05:00", "America/New_York"), now: at(time.March, 7, 12, 0), want: 19 * time.Hour},
+		{name: "unknown timezone", window: window("01:00", "05:00", "Mars/Olympus_Mons"), now: at(time.January, 10, 2, 0), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := backupWindowDelay(tt.window, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("backupWindowDelay error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("backupWindowDelay = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleJitter(t *testing.T) {
+	hourly := mustParseSchedule(t, "0 * * * *")
+	now := time.Date(2026, time.January, 1, 0, 30, 0, 0, time.UTC)
+	jittered := func(uid types.UID, jitterSeconds int32) *dbbackupv1alpha1.DatabaseBackup {
+		return &dbbackupv1alpha1.DatabaseBackup{
+			ObjectMeta: metav1.ObjectMeta{UID: uid},
+			Spec:       dbbackupv1alpha1.DatabaseBackupSpec{Schedule: "0 * * * *", JitterSeconds: jitterSeconds},
+		}
+	}
+
+	t.Run("stable and spread out", func(t *testing.T) {
+		a := nextScheduledRun(hourly, jittered("uid-a", 600), now)
+		b := nextScheduledRun(hourly, jittered("uid-b", 600), now)
+		if a.Equal(b) {
+			t.Errorf("both objects run at %s, want different offsets", a)
+		}
+		if again := nextScheduledRun(hourly, jittered("uid-a", 600), now); !again.Equal(a) {
+			t.Errorf("offset changed from %s to %s", a, again)
+		}
+	})
+
+	tests := []struct {
+		name          string
+		jitterSeconds int32
+		bound         time.Duration
+	}{
+		{name: "no jitter", bound: 0},
+		{name: "within the jitter", jitterSeconds: 600, bound: 600 * time.Second},
+		// Never past the following tick, an hour later
+		{name: "capped by the schedule", jitterSeconds: 7200, bound: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				uid := types.UID(fmt.Sprintf("uid-%d", i))
+				run := nextScheduledRun(hourly, jittered(uid, tt.jitterSeconds), now)
+				if !run.After(now) {
+					t.Fatalf("%s runs at %s, before now", uid, run)
+				}
+				// The offset from the hourly tick the run belongs to
+				if offset := run.Sub(run.Truncate(time.Hour)); offset > 0 && offset >= tt.bound {
+					t.Fatalf("%s runs %s after its tick, want under %s", uid, offset, tt.bound)
+				}
+			}
+		})
+	}
+}
+
+func TestNextRunAfterSlot(t *testing.T) {
+	daily := mustParseSchedule(t, "0 2 * * *")
+	slot := time.Date(2026, time.January, 10, 2, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{name: "started on time", now: slot.Add(time.Second), want: slot.AddDate(0, 0, 1)},
+		// Anchored to the slot, so a slow start doesn't shift later runs
+		{name: "started hours late", now: slot.Add(5 * time.Hour), want: slot.AddDate(0, 0, 1)},
+		// The slot that passed meanwhile is caught up once
+		{name: "next slot passed too", now: slot.Add(25 * time.Hour), want: slot.AddDate(0, 0, 1)},
+		// Only the most recent missed slot is caught up
+		{name: "several slots passed", now: slot.Add(73 * time.Hour), want: slot.AddDate(0, 0, 3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nextRunAfterSlot(daily, &dbbackupv1alpha1.DatabaseBackup{}, slot, tt.now)
+			if !got.Equal(tt.want) {
+				t.Errorf("nextRunAfterSlot = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileAdvancesScheduleWithStart(t *testing.T) {
+	slot := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Hour))
+	dbBackup := waitingBackup("db", 0, func(b *dbbackupv1alpha1.DatabaseBackup) {
+		b.Spec.DatabaseType = "postgres"
+		b.Spec.StorageDestination = dbbackupv1alpha1.StorageDestinationSpec{Type: "s3", Bucket: "backups"}
+		b.Status.LastBackupStatus = "Pending"
+		b.Status.NextScheduledBackup = &slot
+	})
+	r := newTestReconciler(t, dbBackup)
+	counting := &statusCountingClient{Client: r.Client}
+	r.Client = counting
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "default"}}
+
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if counting.updates != 1 {
+		t.Errorf("%d status updates, want the start recorded in one", counting.updates)
+	}
+
+	var got dbbackupv1alpha1.DatabaseBackup
+	if err := r.Get(context.Background(), req.NamespacedName, &got); err != nil {
+		t.Fatalf("getting DatabaseBackup: %v", err)
+	}
+	if got.Status.ActiveBackupJob == "" {
+		t.Fatal("no backup job recorded")
+	}
+	// The slot after the one started, not a time computed from now
+	want := slot.Add(time.Hour)
+	if got.Status.NextScheduledBackup == nil || !got.Status.NextScheduledBackup.Time.Equal(want) {
+		t.Errorf("NextScheduledBackup = %v, want %s", got.Status.NextScheduledBackup, want)
+	}
+}
+
+// createErrorClient fails every Job create with err
+type createErrorClient struct {
+	client.Client
+	err error
+}
+
+func (c *createErrorClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if _, ok := obj.(*batchv1.Job); ok {
+		return c.err
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func TestReconcileForbiddenJobCreate(t *testing.T) {
+	jobs := schema.GroupResource{Group: "batch", Resource: "jobs"}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus string
+		wantCode   dbbackupv1alpha1.FailureCode
+		wantEvent  bool
+	}{
+		{
+			name:       "RBAC",
+			err:        apierrors.NewForbidden(jobs, "db-1", errors.New(`User "system:serviceaccount:default:db-operator" cannot create resource "jobs"`)),
+			wantStatus: "Error",
+			wantCode:   dbbackupv1alpha1.FailureJobCreateFailed,
+		},
+		{
+			name:       "quota",
+			err:        apierrors.NewForbidden(jobs, "db-1", errors.New("exceeded quota: compute, requested: count/jobs.batch=1, used: count/jobs.batch=10, limited: count/jobs.batch=10")),
+			wantStatus: "QuotaExceeded",
+			wantCode:   dbbackupv1alpha1.FailureQuotaExceeded,
+			wantEvent:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbBackup := waitingBackup("db", 0, func(b *dbbackupv1alpha1.DatabaseBackup) {
+				b.Spec.DatabaseType = "postgres"
+				b.Spec.StorageDestination = dbbackupv1alpha1.StorageDestinationSpec{Type: "s3", Bucket: "backups"}
+				b.Status.LastBackupStatus = "Pending"
+			})
+			r := newTestReconciler(t, dbBackup)
+			r.Client = &createErrorClient{Client: r.Client, err: tt.err}
+			recorder := r.Recorder.(*record.FakeRecorder)
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "db", Namespace: "default"}}
+
+			// Returned without an error, so it isn't retried at the usual rate
+			result, err := r.Reconcile(context.Background(), req)
+			if err != nil {
+				t.Fatalf("reconcile: %v", err)
+			}
+			if result.RequeueAfter != forbiddenJobRequeue {
+				t.Errorf("RequeueAfter = %s, want %s", result.RequeueAfter, forbiddenJobRequeue)
+			}
+
+			var got dbbackupv1alpha1.DatabaseBackup
+			if err := r.Get(context.Background(), req.NamespacedName, &got); err != nil {
+				t.Fatalf("getting DatabaseBackup: %v", err)
+			}
+			if got.Status.LastBackupStatus != tt.wantStatus || got.Status.FailureCode != tt.wantCode {
+				t.Errorf("status = %s/%s, want %s/%s", got.Status.LastBackupStatus, got.Status.FailureCode, tt.wantStatus, tt.wantCode)
+			}
+			if !strings.Contains(got.Status.FailureReason, tt.err.Error()) {
+				t.Errorf("FailureReason = %q, want it to carry %q", got.Status.FailureReason, tt.err.Error())
+			}
+			// The slot is kept, so the backup runs once creation is allowed
+			// Stored times keep whole seconds
+			if slot := dbBackup.Status.NextScheduledBackup.Truncate(time.Second); !got.Status.NextScheduledBackup.Time.Equal(slot) {
+				t.Errorf("NextScheduledBackup moved to %s", got.Status.NextScheduledBackup)
+			}
+
+			quotaEvents := 0
+			for len(recorder.Events) > 0 {
+				if strings.Contains(<-recorder.Events, "QuotaExceeded") {
+					quotaEvents++
+				}
+			}
+			if (quotaEvents > 0) != tt.wantEvent {
+				t.Errorf("%d QuotaExceeded events, want event %v", quotaEvents, tt.wantEvent)
+			}
+		})
+	}
+}
+
+func TestSyncActiveJob(t *testing.T) {
+	dbBackup := func() *dbbackupv1alpha1.DatabaseBackup {
+		return &dbbackupv1alpha1.DatabaseBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", UID: "backup-uid"},
+			Status: dbbackupv1alpha1.DatabaseBackupStatus{
+				ActiveBackupJob:    "db-1",
+				ActiveBackupJobUID: "old-uid",
+				LastBackupStatus:   "Running",
+			},
+		}
+	}
+	finished := func(job *batchv1.Job) *batchv1.Job {
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+		return job
+	}
+
+	tests := []struct {
+		name    string
+		status  func(*dbbackupv1alpha1.DatabaseBackupStatus)
+		jobs    func(owner *dbbackupv1alpha1.DatabaseBackup) []runtime.Object
+		wantJob string
+		wantUID types.UID
+	}{
+		{
+			name: "same job",
+			jobs: func(o *dbbackupv1alpha1.DatabaseBackup) []runtime.Object {
+				return []runtime.Object{ownedJob("db-1", "old-uid", o)}
+			},
+			wantJob: "db-1",
+			wantUID: "old-uid",
+		},
+		{
+			name:   "recorded before UIDs were tracked",
+			status: func(s *dbbackupv1alpha1.DatabaseBackupStatus) { s.ActiveBackupJobUID = "" },
+			jobs: func(o *dbbackupv1alpha1.DatabaseBackup) []runtime.Object {
+				return []runtime.Object{ownedJob("db-1", "old-uid", o)}
+			},
+			wantJob: "db-1",
+			wantUID: "old-uid",
+		},
+		{
+			// The running replacement is adopted as an orphan, under its own UID
+			name: "replaced by a running job of the same name",
+			jobs: func(o *dbbackupv1alpha1.DatabaseBackup) []runtime.Object {
+				return []runtime.Object{ownedJob("db-1", "new-uid", o)}
+			},
+			wantJob: "db-1",
+			wantUID: "new-uid",
+		},
+		{
+			name: "replaced by a finished job of the same name",
+			jobs: func(o *dbbackupv1alpha1.DatabaseBackup) []runtime.Object {
+				return []runtime.Object{finished(ownedJob("db-1", "new-uid", o))}
+			},
+		},
+		{
+			name: "job gone",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner := dbBackup()
+			if tt.status != nil {
+				tt.status(&owner.Status)
+			}
+			var objs []runtime.Object
+			if tt.jobs != nil {
+				objs = tt.jobs(owner)
+			}
+			r := newTestReconciler(t, objs...)
+
+			if err := r.syncActiveJob(context.Background(), owner); err != nil {
+				t.Fatalf("syncActiveJob: %v", err)
+			}
+			if owner.Status.ActiveBackupJob != tt.wantJob || owner.Status.ActiveBackupJobUID != tt.wantUID {
+				t.Errorf("active job = %q (%q), want %q (%q)", owner.Status.ActiveBackupJob, owner.Status.ActiveBackupJobUID, tt.wantJob, tt.wantUID)
+			}
+		})
+	}
+}
+
+// Helper function to find an env var of a container by name
+func findEnv(container corev1.Container, name string) *corev1.EnvVar {
+	for i := range container.Env {
+		if container.Env[i].Name == name {
+			return &container.Env[i]
+		}
+	}
+	return nil
+}
+
+// Helper function to check the value of a backup container env var
+func expectEnv(t *testing.T, job *batchv1.Job, name, value string) {
+	t.Helper()
+	env := findEnv(job.Spec.Template.Spec.Containers[0], name)
+	if env == nil {
+		t.Errorf("env %s not set", name)
+	} else if env.Value != value {
+		t.Errorf("env %s = %q, want %q", name, env.Value, value)
+	}
+}
+
+func TestBuildBackupJob(t *testing.T) {
+	scheduledTime := time.Date(2026, time.March, 1, 2, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		spec    func(*dbbackupv1alpha1.DatabaseBackupSpec)
+		check   func(t *testing.T, job *batchv1.Job)
+		wantErr string
+	}{
+		{
+			name: "priority class and preemption policy",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				never := corev1.PreemptNever
+				s.PriorityClassName = "backup-low"
+				s.PreemptionPolicy = &never
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				podSpec := job.Spec.Template.Spec
+				if podSpec.PriorityClassName != "backup-low" {
+					t.Errorf("priorityClassName = %q, want backup-low", podSpec.PriorityClassName)
+				}
+				if podSpec.PreemptionPolicy == nil || *podSpec.PreemptionPolicy != corev1.PreemptNever {
+					t.Errorf("preemptionPolicy = %v, want Never", podSpec.PreemptionPolicy)
+				}
+			},
+		},
+		{
+			name: "no priority class",
+			check: func(t *testing.T, job *batchv1.Job) {
+				podSpec := job.Spec.Template.Spec
+				if podSpec.PriorityClassName != "" || podSpec.PreemptionPolicy != nil {
+					t.Errorf("priorityClassName = %q, preemptionPolicy = %v, want unset", podSpec.PriorityClassName, podSpec.PreemptionPolicy)
+				}
+			},
+		},
+		{
+			name: "default backoff limit",
+			check: func(t *testing.T, job *batchv1.Job) {
+				if limit := job.Spec.BackoffLimit; limit == nil || *limit != dbbackupv1alpha1.DefaultJobBackoffLimit {
+					t.Errorf("backoffLimit = %v, want %d", limit, dbbackupv1alpha1.DefaultJobBackoffLimit)
+				}
+			},
+		},
+		{
+			name: "backoff limit zero",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				zero := int32(0)
+				s.JobBackoffLimit = &zero
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				if limit := job.Spec.BackoffLimit; limit == nil || *limit != 0 {
+					t.Errorf("backoffLimit = %v, want 0", limit)
+				}
+			},
+		},
+		{
+			name: "extra volumes after the managed ones",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.StorageDestination = dbbackupv1alpha1.StorageDestinationSpec{Type: "pvc", PVCName: "backups", SecretName: "storage"}
+				s.ExtraVolumes = []corev1.Volume{{
+					Name:         "ca-bundle",
+					VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "ca"}}},
+				}}
+				s.ExtraVolumeMounts = []corev1.VolumeMount{{Name: "ca-bundle", MountPath: "/etc/ca"}}
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				podSpec := job.Spec.Template.Spec
+				var volumes, mounts []string
+				for _, volume := range podSpec.Volumes {
+					volumes = append(volumes, volume.Name)
+				}
+				for _, mount := range podSpec.Containers[0].VolumeMounts {
+					mounts = append(mounts, mount.Name+":"+mount.MountPath)
+				}
+				if got, want := strings.Join(volumes, ","), "backup-storage,storage-credentials,ca-bundle"; got != want {
+					t.Errorf("volumes = %s, want %s", got, want)
+				}
+				if got, want := strings.Join(mounts, ","), "backup-storage:/backups,storage-credentials:/credentials,ca-bundle:/etc/ca"; got != want {
+					t.Errorf("mounts = %s, want %s", got, want)
+				}
+			},
+		},
+		{
+			name: "parallel shards",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				parallelism, completions := int32(2), int32(4)
+				s.Parallelism = &parallelism
+				s.Completions = &completions
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				if p := job.Spec.Parallelism; p == nil || *p != 2 {
+					t.Errorf("parallelism = %v, want 2", p)
+				}
+				if c := job.Spec.Completions; c == nil || *c != 4 {
+					t.Errorf("completions = %v, want 4", c)
+				}
+				if mode := job.Spec.CompletionMode; mode == nil || *mode != batchv1.IndexedCompletion {
+					t.Errorf("completionMode = %v, want Indexed", mode)
+				}
+				expectEnv(t, job, "SHARD_COUNT", "4")
+				index := findEnv(job.Spec.Template.Spec.Containers[0], "SHARD_INDEX")
+				if index == nil || index.ValueFrom == nil || index.ValueFrom.FieldRef == nil ||
+					index.ValueFrom.FieldRef.FieldPath != "metadata.annotations['"+batchv1.JobCompletionIndexAnnotation+"']" {
+					t.Errorf("SHARD_INDEX = %+v, want the completion index annotation", index)
+				}
+			},
+		},
+		{
+			name: "one shard per parallel pod",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				parallelism := int32(3)
+				s.Parallelism = &parallelism
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				if c := job.Spec.Completions; c == nil || *c != 3 {
+					t.Errorf("completions = %v, want 3", c)
+				}
+				expectEnv(t, job, "SHARD_COUNT", "3")
+			},
+		},
+		{
+			name: "single pod",
+			check: func(t *testing.T, job *batchv1.Job) {
+				if job.Spec.Parallelism != nil || job.Spec.Completions != nil || job.Spec.CompletionMode != nil {
+					t.Errorf("parallelism = %v, completions = %v, completionMode = %v, want unset", job.Spec.Parallelism, job.Spec.Completions, job.Spec.CompletionMode)
+				}
+				if env := findEnv(job.Spec.Template.Spec.Containers[0], "SHARD_INDEX"); env != nil {
+					t.Errorf("SHARD_INDEX set without sharding: %+v", env)
+				}
+			},
+		},
+		{
+			name: "env from a config map",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.EnvFrom = []corev1.EnvFromSource{{
+					ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "backup-tuning"}},
+				}}
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				container := job.Spec.Template.Spec.Containers[0]
+				if len(container.EnvFrom) != 1 || container.EnvFrom[0].ConfigMapRef == nil || container.EnvFrom[0].ConfigMapRef.Name != "backup-tuning" {
+					t.Errorf("envFrom = %+v, want the backup-tuning config map", container.EnvFrom)
+				}
+				// Env wins over EnvFrom, so the operator's own variables stay in Env
+				expectEnv(t, job, "DB_TYPE", "postgres")
+				expectEnv(t, job, "STORAGE_TYPE", "s3")
+			},
+		},
+		{
+			name: "object lock rounded up to days",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.StorageDestination.Immutability = &dbbackupv1alpha1.ImmutabilitySpec{
+					RetainUntilDuration: metav1.Duration{Duration: 30*24*time.Hour + time.Hour},
+				}
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				expectEnv(t, job, "OBJECT_LOCK_DAYS", "31")
+				expectEnv(t, job, "CLEANUP_SKIP_LOCKED", "true")
+			},
+		},
+		{
+			name: "restricted security contexts",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				nonRoot, noEscalation := true, false
+				s.PodSecurityContext = &corev1.PodSecurityContext{
+					RunAsNonRoot:   &nonRoot,
+					SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+				}
+				s.ContainerSecurityContext = &corev1.SecurityContext{
+					AllowPrivilegeEscalation: &noEscalation,
+					Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+				}
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				pod := job.Spec.Template.Spec.SecurityContext
+				if pod == nil || pod.RunAsNonRoot == nil || !*pod.RunAsNonRoot || pod.SeccompProfile == nil {
+					t.Errorf("pod securityContext = %+v, want runAsNonRoot with a seccomp profile", pod)
+				}
+				container := job.Spec.Template.Spec.Containers[0].SecurityContext
+				if container == nil || container.Capabilities == nil || len(container.Capabilities.Drop) != 1 || container.Capabilities.Drop[0] != "ALL" {
+					t.Errorf("container securityContext = %+v, want all capabilities dropped", container)
+				}
+			},
+		},
+		{
+			name: "no security contexts",
+			check: func(t *testing.T, job *batchv1.Job) {
+				if job.Spec.Template.Spec.SecurityContext != nil || job.Spec.Template.Spec.Containers[0].SecurityContext != nil {
+					t.Error("security context set without one in the spec")
+				}
+			},
+		},
+		{
+			name: "artifact named from the template",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.NamingTemplate = "{{.DatabaseType}}/{{.Namespace}}-{{.Name}}-{{.Date}}"
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				expectEnv(t, job, "ARTIFACT_NAME", "postgres/default-db-20260301")
+				// The Job keeps its slot name
+				if job.Name != "db-202603010200" {
+					t.Errorf("job name = %s, want db-202603010200", job.Name)
+				}
+			},
+		},
+		{
+			name: "naming template with an unknown field",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.NamingTemplate = "{{.Cluster}}-{{.Name}}"
+			},
+			wantErr: "invalid naming template",
+		},
+		{
+			name: "custom storage endpoint",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				pathStyle := false
+				s.StorageDestination.Endpoint = "https://minio.storage.svc:9000"
+				s.StorageDestination.UsePathStyle = &pathStyle
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				expectEnv(t, job, "STORAGE_ENDPOINT", "https://minio.storage.svc:9000")
+				expectEnv(t, job, "S3_PATH_STYLE", "false")
+			},
+		},
+		{
+			name: "default storage endpoint",
+			check: func(t *testing.T, job *batchv1.Job) {
+				container := job.Spec.Template.Spec.Containers[0]
+				if findEnv(container, "STORAGE_ENDPOINT") != nil || findEnv(container, "S3_PATH_STYLE") != nil {
+					t.Error("endpoint env set without an endpoint in the spec")
+				}
+			},
+		},
+		{
+			name: "dns config and host aliases",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.DNSPolicy = corev1.DNSNone
+				s.DNSConfig = &corev1.PodDNSConfig{Nameservers: []string{"10.0.0.53"}, Searches: []string{"db.internal"}}
+				s.HostAliases = []corev1.HostAlias{{IP: "10.1.2.3", Hostnames: []string{"pg.db.internal"}}}
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				podSpec := job.Spec.Template.Spec
+				if podSpec.DNSPolicy != corev1.DNSNone {
+					t.Errorf("dnsPolicy = %q, want None", podSpec.DNSPolicy)
+				}
+				if podSpec.DNSConfig == nil || len(podSpec.DNSConfig.Nameservers) != 1 || podSpec.DNSConfig.Nameservers[0] != "10.0.0.53" {
+					t.Errorf("dnsConfig = %+v, want nameserver 10.0.0.53", podSpec.DNSConfig)
+				}
+				if len(podSpec.HostAliases) != 1 || podSpec.HostAliases[0].IP != "10.1.2.3" {
+					t.Errorf("hostAliases = %+v, want 10.1.2.3", podSpec.HostAliases)
+				}
+			},
+		},
+		{
+			name: "cluster dns",
+			check: func(t *testing.T, job *batchv1.Job) {
+				podSpec := job.Spec.Template.Spec
+				if podSpec.DNSPolicy != "" || podSpec.DNSConfig != nil || podSpec.HostAliases != nil {
+					t.Errorf("dnsPolicy = %q, dnsConfig = %+v, hostAliases = %+v, want unset", podSpec.DNSPolicy, podSpec.DNSConfig, podSpec.HostAliases)
+				}
+			},
+		},
+		{
+			name: "scheduler and queue labels",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.SchedulerName = "volcano"
+				s.JobLabels = map[string]string{
+					"kueue.x-k8s.io/queue-name": "backups",
+					backupNameLabel:             "other",
+				}
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				if name := job.Spec.Template.Spec.SchedulerName; name != "volcano" {
+					t.Errorf("schedulerName = %q, want volcano", name)
+				}
+				if queue := job.Labels["kueue.x-k8s.io/queue-name"]; queue != "backups" {
+					t.Errorf("queue label = %q, want backups", queue)
+				}
+				// User labels never replace the operator's own
+				if owner := job.Labels[backupNameLabel]; owner != "db" {
+					t.Errorf("%s = %q, want db", backupNameLabel, owner)
+				}
+			},
+		},
+		{
+			name: "included tables",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.IncludeTables = []string{"orders", "payments"}
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				expectEnv(t, job, "INCLUDE_TABLES", "orders,payments")
+				if env := findEnv(job.Spec.Template.Spec.Containers[0], "EXCLUDE_TABLES"); env != nil {
+					t.Errorf("EXCLUDE_TABLES set without excluded tables: %+v", env)
+				}
+			},
+		},
+		{
+			name: "excluded tables",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.ExcludeTables = []string{"audit_log"}
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				expectEnv(t, job, "EXCLUDE_TABLES", "audit_log")
+			},
+		},
+		{
+			name: "bandwidth limit",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.BandwidthLimit = "50MB/s"
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				expectEnv(t, job, "RATE_LIMIT", "50MB/s")
+			},
+		},
+		{
+			name: "downward api pod metadata",
+			check: func(t *testing.T, job *batchv1.Job) {
+				container := job.Spec.Template.Spec.Containers[0]
+				for name, fieldPath := range map[string]string{
+					"POD_NAME":      "metadata.name",
+					"POD_NAMESPACE": "metadata.namespace",
+					"NODE_NAME":     "spec.nodeName",
+				} {
+					env := findEnv(container, name)
+					if env == nil || env.ValueFrom == nil || env.ValueFrom.FieldRef == nil || env.ValueFrom.FieldRef.FieldPath != fieldPath {
+						t.Errorf("%s = %+v, want a fieldRef to %s", name, env, fieldPath)
+					}
+				}
+				// Operator env is set once, next to the downward API entries
+				seen := map[string]bool{}
+				for _, env := range container.Env {
+					if seen[env.Name] {
+						t.Errorf("env %s set twice", env.Name)
+					}
+					seen[env.Name] = true
+				}
+			},
+		},
+		{
+			name: "indexed sharded backup",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				completions := int32(3)
+				s.ShardedBackup = true
+				s.Completions = &completions
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				if mode := job.Spec.CompletionMode; mode == nil || *mode != batchv1.IndexedCompletion {
+					t.Errorf("completionMode = %v, want Indexed", mode)
+				}
+				if c := job.Spec.Completions; c == nil || *c != 3 {
+					t.Errorf("completions = %v, want 3", c)
+				}
+				index := findEnv(job.Spec.Template.Spec.Containers[0], "JOB_COMPLETION_INDEX")
+				if index == nil || index.ValueFrom == nil || index.ValueFrom.FieldRef == nil ||
+					index.ValueFrom.FieldRef.FieldPath != "metadata.annotations['"+batchv1.JobCompletionIndexAnnotation+"']" {
+					t.Errorf("JOB_COMPLETION_INDEX = %+v, want the completion index annotation", index)
+				}
+			},
+		},
+		{
+			name: "backup tags",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.BackupTags = map[string]string{"env": "prod", "classification": "pii"}
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				// Keys are sorted, so the env is stable across reconciles
+				expectEnv(t, job, "BACKUP_TAGS", `{"classification":"pii","env":"prod"}`)
+			},
+		},
+		{
+			name: "stream to storage",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				stream := true
+				s.StreamToStorage = &stream
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				expectEnv(t, job, "STREAM", "true")
+				for _, volume := range job.Spec.Template.Spec.Volumes {
+					if volume.Name == "backup-storage" {
+						t.Error("staging volume mounted in stream mode")
+					}
+				}
+			},
+		},
+		{
+			name: "stream to storage disabled",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				stream := false
+				s.StreamToStorage = &stream
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				if env := findEnv(job.Spec.Template.Spec.Containers[0], "STREAM"); env != nil {
+					t.Errorf("STREAM set with streamToStorage false: %+v", env)
+				}
+			},
+		},
+		{
+			name: "database client certificate",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.TLSConfig = &dbbackupv1alpha1.DatabaseTLSSpec{SecretName: "db-client-cert"}
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				podSpec := job.Spec.Template.Spec
+				var secret *corev1.SecretVolumeSource
+				for _, volume := range podSpec.Volumes {
+					if volume.Name == "db-tls" {
+						secret = volume.Secret
+					}
+				}
+				if secret == nil || secret.SecretName != "db-client-cert" || secret.DefaultMode == nil || *secret.DefaultMode != 0440 {
+					t.Errorf("db-tls volume = %+v, want db-client-cert with mode 0440", secret)
+				}
+				mounted := false
+				for _, mount := range podSpec.Containers[0].VolumeMounts {
+					mounted = mounted || (mount.Name == "db-tls" && mount.MountPath == "/db-tls" && mount.ReadOnly)
+				}
+				if !mounted {
+					t.Errorf("mounts = %+v, want db-tls read-only at /db-tls", podSpec.Containers[0].VolumeMounts)
+				}
+				expectEnv(t, job, "DB_SSL_MODE", dbbackupv1alpha1.DefaultSSLMode)
+				expectEnv(t, job, "DB_SSL_ROOT_CERT", "/db-tls/ca.crt")
+				expectEnv(t, job, "DB_SSL_CERT", "/db-tls/tls.crt")
+				expectEnv(t, job, "DB_SSL_KEY", "/db-tls/tls.key")
+			},
+		},
+		{
+			name: "command and args override",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.Command = []string{"/usr/local/bin/dump"}
+				s.Args = []string{"--verbose"}
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				container := job.Spec.Template.Spec.Containers[0]
+				if strings.Join(container.Command, " ") != "/usr/local/bin/dump" || strings.Join(container.Args, " ") != "--verbose" {
+					t.Errorf("command = %q, args = %q, want the overrides", container.Command, container.Args)
+				}
+			},
+		},
+		{
+			name: "image entrypoint",
+			check: func(t *testing.T, job *batchv1.Job) {
+				container := job.Spec.Template.Spec.Containers[0]
+				if container.Command != nil || container.Args != nil {
+					t.Errorf("command = %q, args = %q, want the image defaults", container.Command, container.Args)
+				}
+			},
+		},
+		{
+			name: "lifecycle rules",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.LifecycleRules = []dbbackupv1alpha1.LifecycleRule{
+					{AfterHours: 168, Action: dbbackupv1alpha1.LifecycleTransition, StorageClass: "GLACIER"},
+					{AfterHours: 720, Action: dbbackupv1alpha1.LifecycleDelete},
+				}
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				expectEnv(t, job, "LIFECYCLE_RULES", `[{"afterHours":168,"action":"Transition","storageClass":"GLACIER"},{"afterHours":720,"action":"Delete"}]`)
+			},
+		},
+		{
+			name: "workers",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.Workers = 4
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				expectEnv(t, job, "WORKERS", "4")
+			},
+		},
+		{
+			name: "single worker",
+			check: func(t *testing.T, job *batchv1.Job) {
+				if env := findEnv(job.Spec.Template.Spec.Containers[0], "WORKERS"); env != nil {
+					t.Errorf("WORKERS set without workers in the spec: %+v", env)
+				}
+			},
+		},
+		{
+			name: "storage ca bundle",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.StorageCABundleSecret = "minio-ca"
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				podSpec := job.Spec.Template.Spec
+				var secret *corev1.SecretVolumeSource
+				for _, volume := range podSpec.Volumes {
+					if volume.Name == "storage-ca" {
+						secret = volume.Secret
+					}
+				}
+				if secret == nil || secret.SecretName != "minio-ca" || len(secret.Items) != 1 || secret.Items[0].Key != "ca.crt" {
+					t.Errorf("storage-ca volume = %+v, want ca.crt of minio-ca", secret)
+				}
+				mounted := false
+				for _, mount := range podSpec.Containers[0].VolumeMounts {
+					mounted = mounted || (mount.Name == "storage-ca" && mount.MountPath == "/storage-ca" && mount.ReadOnly)
+				}
+				if !mounted {
+					t.Errorf("mounts = %+v, want storage-ca read-only at /storage-ca", podSpec.Containers[0].VolumeMounts)
+				}
+				expectEnv(t, job, "STORAGE_CA_BUNDLE", "/storage-ca/ca.crt")
+			},
+		},
+		{
+			name: "upload sidecar",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				pipeline := true
+				s.PipelineMode = &pipeline
+				s.StorageDestination.SecretName = "storage"
+				s.TLSConfig = &dbbackupv1alpha1.DatabaseTLSSpec{SecretName: "db-client-cert"}
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				podSpec := job.Spec.Template.Spec
+				if len(podSpec.Containers) != 2 || podSpec.Containers[1].Name != pipelineUploadContainer {
+					t.Fatalf("containers = %d, want backup and %s", len(podSpec.Containers), pipelineUploadContainer)
+				}
+				backup, upload := podSpec.Containers[0], podSpec.Containers[1]
+				if upload.Image != backup.Image {
+					t.Errorf("sidecar image = %s, want %s", upload.Image, backup.Image)
+				}
+				for container, role := range map[*corev1.Container]string{&backup: "dump", &upload: "upload"} {
+					if env := findEnv(*container, "PIPELINE_ROLE"); env == nil || env.Value != role {
+						t.Errorf("%s PIPELINE_ROLE = %+v, want %s", container.Name, env, role)
+					}
+				}
+
+				// The sidecar gets storage settings only, never how to reach the database
+				if findEnv(upload, "BUCKET") == nil || findEnv(upload, "DB_SSL_MODE") != nil {
+					t.Errorf("sidecar env = %+v, want storage env without database env", upload.Env)
+				}
+				var mounts []string
+				for _, mount := range upload.VolumeMounts {
+					mounts = append(mounts, mount.Name)
+				}
+				if got, want := strings.Join(mounts, ","), "storage-credentials,pipeline"; got != want {
+					t.Errorf("sidecar mounts = %s, want %s", got, want)
+				}
+			},
+		},
+		{
+			name: "no upload sidecar",
+			check: func(t *testing.T, job *batchv1.Job) {
+				if n := len(job.Spec.Template.Spec.Containers); n != 1 {
+					t.Errorf("%d containers without pipeline mode, want 1", n)
+				}
+			},
+		},
+		{
+			name: "file extension from the database type",
+			check: func(t *testing.T, job *batchv1.Job) {
+				expectEnv(t, job, "FILE_EXTENSION", ".sql.gz")
+			},
+		},
+		{
+			name: "file extension of encrypted mongodb backups",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.DatabaseType = "mongodb"
+				s.Encryption = &dbbackupv1alpha1.EncryptionSpec{SecretName: "backup-key"}
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				expectEnv(t, job, "FILE_EXTENSION", ".archive.gz.enc")
+			},
+		},
+		{
+			name: "file extension override",
+			spec: func(s *dbbackupv1alpha1.DatabaseBackupSpec) {
+				s.FileExtension = ".dump"
+			},
+			check: func(t *testing.T, job *batchv1.Job) {
+				expectEnv(t, job, "FILE_EXTENSION", ".dump")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbBackup := &dbbackupv1alpha1.DatabaseBackup{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+				Spec: dbbackupv1alpha1.DatabaseBackupSpec{
+					DatabaseType:       "postgres",
+					StorageDestination: dbbackupv1alpha1.StorageDestinationSpec{Type: "s3", Bucket: "backups"},
+				},
+			}
+			if tt.spec != nil {
+				tt.spec(&dbBackup.Spec)
+			}
+
+			job, err := buildBackupJob(dbBackup, scheduledTime)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("buildBackupJob error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildBackupJob: %v", err)
+			}
+			// createBackupJob adds the sidecar once the backup container is final
+			addUploadPipeline(&job.Spec.Template.Spec, dbBackup)
+			tt.check(t, job)
+		})
+	}
+}
+
+func TestSyncStorageSecret(t *testing.T) {
+	source := func(key string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "storage", Namespace: "shared"},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{"access-key": []byte(key)},
+		}
+	}
+	crossNamespace := func() *dbbackupv1alpha1.DatabaseBackup {
+		return &dbbackupv1alpha1.DatabaseBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", UID: "backup-uid"},
+			Spec: dbbackupv1alpha1.DatabaseBackupSpec{
+				StorageDestination: dbbackupv1alpha1.StorageDestinationSpec{Type: "s3", SecretName: "storage", SecretNamespace: "shared"},
+			},
+		}
+	}
+	copyKey := types.NamespacedName{Name: "db-storage-credentials", Namespace: "default"}
+	ctx := context.Background()
+
+	t.Run("same namespace", func(t *testing.T) {
+		dbBackup := crossNamespace()
+		dbBackup.Spec.StorageDestination.SecretNamespace = "default"
+		r := newTestReconciler(t)
+		if err := r.syncStorageSecret(ctx, dbBackup); err != nil {
+			t.Fatalf("syncStorageSecret: %v", err)
+		}
+		var secrets corev1.SecretList
+		if err := r.List(ctx, &secrets); err != nil {
+			t.Fatal(err)
+		}
+		if len(secrets.Items) != 0 {
+			t.Errorf("%d secrets created for a secret in the same namespace", len(secrets.Items))
+		}
+		if name := storageSecretName(dbBackup); name != "storage" {
+			t.Errorf("mounted secret = %s, want storage", name)
+		}
+	})
+
+	t.Run("copied and updated", func(t *testing.T) {
+		dbBackup := crossNamespace()
+		r := newTestReconciler(t, source("v1"))
+		if err := r.syncStorageSecret(ctx, dbBackup); err != nil {
+			t.Fatalf("syncStorageSecret: %v", err)
+		}
+		var secretCopy corev1.Secret
+		if err := r.Get(ctx, copyKey, &secretCopy); err != nil {
+			t.Fatalf("getting the copy: %v", err)
+		}
+		if string(secretCopy.Data["access-key"]) != "v1" || secretCopy.Type != corev1.SecretTypeOpaque {
+			t.Errorf("copy = %s %q, want Opaque v1", secretCopy.Type, secretCopy.Data["access-key"])
+		}
+		if !metav1.IsControlledBy(&secretCopy, dbBackup) {
+			t.Error("copy isn't owned by the DatabaseBackup")
+		}
+		if secretCopy.Labels[watchedSecretLabel] != "true" || secretCopy.Labels[backupNameLabel] != "db" {
+			t.Errorf("copy labels = %v, want it watched and labelled with the backup", secretCopy.Labels)
+		}
+
+		// Unchanged source, no write
+		version := secretCopy.ResourceVersion
+		if err := r.syncStorageSecret(ctx, dbBackup); err != nil {
+			t.Fatalf("syncStorageSecret: %v", err)
+		}
+		if err := r.Get(ctx, copyKey, &secretCopy); err != nil {
+			t.Fatal(err)
+		}
+		if secretCopy.ResourceVersion != version {
+			t.Error("copy rewritten although the source didn't change")
+		}
+
+		var stored corev1.Secret
+		if err := r.Get(ctx, client.ObjectKeyFromObject(source("")), &stored); err != nil {
+			t.Fatal(err)
+		}
+		stored.Data = map[string][]byte{"access-key": []byte("v2")}
+		if err := r.Update(ctx, &stored); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.syncStorageSecret(ctx, dbBackup); err != nil {
+			t.Fatalf("syncStorageSecret: %v", err)
+		}
+		if err := r.Get(ctx, copyKey, &secretCopy); err != nil {
+			t.Fatal(err)
+		}
+		if string(secretCopy.Data["access-key"]) != "v2" {
+			t.Errorf("copy = %q after the source changed, want v2", secretCopy.Data["access-key"])
+		}
+	})
+
+	t.Run("existing secret not owned", func(t *testing.T) {
+		dbBackup := crossNamespace()
+		unowned := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: copyKey.Name, Namespace: copyKey.Namespace},
+			Data:       map[string][]byte{"access-key": []byte("theirs")},
+		}
+		r := newTestReconciler(t, source("v1"), unowned)
+		if err := r.syncStorageSecret(ctx, dbBackup); err == nil {
+			t.Fatal("syncStorageSecret overwrote a secret it doesn't own")
+		}
+		var secret corev1.Secret
+		if err := r.Get(ctx, copyKey, &secret); err != nil {
+			t.Fatal(err)
+		}
+		if string(secret.Data["access-key"]) != "theirs" {
+			t.Errorf("unowned secret = %q, want it untouched", secret.Data["access-key"])
+		}
+		recorder := r.Recorder.(*record.FakeRecorder)
+		conflicts := 0
+		for len(recorder.Events) > 0 {
+			if strings.Contains(<-recorder.Events, "SecretConflict") {
+				conflicts++
+			}
+		}
+		if conflicts != 1 {
+			t.Errorf("%d SecretConflict events, want 1", conflicts)
+		}
+	})
+}
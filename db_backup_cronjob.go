@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+
+// Helper function to check if backups are scheduled by a native CronJob
+func isNativeCronJobMode(dbBackup *dbbackupv1alpha1.DatabaseBackup) bool {
+	return dbBackup.Spec.SchedulingMode == "NativeCronJob"
+}
+
+// Helper function to validate the parts of a spec NativeCronJob mode can't
+// support, since every run shares one pod template
+func validateNativeCronJobSpec(spec *dbbackupv1alpha1.DatabaseBackupSpec) error {
+	switch {
+	case spec.Mode == "snapshot" || spec.Mode == "exec":
+		return fmt.Errorf("schedulingMode NativeCronJob does not support %s mode", spec.Mode)
+	case spec.BackupType == "incremental":
+		return fmt.Errorf("schedulingMode NativeCronJob does not support incremental backups")
+	case spec.NamingTemplate != "":
+		return fmt.Errorf("schedulingMode NativeCronJob does not support namingTemplate")
+	case spec.JitterSeconds > 0:
+		return fmt.Errorf("schedulingMode NativeCronJob does not support jitterSeconds")
+	case spec.PreferRole != nil:
+		return fmt.Errorf("schedulingMode NativeCronJob does not support preferRole")
+	case spec.SuccessLogPattern != "":
+		return fmt.Errorf("schedulingMode NativeCronJob does not support successLogPattern")
+	case len(spec.Targets) > 0:
+		return fmt.Errorf("schedulingMode NativeCronJob does not support targets")
+	}
+	return nil
+}
+
+// Helper function to get the name of the CronJob running a DatabaseBackup's schedule
+func backupCronJobName(dbBackup *dbbackupv1alpha1.DatabaseBackup) string {
+	return fmt.Sprintf("%s-backup", dbBackup.Name)
+}
+
+// Helper function to run the schedule through an owned CronJob and mirror
+// its last run into status
+func (r *DatabaseBackupReconciler) reconcileCronJob(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	schedule, err := cron.ParseStandard(dbBackup.Spec.Schedule)
+	if err != nil {
+		log.Error(err, "Failed to parse schedule", "schedule", dbBackup.Spec.Schedule)
+		dbBackup.Status.LastBackupStatus = "Error"
+		dbBackup.Status.FailureReason = describeScheduleError(dbBackup.Spec.Schedule, err)
+		dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureInvalidSchedule
+		setScheduleValid(dbBackup, err)
+		return ctrl.Result{}, nil
+	}
+
+	// The Job name is set by the CronJob controller for each run
+	template, err := buildBackupJob(r.withStorageDefaults(dbBackup), time.Now())
+	if err != nil {
+		dbBackup.Status.LastBackupStatus = "Error"
+		dbBackup.Status.FailureReason = fmt.Sprintf("Failed to build backup job: %v", err)
+		dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureInvalidSpec
+		return ctrl.Result{}, nil
+	}
+	r.addImagePullSecret(&template.Spec.Template.Spec, dbBackup)
+	if dbBackup.Spec.Encryption != nil {
+		keyID, err := r.currentEncryptionKeyID(ctx, dbBackup)
+		if err != nil {
+			log.Error(err, "Failed to get encryption key id")
+			return ctrl.Result{}, err
+		}
+		addEncryptionKey(&template.Spec.Template.Spec, dbBackup, keyID)
+		template.Annotations[encryptionKeyIDAnnotation] = keyID
+	}
+	addUploadPipeline(&template.Spec.Template.Spec, dbBackup)
+
+	// A global pause suspends the CronJob for as long as it lasts
+	paused := meta.IsStatusConditionTrue(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionGloballyPaused)
+
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      backupCronJobName(dbBackup),
+			Namespace: dbBackup.Namespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, cronJob, func() error {
+		if cronJob.Labels == nil {
+			cronJob.Labels = map[string]string{}
+		}
+		cronJob.Labels["app"] = "db-backup-operator"
+		cronJob.Labels[backupNameLabel] = dbBackup.Name
+		cronJob.Spec.Schedule = dbBackup.Spec.Schedule
+		cronJob.Spec.StartingDeadlineSeconds = dbBackup.Spec.StartingDeadlineSeconds
+		cronJob.Spec.ConcurrencyPolicy = batchv1.ForbidConcurrent
+		cronJob.Spec.Suspend = &paused
+		cronJob.Spec.JobTemplate = batchv1.JobTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      template.Labels,
+				Annotations: template.Annotations,
+			},
+			Spec: template.Spec,
+		}
+		return ctrl.SetControllerReference(dbBackup, cronJob, r.Scheme)
+	}); err != nil {
+		log.Error(err, "Failed to create or update backup CronJob")
+		return ctrl.Result{}, err
+	}
+
+	// Mirror the CronJob's view of the last run
+	status := &dbBackup.Status
+	status.ActiveBackupJob = ""
+	status.ActiveBackupJobUID = ""
+	if active := cronJob.Status.Active; len(active) > 0 {
+		status.ActiveBackupJob = active[len(active)-1].Name
+		status.ActiveBackupJobUID = active[len(active)-1].UID
+	}
+	status.LastSuccessfulBackup = cronJob.Status.LastSuccessfulTime
+	lastSchedule := cronJob.Status.LastScheduleTime
+	switch {
+	case status.ActiveBackupJob != "":
+		status.LastBackupStatus = "Running"
+	case lastSchedule == nil:
+		status.LastBackupStatus = "Pending"
+	case status.LastSuccessfulBackup != nil && !status.LastSuccessfulBackup.Before(lastSchedule):
+		status.LastBackupStatus = "Succeeded"
+		status.FailureReason = ""
+		status.FailureCode = ""
+	default:
+		status.LastBackupStatus = "Failed"
+		status.FailureReason = "Backup job failed, check job logs for details"
+		status.FailureCode = dbbackupv1alpha1.FailureJobFailed
+	}
+	next := metav1.NewTime(schedule.Next(time.Now()))
+	status.NextScheduledBackup = &next
+	status.UpcomingBackups = upcomingBackups(schedule, dbBackup, next.Time)
+	status.ScheduleDescription = describeSchedule(dbBackup.Spec.Schedule)
+	setScheduleValid(dbBackup, nil)
+
+	// CronJob changes trigger reconciles; this just refreshes NextScheduledBackup
+	return ctrl.Result{RequeueAfter: time.Until(next.Time)}, nil
+}
+
+// Helper function to remove the backup CronJob left behind after switching
+// back to Controller scheduling
+func (r *DatabaseBackupReconciler) deleteBackupCronJob(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) error {
+	var cronJob batchv1.CronJob
+	cronJobName := types.NamespacedName{Name: backupCronJobName(dbBackup), Namespace: dbBackup.Namespace}
+	if err := r.Get(ctx, cronJobName, &cronJob); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(&cronJob, dbBackup) {
+		return nil
+	}
+	return client.IgnoreNotFound(r.Delete(ctx, &cronJob, client.PropagationPolicy(metav1.DeletePropagationBackground)))
+}
@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters,verbs=get
+//+kubebuilder:rbac:groups=acid.zalan.do,resources=postgresqls,verbs=get
+
+// databaseCredentialsDir is where a resolved database's credentials secret is mounted
+const databaseCredentialsDir = "/db-credentials"
+
+// databaseProvider extracts connection details from a database operator's
+// resource. Fields left empty come from the DatabaseRef's field paths
+type databaseProvider func(obj *unstructured.Unstructured) dbbackupv1alpha1.ResolvedDatabase
+
+// databaseProviders are the database operators whose resources resolve
+// without any field paths
+var databaseProviders = map[schema.GroupKind]databaseProvider{
+	// CloudNativePG: the read-write service and the application user secret
+	{Group: "postgresql.cnpg.io", Kind: "Cluster"}: func(obj *unstructured.Unstructured) dbbackupv1alpha1.ResolvedDatabase {
+		host, _, _ := unstructured.NestedString(obj.Object, "status", "writeService")
+		if host == "" {
+			host = obj.GetName() + "-rw"
+		}
+		secret, _, _ := unstructured.NestedString(obj.Object, "spec", "bootstrap", "initdb", "secret", "name")
+		if secret == "" {
+			secret = obj.GetName() + "-app"
+		}
+		return dbbackupv1alpha1.ResolvedDatabase{Host: host, Port: 5432, CredentialsSecret: secret}
+	},
+	// Zalando postgres-operator: the master service and the superuser secret
+	{Group: "acid.zalan.do", Kind: "postgresql"}: func(obj *unstructured.Unstructured) dbbackupv1alpha1.ResolvedDatabase {
+		return dbbackupv1alpha1.ResolvedDatabase{
+			Host:              obj.GetName(),
+			Port:              5432,
+			CredentialsSecret: fmt.Sprintf("postgres.%s.credentials.postgresql.acid.zalan.do", obj.GetName()),
+		}
+	},
+}
+
+// Helper function to read a dotted field path (e.g. status.host) as a string
+func nestedFieldString(obj *unstructured.Unstructured, fieldPath string) (string, error) {
+	value, found, err := unstructured.NestedFieldNoCopy(obj.Object, strings.Split(fieldPath, ".")...)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("field %s not found", fieldPath)
+	}
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("field %s is a %T, not a string or number", fieldPath, value)
+	}
+}
+
+// Helper function to resolve a DatabaseRef into connection details
+func (r *DatabaseBackupReconciler) resolveDatabaseRef(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (*dbbackupv1alpha1.ResolvedDatabase, error) {
+	ref := dbBackup.Spec.DatabaseRef
+	gk := schema.GroupKind{Group: ref.APIGroup, Kind: ref.Kind}
+	mapping, err := r.RESTMapper().RESTMapping(gk)
+	if err != nil {
+		return nil, fmt.Errorf("unknown database kind %s: %w", gk, err)
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(mapping.GroupVersionKind)
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: dbBackup.Namespace}, obj); err != nil {
+		return nil, fmt.Errorf("failed to get %s %s: %w", gk, ref.Name, err)
+	}
+
+	var resolved dbbackupv1alpha1.ResolvedDatabase
+	if provider, ok := databaseProviders[gk]; ok {
+		resolved = provider(obj)
+	}
+
+	// Field paths override the built-in providers and cover any other kind
+	if ref.HostField != "" {
+		if resolved.Host, err = nestedFieldString(obj, ref.HostField); err != nil {
+			return nil, fmt.Errorf("failed to read host from %s %s: %w", gk, ref.Name, err)
+		}
+	}
+	if ref.PortField != "" {
+		portValue, err := nestedFieldString(obj, ref.PortField)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read port from %s %s: %w", gk, ref.Name, err)
+		}
+		port, err := strconv.ParseInt(portValue, 10, 32)
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("invalid port %q in %s %s", portValue, gk, ref.Name)
+		}
+		resolved.Port = int32(port)
+	}
+	if ref.SecretField != "" {
+		if resolved.CredentialsSecret, err = nestedFieldString(obj, ref.SecretField); err != nil {
+			return nil, fmt.Errorf("failed to read credentials secret from %s %s: %w", gk, ref.Name, err)
+		}
+	}
+
+	if resolved.Host == "" {
+		return nil, fmt.Errorf("%s %s has no host; set hostField", gk, ref.Name)
+	}
+	return &resolved, nil
+}
+
+// Helper function to pass a resolved database's connection details to a backup pod
+func addDatabaseConnection(podSpec *corev1.PodSpec, resolved *dbbackupv1alpha1.ResolvedDatabase) {
+	container := &podSpec.Containers[0]
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  "DB_HOST",
+		Value: resolved.Host,
+	})
+	if resolved.Port != 0 {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "DB_PORT",
+			Value: strconv.Itoa(int(resolved.Port)),
+		})
+	}
+	if resolved.CredentialsSecret == "" {
+		return
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "db-credentials",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: resolved.CredentialsSecret,
+			},
+		},
+	})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      "db-credentials",
+		MountPath: databaseCredentialsDir,
+		ReadOnly:  true,
+	})
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  "DB_CREDENTIALS_DIR",
+		Value: databaseCredentialsDir,
+	})
+}
@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// destinationConfig is one entry of the STORAGE_DESTINATIONS variable handed
+// to backup images when more than one destination is configured
+type destinationConfig struct {
+	Name           string `json:"name"`
+	Type           string `json:"type"`
+	Bucket         string `json:"bucket,omitempty"`
+	Path           string `json:"path,omitempty"`
+	Endpoint       string `json:"endpoint,omitempty"`
+	UsePathStyle   *bool  `json:"usePathStyle,omitempty"`
+	MountPath      string `json:"mountPath,omitempty"`
+	CredentialsDir string `json:"credentialsDir,omitempty"`
+}
+
+// Helper function to check if a DatabaseBackup writes to more than one destination
+func hasMultipleDestinations(dbBackup *dbbackupv1alpha1.DatabaseBackup) bool {
+	return len(dbBackup.Spec.StorageDestinations) > 0
+}
+
+// Helper function to get the name a storage destination is reported under.
+// Index 0 is StorageDestination, index n is StorageDestinations[n-1].
+func destinationName(dest *dbbackupv1alpha1.StorageDestinationSpec, index int) string {
+	if dest.Name != "" {
+		return dest.Name
+	}
+	if index == 0 {
+		return "primary"
+	}
+	return fmt.Sprintf("destination-%d", index)
+}
+
+// Helper function to list the names of every configured storage destination
+func destinationNames(spec *dbbackupv1alpha1.DatabaseBackupSpec) []string {
+	names := []string{destinationName(&spec.StorageDestination, 0)}
+	for i := range spec.StorageDestinations {
+		names = append(names, destinationName(&spec.StorageDestinations[i], i+1))
+	}
+	return names
+}
+
+// Helper function to validate the additional storage destinations
+func validateDestinations(spec *dbbackupv1alpha1.DatabaseBackupSpec) error {
+	seen := map[string]bool{}
+	for _, name := range destinationNames(spec) {
+		if seen[name] {
+			return fmt.Errorf("duplicate storage destination name %q", name)
+		}
+		seen[name] = true
+	}
+	for i, dest := range spec.StorageDestinations {
+		name := destinationName(&dest, i+1)
+		if dest.SecretNamespace != "" {
+			return fmt.Errorf("storage destination %q: secretNamespace is only supported on storageDestination", name)
+		}
+		if dest.WorkloadIdentity != nil {
+			return fmt.Errorf("storage destination %q: workloadIdentity is only supported on storageDestination", name)
+		}
+		if dest.Immutability != nil {
+			return fmt.Errorf("storage destination %q: immutability is only supported on storageDestination", name)
+		}
+	}
+	return nil
+}
+
+// Helper function to mount the additional destinations' volumes and
+// credentials and describe every destination in STORAGE_DESTINATIONS.
+// The primary destination keeps the mounts set up by addStorageVolumes.
+func addDestinationsConfig(podSpec *corev1.PodSpec, dbBackup *dbbackupv1alpha1.DatabaseBackup) error {
+	return addSomeDestinationsConfig(podSpec, dbBackup, nil)
+}
+
+// Helper function like addDestinationsConfig, limited to the named
+// destinations. A nil only includes every destination.
+func addSomeDestinationsConfig(podSpec *corev1.PodSpec, dbBackup *dbbackupv1alpha1.DatabaseBackup, only []string) error {
+	included := func(name string) bool {
+		if only == nil {
+			return true
+		}
+		for _, n := range only {
+			if n == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	primary := dbBackup.Spec.StorageDestination
+	config := []destinationConfig{{
+		Name:         destinationName(&primary, 0),
+		Type:         primary.Type,
+		Bucket:       primary.Bucket,
+		Path:         primary.Path,
+		Endpoint:     primary.Endpoint,
+		UsePathStyle: primary.UsePathStyle,
+	}}
+	if primary.Type == "pvc" && primary.PVCName != "" {
+		config[0].MountPath = "/backups"
+	}
+	if primary.SecretName != "" {
+		config[0].CredentialsDir = "/credentials"
+	}
+	if !included(config[0].Name) {
+		config = nil
+	}
+
+	container := &podSpec.Containers[0]
+	for i, dest := range dbBackup.Spec.StorageDestinations {
+		entry := destinationConfig{
+			Name:         destinationName(&dest, i+1),
+			Type:         dest.Type,
+			Bucket:       dest.Bucket,
+			Path:         dest.Path,
+			Endpoint:     dest.Endpoint,
+			UsePathStyle: dest.UsePathStyle,
+		}
+		if !included(entry.Name) {
+			continue
+		}
+
+		if dest.Type == "pvc" && dest.PVCName != "" {
+			volumeName := "backup-storage-" + entry.Name
+			entry.MountPath = path.Join("/backups-extra", entry.Name)
+			podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+				Name: volumeName,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: dest.PVCName,
+					},
+				},
+			})
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      volumeName,
+				MountPath: entry.MountPath,
+			})
+		}
+
+		if dest.SecretName != "" {
+			volumeName := "storage-credentials-" + entry.Name
+			entry.CredentialsDir = path.Join("/credentials-extra", entry.Name)
+			podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+				Name: volumeName,
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: dest.SecretName,
+					},
+				},
+			})
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      volumeName,
+				MountPath: entry.CredentialsDir,
+			})
+		}
+
+		config = append(config, entry)
+	}
+
+	value, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  "STORAGE_DESTINATIONS",
+		Value: string(value),
+	})
+	return nil
+}
+
+// Helper function to record the per-destination outcome of a finished
+// backup job. Destinations missing from the report take the job's outcome.
+// Returns the names of the destinations that failed.
+func recordDestinationStatuses(dbBackup *dbbackupv1alpha1.DatabaseBackup, report *backupReport, jobSucceeded bool) []string {
+	reported := map[string]destinationReport{}
+	if report != nil {
+		for _, dest := range report.Destinations {
+			reported[dest.Name] = dest
+		}
+	}
+
+	previous := map[string]dbbackupv1alpha1.DestinationStatus{}
+	for _, status := range dbBackup.Status.Destinations {
+		previous[status.Name] = status
+	}
+
+	now := metav1.Now()
+	var statuses []dbbackupv1alpha1.DestinationStatus
+	var failed []string
+	for _, name := range destinationNames(&dbBackup.Spec) {
+		status := previous[name]
+		status.Name = name
+		status.Message = ""
+
+		succeeded := jobSucceeded
+		if dest, ok := reported[name]; ok {
+			succeeded = dest.Status == "Succeeded"
+			status.Message = dest.Message
+		}
+		if succeeded {
+			status.LastBackupStatus = "Succeeded"
+			status.LastSuccessfulBackup = &now
+		} else {
+			status.LastBackupStatus = "Failed"
+			failed = append(failed, name)
+		}
+		statuses = append(statuses, status)
+	}
+	dbBackup.Status.Destinations = statuses
+	return failed
+}
+
+// Helper function to describe the destinations a backup failed to reach
+func describeFailedDestinations(failed []string) string {
+	return fmt.Sprintf("Upload failed for storage destinations: %s", strings.Join(failed, ", "))
+}
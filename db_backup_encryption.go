@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+const (
+	// keyIDAnnotation on an encryption key secret names the key version it holds
+	keyIDAnnotation = "db.example.io/key-id"
+
+	// encryptionKeyIDAnnotation records on a backup Job which key encrypted it
+	encryptionKeyIDAnnotation = "db.example.io/encryption-key-id"
+
+	// encryptionKeyDir is where the encryption key secret is mounted
+	encryptionKeyDir = "/encryption"
+)
+
+// Helper function to get the secret key holding the encryption key
+func encryptionKeyName(dbBackup *dbbackupv1alpha1.DatabaseBackup) string {
+	if key := dbBackup.Spec.Encryption.Key; key != "" {
+		return key
+	}
+	return dbbackupv1alpha1.DefaultEncryptionKey
+}
+
+// Helper function to get the name of the Secret pinning the key with the
+// given id for a DatabaseBackup. Key ids needn't be valid names, so the name
+// is derived from a hash of the id
+func encryptionKeyCopyName(dbBackup *dbbackupv1alpha1.DatabaseBackup, keyID string) string {
+	sum := sha256.Sum256([]byte(keyID))
+	return fmt.Sprintf("%s-key-%s", dbBackup.Name, hex.EncodeToString(sum[:])[:10])
+}
+
+// Helper function to get the id of the current encryption key and pin the
+// key in an immutable Secret of its own. Backup pods mount the pinned copy
+// rather than the source secret, so the key an artifact is encrypted with is
+// always the one its key id names, even if the source secret is rotated
+// before the pod starts. Restores of older backups keep working from their
+// own copy after any number of rotations.
+//
+// The id is the source secret's db.example.io/key-id annotation or, without
+// one, a fingerprint of the key itself
+func (r *DatabaseBackupReconciler) currentEncryptionKeyID(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (string, error) {
+	var secret corev1.Secret
+	secretName := types.NamespacedName{Name: dbBackup.Spec.Encryption.SecretName, Namespace: dbBackup.Namespace}
+	if err := r.apiReader().Get(ctx, secretName, &secret); err != nil {
+		return "", fmt.Errorf("failed to get encryption key secret %s: %w", secretName, err)
+	}
+	key := encryptionKeyName(dbBackup)
+	material, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("encryption key secret %s has no key %q", secretName, key)
+	}
+
+	keyID := secret.Annotations[keyIDAnnotation]
+	if keyID == "" {
+		sum := sha256.Sum256(material)
+		keyID = "sha256:" + hex.EncodeToString(sum[:])[:16]
+	}
+
+	immutable := true
+	pinned := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        encryptionKeyCopyName(dbBackup, keyID),
+			Namespace:   dbBackup.Namespace,
+			Labels:      map[string]string{backupNameLabel: dbBackup.Name, watchedSecretLabel: "true"},
+			Annotations: map[string]string{keyIDAnnotation: keyID},
+		},
+		Type:      corev1.SecretTypeOpaque,
+		Immutable: &immutable,
+		Data:      map[string][]byte{key: material},
+	}
+	if err := ctrl.SetControllerReference(dbBackup, pinned, r.Scheme); err != nil {
+		return "", err
+	}
+	if err := r.Create(ctx, pinned); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return "", fmt.Errorf("failed to pin encryption key %s: %w", keyID, err)
+		}
+		var existing corev1.Secret
+		if err := r.apiReader().Get(ctx, types.NamespacedName{Name: pinned.Name, Namespace: pinned.Namespace}, &existing); err != nil {
+			return "", err
+		}
+		if !bytes.Equal(existing.Data[key], material) {
+			return "", fmt.Errorf("encryption key id %q is already used for a different key, give the new key a new id", keyID)
+		}
+	}
+	return keyID, nil
+}
+
+// Helper function to mount the pinned encryption key with the given id into
+// a backup pod and tell the image which key version it is
+func addEncryptionKey(podSpec *corev1.PodSpec, dbBackup *dbbackupv1alpha1.DatabaseBackup, keyID string) {
+	key := encryptionKeyName(dbBackup)
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "encryption-key",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: encryptionKeyCopyName(dbBackup, keyID),
+			},
+		},
+	})
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      "encryption-key",
+		MountPath: encryptionKeyDir,
+		ReadOnly:  true,
+	})
+	container.Env = append(container.Env,
+		corev1.EnvVar{
+			Name:  "ENCRYPTION_KEY_FILE",
+			Value: path.Join(encryptionKeyDir, key),
+		},
+		corev1.EnvVar{
+			Name:  "ENCRYPTION_KEY_ID",
+			Value: keyID,
+		},
+	)
+}
@@ -0,0 +1,319 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=core,resources=pods/exec,verbs=create
+
+// execOutputLimit is how much of an exec backup's stderr is kept for status
+const execOutputLimit = 1024
+
+// execPollInterval is how often a running exec backup is re-checked, as a
+// fallback for completion events dropped from a full queue
+const execPollInterval = 30 * time.Second
+
+// PodExecutor runs a command in a pod's container
+type PodExecutor interface {
+	Exec(ctx context.Context, namespace, pod, container string, command []string, stdout, stderr io.Writer) error
+}
+
+// remotePodExecutor runs commands through the pods/exec subresource
+type remotePodExecutor struct {
+	config    *rest.Config
+	clientset kubernetes.Interface
+}
+
+// NewPodExecutor returns a PodExecutor that uses the API server's exec endpoint
+func NewPodExecutor(config *rest.Config) (PodExecutor, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &remotePodExecutor{config: config, clientset: clientset}, nil
+}
+
+func (e *remotePodExecutor) Exec(ctx context.Context, namespace, pod, container string, command []string, stdout, stderr io.Writer) error {
+	req := e.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(e.config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	return executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+}
+
+// execResult is the outcome of a finished exec backup
+type execResult struct {
+	err       error
+	stderr    string
+	cancelled bool
+}
+
+// execTracker keeps track of exec backups running in the background. It is
+// process-local, so backups in flight are lost if the controller restarts.
+// The zero value is ready to use.
+type execTracker struct {
+	mu      sync.Mutex
+	running map[types.NamespacedName]context.CancelFunc
+	results map[types.NamespacedName]execResult
+}
+
+func (t *execTracker) start(key types.NamespacedName, cancel context.CancelFunc) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.running[key]; ok {
+		return false
+	}
+	if t.running == nil {
+		t.running = map[types.NamespacedName]context.CancelFunc{}
+	}
+	t.running[key] = cancel
+	delete(t.results, key)
+	return true
+}
+
+// cancel aborts a running exec backup. Its result is still reported through
+// finish once the exec has returned
+func (t *execTracker) cancel(key types.NamespacedName) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cancel, ok := t.running[key]
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+func (t *execTracker) finish(key types.NamespacedName, result execResult) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.running, key)
+	if t.results == nil {
+		t.results = map[types.NamespacedName]execResult{}
+	}
+	t.results[key] = result
+}
+
+// peek returns the result of a finished exec backup, and whether it is
+// still running when there is none. The result is kept until drop, so it
+// can be recorded again if storing it fails
+func (t *execTracker) peek(key types.NamespacedName) (*execResult, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if result, ok := t.results[key]; ok {
+		return &result, false
+	}
+	_, running := t.running[key]
+	return nil, running
+}
+
+// drop forgets the result of a finished exec backup once it is stored
+func (t *execTracker) drop(key types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.results, key)
+}
+
+// limitedBuffer keeps the last max bytes written to it
+type limitedBuffer struct {
+	buf bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.buf.Write(p)
+	if extra := b.buf.Len() - b.max; extra > 0 {
+		b.buf.Next(extra)
+	}
+	return len(p), nil
+}
+
+// Helper function to check if a DatabaseBackup runs in the target pod
+func isExecMode(dbBackup *dbbackupv1alpha1.DatabaseBackup) bool {
+	return dbBackup.Spec.Mode == "exec"
+}
+
+// Helper function to start an exec backup in a ready target pod. The
+// command runs in the background; its outcome is picked up by
+// syncActiveExec once the reconcile it triggers comes in. Returns the pod name.
+func (r *DatabaseBackupReconciler) startExecBackup(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (string, error) {
+	if r.Executor == nil {
+		return "", fmt.Errorf("exec backups are not enabled on this controller")
+	}
+	execSpec := dbBackup.Spec.Exec
+
+	pods, err := r.findTargetPods(ctx, dbBackup)
+	if err != nil {
+		return "", err
+	}
+	pod, err := selectTargetPod(pods, dbBackup.Spec.PreferRole)
+	if err != nil {
+		return "", err
+	}
+
+	container := execSpec.Container
+	if container == "" {
+		container = pod.Spec.Containers[0].Name
+	}
+
+	timeout := dbbackupv1alpha1.DefaultExecTimeout
+	if execSpec.Timeout != nil {
+		timeout = execSpec.Timeout.Duration
+	}
+
+	// Exec can't set environment variables, so pass the backup settings through env(1)
+	dest := dbBackup.Spec.StorageDestination
+	command := []string{"env",
+		"DB_TYPE=" + dbBackup.Spec.DatabaseType,
+		"STORAGE_TYPE=" + dest.Type,
+		"BUCKET=" + dest.Bucket,
+		"STORAGE_PATH=" + dest.Path,
+	}
+	if dest.Endpoint != "" {
+		command = append(command, "STORAGE_ENDPOINT="+dest.Endpoint)
+	}
+	if dbBackup.Spec.BandwidthLimit != "" {
+		command = append(command, "RATE_LIMIT="+dbBackup.Spec.BandwidthLimit)
+	}
+	if dbBackup.Spec.Workers > 0 {
+		command = append(command, "WORKERS="+strconv.Itoa(int(dbBackup.Spec.Workers)))
+	}
+	command = append(command, "FILE_EXTENSION="+artifactExtension(dbBackup))
+	if dbBackup.Spec.StreamToStorage != nil && *dbBackup.Spec.StreamToStorage {
+		command = append(command, "STREAM=true")
+	}
+	if len(dbBackup.Spec.BackupTags) > 0 {
+		tags, err := json.Marshal(dbBackup.Spec.BackupTags)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode backup tags: %w", err)
+		}
+		command = append(command, "BACKUP_TAGS="+string(tags))
+	}
+	if len(dbBackup.Spec.LifecycleRules) > 0 {
+		rules, err := json.Marshal(dbBackup.Spec.LifecycleRules)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode lifecycle rules: %w", err)
+		}
+		command = append(command, "LIFECYCLE_RULES="+string(rules))
+	}
+	command = append(command, execSpec.Command...)
+
+	key := types.NamespacedName{Name: dbBackup.Name, Namespace: dbBackup.Namespace}
+	execCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	if !r.execs.start(key, cancel) {
+		cancel()
+		return "", fmt.Errorf("an exec backup is already running")
+	}
+
+	notify := &dbbackupv1alpha1.DatabaseBackup{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}
+	podName := pod.Name
+	go func() {
+		defer cancel()
+
+		// The command uploads the artifact itself, so its stdout isn't kept
+		stderr := &limitedBuffer{max: execOutputLimit}
+		err := r.Executor.Exec(execCtx, key.Namespace, podName, container, command, io.Discard, stderr)
+		if execCtx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+		cancelled := execCtx.Err() == context.Canceled
+		r.execs.finish(key, execResult{err: err, stderr: stderr.buf.String(), cancelled: cancelled})
+
+		// Wake the reconciler up to record the outcome. The send never
+		// blocks; if the queue is full the result is picked up by the
+		// execPollInterval requeue instead
+		select {
+		case r.execEvents <- event.GenericEvent{Object: notify}:
+		default:
+			log.FromContext(ctx).Info("Exec event queue full, leaving the result to the next poll", "pod", podName)
+		}
+	}()
+
+	log.FromContext(ctx).Info("Started exec backup", "pod", podName, "container", container)
+	return podName, nil
+}
+
+// Helper function to abort the running exec backup on request. Cancelling
+// closes the exec stream, which ends the command along with its session in
+// most container runtimes; the outcome is recorded by syncActiveExec as
+// usual once the exec has returned
+func (r *DatabaseBackupReconciler) cancelExecBackup(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) {
+	key := types.NamespacedName{Name: dbBackup.Name, Namespace: dbBackup.Namespace}
+	if !r.execs.cancel(key) {
+		return
+	}
+	log.FromContext(ctx).Info("Cancelled exec backup on request", "pod", dbBackup.Status.ActiveExec)
+	r.Recorder.Eventf(dbBackup, corev1.EventTypeNormal, "BackupCancelled", "Cancelled exec backup in pod %s on request", dbBackup.Status.ActiveExec)
+}
+
+// Helper function to record the outcome of the active exec backup once it
+// has finished. Returns true if the status changed.
+func (r *DatabaseBackupReconciler) syncActiveExec(dbBackup *dbbackupv1alpha1.DatabaseBackup) bool {
+	key := types.NamespacedName{Name: dbBackup.Name, Namespace: dbBackup.Namespace}
+	result, running := r.execs.peek(key)
+	if running {
+		return false
+	}
+
+	switch {
+	case result != nil && result.cancelled:
+		dbBackup.Status.LastBackupStatus = "Cancelled"
+	case result == nil:
+		dbBackup.Status.LastBackupStatus = "Failed"
+		dbBackup.Status.FailureReason = "Exec backup was interrupted by a controller restart"
+		dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureJobFailed
+		recordBackupFailure(dbBackup)
+	case result.err != nil:
+		dbBackup.Status.LastBackupStatus = "Failed"
+		dbBackup.Status.FailureReason = fmt.Sprintf("Exec backup in pod %s failed: %v", dbBackup.Status.ActiveExec, result.err)
+		if result.stderr != "" {
+			dbBackup.Status.FailureReason += ": " + result.stderr
+		}
+		dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureJobFailed
+		recordBackupFailure(dbBackup)
+	default:
+		now := metav1.Now()
+		dbBackup.Status.LastSuccessfulBackup = &now
+		dbBackup.Status.LastBackupStatus = "Succeeded"
+		dbBackup.Status.FailureReason = ""
+		dbBackup.Status.FailureCode = ""
+		dbBackup.Status.ConsecutiveFailures = 0
+	}
+
+	finishManualBackup(dbBackup, dbBackup.Status.ActiveExec)
+	dbBackup.Status.ActiveExec = ""
+	return true
+}
@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// fakeExecutor records the exec it was asked to run and fails it with err
+type fakeExecutor struct {
+	mu        sync.Mutex
+	pod       string
+	container string
+	command   []string
+	stderr    string
+	err       error
+}
+
+func (e *fakeExecutor) Exec(ctx context.Context, namespace, pod, container string, command []string, stdout, stderr io.Writer) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.pod, e.container, e.command = pod, container, command
+	io.WriteString(stderr, e.stderr)
+	return e.err
+}
+
+func databasePod(name, role string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{"app": "db", dbbackupv1alpha1.DefaultRoleLabelKey: role},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "postgres"}}},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+// Helper function to wait for the exec backup started for key to finish
+func waitForExec(t *testing.T, r *DatabaseBackupReconciler, key types.NamespacedName) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, running := r.execs.peek(key); !running {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("exec backup didn't finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestExecBackup(t *testing.T) {
+	execBackup := func() *dbbackupv1alpha1.DatabaseBackup {
+		return &dbbackupv1alpha1.DatabaseBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+			Spec: dbbackupv1alpha1.DatabaseBackupSpec{
+				DatabaseType:       "postgres",
+				Mode:               "exec",
+				DatabaseSelector:   metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+				PreferRole:         &dbbackupv1alpha1.PreferRoleSpec{Value: "replica"},
+				StorageDestination: dbbackupv1alpha1.StorageDestinationSpec{Type: "s3", Bucket: "backups"},
+				Exec:               &dbbackupv1alpha1.ExecSpec{Command: []string{"/backup.sh"}},
+			},
+		}
+	}
+	pods := []runtime.Object{
+		databasePod("db-0", "primary", true),
+		databasePod("db-1", "replica", false),
+		databasePod("db-2", "replica", true),
+	}
+
+	tests := []struct {
+		name          string
+		err           error
+		stderr        string
+		wantStatus    string
+		wantReason    string
+		wantFailCount int32
+	}{
+		{
+			name:       "succeeds",
+			wantStatus: "Succeeded",
+		},
+		{
+			name:          "fails",
+			err:           errors.New("command terminated with exit code 1"),
+			stderr:        "pg_dump: connection refused",
+			wantStatus:    "Failed",
+			wantReason:    "Exec backup in pod db-2 failed: command terminated with exit code 1: pg_dump: connection refused",
+			wantFailCount: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbBackup := execBackup()
+			r := newTestReconciler(t, pods...)
+			executor := &fakeExecutor{err: tt.err, stderr: tt.stderr}
+			r.Executor = executor
+
+			podName, err := r.startExecBackup(context.Background(), dbBackup)
+			if err != nil {
+				t.Fatalf("startExecBackup: %v", err)
+			}
+			// The only ready pod with the preferred role
+			if podName != "db-2" {
+				t.Errorf("started in pod %s, want db-2", podName)
+			}
+			key := types.NamespacedName{Name: "db", Namespace: "default"}
+			waitForExec(t, r, key)
+
+			executor.mu.Lock()
+			if executor.pod != "db-2" || executor.container != "postgres" {
+				t.Errorf("exec ran in %s/%s, want db-2/postgres", executor.pod, executor.container)
+			}
+			if last := executor.command[len(executor.command)-1]; last != "/backup.sh" {
+				t.Errorf("command ends with %q, want the user's command", last)
+			}
+			executor.mu.Unlock()
+
+			// A failed status write leaves the result for the next reconcile
+			for attempt := 1; attempt <= 2; attempt++ {
+				dbBackup.Status = dbbackupv1alpha1.DatabaseBackupStatus{ActiveExec: podName, LastBackupStatus: "Running"}
+				if !r.syncActiveExec(dbBackup) {
+					t.Fatalf("attempt %d: syncActiveExec reported no change", attempt)
+				}
+				status := dbBackup.Status
+				if status.ActiveExec != "" {
+					t.Errorf("attempt %d: ActiveExec = %q, want it cleared", attempt, status.ActiveExec)
+				}
+				if status.LastBackupStatus != tt.wantStatus {
+					t.Errorf("attempt %d: LastBackupStatus = %q, want %q", attempt, status.LastBackupStatus, tt.wantStatus)
+				}
+				if status.FailureReason != tt.wantReason {
+					t.Errorf("attempt %d: FailureReason = %q, want %q", attempt, status.FailureReason, tt.wantReason)
+				}
+				if status.ConsecutiveFailures != tt.wantFailCount {
+					t.Errorf("attempt %d: ConsecutiveFailures = %d, want %d", attempt, status.ConsecutiveFailures, tt.wantFailCount)
+				}
+				if tt.err != nil && status.FailureCode != dbbackupv1alpha1.FailureJobFailed {
+					t.Errorf("attempt %d: FailureCode = %q, want %q", attempt, status.FailureCode, dbbackupv1alpha1.FailureJobFailed)
+				}
+			}
+
+			// Once stored the result is gone, as after a controller restart
+			r.execs.drop(key)
+			dbBackup.Status = dbbackupv1alpha1.DatabaseBackupStatus{ActiveExec: podName}
+			r.syncActiveExec(dbBackup)
+			if !strings.Contains(dbBackup.Status.FailureReason, "controller restart") {
+				t.Errorf("FailureReason after drop = %q, want an interrupted backup", dbBackup.Status.FailureReason)
+			}
+		})
+	}
+}
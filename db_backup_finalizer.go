@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// orphanJobsFinalizer holds back deletion of a DatabaseBackup with
+// OrphanJobsOnDelete until its running backup Jobs have been released
+const orphanJobsFinalizer = "db.example.io/orphan-jobs"
+
+// Helper function to check if running Jobs should outlive their DatabaseBackup
+func orphansJobsOnDelete(dbBackup *dbbackupv1alpha1.DatabaseBackup) bool {
+	return dbBackup.Spec.OrphanJobsOnDelete != nil && *dbBackup.Spec.OrphanJobsOnDelete
+}
+
+// Helper function to keep the orphan-jobs finalizer in line with the spec.
+// Returns true if the object was updated.
+func (r *DatabaseBackupReconciler) syncOrphanJobsFinalizer(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (bool, error) {
+	want := orphansJobsOnDelete(dbBackup)
+	if want == controllerutil.ContainsFinalizer(dbBackup, orphanJobsFinalizer) {
+		return false, nil
+	}
+	if want {
+		controllerutil.AddFinalizer(dbBackup, orphanJobsFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(dbBackup, orphanJobsFinalizer)
+	}
+	return true, r.Update(ctx, dbBackup)
+}
+
+// Helper function to release running backup Jobs of a DatabaseBackup being
+// deleted, so garbage collection leaves them to finish, then let the
+// deletion proceed. Finished Jobs are left to be collected.
+func (r *DatabaseBackupReconciler) finalizeOrphanJobs(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) error {
+	log := log.FromContext(ctx)
+
+	jobs, err := r.listOwnedJobs(ctx, dbBackup)
+	if err != nil {
+		return err
+	}
+	for i := range jobs {
+		job := &jobs[i]
+		if isJobComplete(job) {
+			continue
+		}
+
+		var refs []metav1.OwnerReference
+		for _, ref := range job.OwnerReferences {
+			if ref.UID != dbBackup.UID {
+				refs = append(refs, ref)
+			}
+		}
+		job.OwnerReferences = refs
+		if err := r.Update(ctx, job); err != nil {
+			return err
+		}
+		log.Info("Orphaned running backup job so it can finish", "job_name", job.Name)
+	}
+
+	controllerutil.RemoveFinalizer(dbBackup, orphanJobsFinalizer)
+	return r.Update(ctx, dbBackup)
+}
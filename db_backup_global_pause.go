@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// globalPauseKey in the operator ConfigMap pauses every DatabaseBackup when true
+const globalPauseKey = "globalPause"
+
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+
+// Helper function to check if the operator ConfigMap pauses all backups. A
+// missing ConfigMap means not paused
+func (r *DatabaseBackupReconciler) isGloballyPaused(ctx context.Context) (bool, error) {
+	if r.ConfigMap.Name == "" {
+		return false, nil
+	}
+
+	var configMap corev1.ConfigMap
+	if err := r.Get(ctx, r.ConfigMap, &configMap); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	value, ok := configMap.Data[globalPauseKey]
+	if !ok {
+		return false, nil
+	}
+	paused, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s value %q in ConfigMap %s: %w", globalPauseKey, value, r.ConfigMap, err)
+	}
+	return paused, nil
+}
+
+// Helper function to set the GloballyPaused condition. Returns true if it changed
+func setGloballyPaused(dbBackup *dbbackupv1alpha1.DatabaseBackup, paused bool) bool {
+	condition := metav1.Condition{
+		Type:               dbbackupv1alpha1.ConditionGloballyPaused,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: dbBackup.Generation,
+		Reason:             "NotPaused",
+		Message:            "Backups are not globally paused",
+	}
+	if paused {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "GlobalPause"
+		condition.Message = fmt.Sprintf("All backups are paused by %s in the operator ConfigMap", globalPauseKey)
+	}
+
+	// Don't add the condition to objects that have never been paused
+	existing := meta.FindStatusCondition(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionGloballyPaused)
+	if existing == nil && !paused {
+		return false
+	}
+	if existing != nil && existing.Status == condition.Status {
+		return false
+	}
+	meta.SetStatusCondition(&dbBackup.Status.Conditions, condition)
+	return true
+}
+
+// Helper function to map a change to the operator ConfigMap to every DatabaseBackup
+func (r *DatabaseBackupReconciler) findBackupsForConfigMap(obj client.Object) []reconcile.Request {
+	if r.ConfigMap.Name == "" || obj.GetName() != r.ConfigMap.Name || obj.GetNamespace() != r.ConfigMap.Namespace {
+		return nil
+	}
+
+	var backups dbbackupv1alpha1.DatabaseBackupList
+	if err := r.List(context.Background(), &backups, r.shardSelector()); err != nil {
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(backups.Items))
+	for _, dbBackup := range backups.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Name:      dbBackup.Name,
+			Namespace: dbBackup.Namespace,
+		}})
+	}
+	return requests
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// imagePullGracePeriod is how long a backup pod may keep failing to pull an
+// image before the backup is failed. Pulls of large or rate-limited images
+// can fail a few times before succeeding
+const imagePullGracePeriod = 5 * time.Minute
+
+// Helper function to find a pod of a running Job that has been failing to
+// pull one of its images for longer than imagePullGracePeriod. Returns a
+// description of the failing pull, or "" if there is none
+func (r *DatabaseBackupReconciler) findImagePullFailure(ctx context.Context, job *batchv1.Job, now time.Time) (string, error) {
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList,
+		client.InNamespace(job.Namespace),
+		client.MatchingLabels{"job-name": job.Name},
+	); err != nil {
+		return "", err
+	}
+
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != corev1.PodPending || now.Sub(pod.CreationTimestamp.Time) < imagePullGracePeriod {
+			continue
+		}
+		statuses := append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			waiting := status.State.Waiting
+			if waiting == nil || (waiting.Reason != "ImagePullBackOff" && waiting.Reason != "ErrImagePull") {
+				continue
+			}
+			return fmt.Sprintf("Container %s of pod %s can't pull image %s: %s", status.Name, pod.Name, status.Image, waiting.Message), nil
+		}
+	}
+	return "", nil
+}
+
+// Helper function to fail a backup whose image can't be pulled. The Job
+// would otherwise sit in ImagePullBackOff forever, holding up scheduling,
+// so it is deleted like a cancelled one
+func (r *DatabaseBackupReconciler) failImagePull(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, job *batchv1.Job, reason string) error {
+	log := log.FromContext(ctx)
+
+	if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationForeground)); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	if err := r.cancelTargetJobs(ctx, dbBackup); err != nil {
+		return err
+	}
+	log.Info("Backup image can't be pulled, failing backup", "job_name", job.Name, "reason", reason)
+	r.Recorder.Event(dbBackup, corev1.EventTypeWarning, string(dbbackupv1alpha1.FailureImagePullFailed), reason)
+
+	dbBackup.Status.LastBackupStatus = "Failed"
+	dbBackup.Status.FailureReason = reason
+	dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureImagePullFailed
+	recordBackupFailure(dbBackup)
+
+	if dbBackup.Spec.AuditLog {
+		entry := newAuditEntry(dbBackup, job.Name, job.Name, dbBackup.Status.ManualBackupRun == job.Name)
+		entry.Spec.BackupType = job.Annotations[backupTypeAnnotation]
+		if err := r.recordAuditEntry(ctx, dbBackup, entry); err != nil {
+			return err
+		}
+	}
+
+	finishManualBackup(dbBackup, job.Name)
+	dbBackup.Status.ActiveBackupJob = ""
+	dbBackup.Status.ActiveBackupJobUID = ""
+	dbBackup.Status.CancellingJob = job.Name
+	recordBackupProgress(dbBackup, nil)
+	return nil
+}
+
+// Helper function to delete a running restore test, re-upload, integrity
+// check or target Job whose pod can't pull its image, so the caller records
+// it as failed instead of waiting on it forever. Returns the pull failure,
+// or "" if the Job isn't stuck
+func (r *DatabaseBackupReconciler) abortImagePull(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, job *batchv1.Job) (string, error) {
+	reason, err := r.findImagePullFailure(ctx, job, time.Now())
+	if err != nil || reason == "" {
+		return "", err
+	}
+	if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationForeground)); client.IgnoreNotFound(err) != nil {
+		return "", err
+	}
+	log.FromContext(ctx).Info("Job image can't be pulled, deleting job", "job_name", job.Name, "reason", reason)
+	r.Recorder.Event(dbBackup, corev1.EventTypeWarning, string(dbbackupv1alpha1.FailureImagePullFailed), reason)
+	return reason, nil
+}
@@ -0,0 +1,265 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+const (
+	// incidentRetryInterval is how soon an incident that couldn't be
+	// opened or resolved is retried
+	incidentRetryInterval = time.Minute
+
+	// incidentRequestTimeout bounds a single request to the incident provider
+	incidentRequestTimeout = 10 * time.Second
+
+	// incidentResponseLimit caps how much of an error response is kept
+	incidentResponseLimit = 512
+)
+
+// Default endpoints of the supported incident providers
+const (
+	pagerDutyEventsURL  = "https://events.pagerduty.com/v2/enqueue"
+	opsgenieAlertsURL   = "https://api.opsgenie.com/v2/alerts"
+	opsgenieEUAlertsURL = "https://api.eu.opsgenie.com/v2/alerts"
+)
+
+// IncidentEvent opens or resolves an incident with an incident provider
+type IncidentEvent struct {
+	// Provider is pagerduty or opsgenie
+	Provider string
+
+	// Endpoint overrides the provider's default API endpoint. It must be
+	// one of the endpoints the notifier allows
+	Endpoint string
+
+	// IntegrationKey authenticates to the provider
+	IntegrationKey string
+
+	// DedupKey identifies the incident across open and resolve
+	DedupKey string
+
+	// Summary describes the incident
+	Summary string
+
+	// Source is the DatabaseBackup the incident is about, as namespace/name
+	Source string
+
+	// Resolve resolves the incident instead of opening it
+	Resolve bool
+}
+
+// IncidentNotifier delivers incident events to an incident provider
+type IncidentNotifier interface {
+	Send(ctx context.Context, event IncidentEvent) error
+}
+
+// httpIncidentNotifier talks to the providers' HTTP APIs
+type httpIncidentNotifier struct {
+	client  *http.Client
+	allowed map[string]bool
+}
+
+// NewIncidentNotifier returns an IncidentNotifier for PagerDuty's Events API
+// v2 and Opsgenie's Alert API. Endpoint overrides are limited to the
+// providers' public endpoints and allowedEndpoints, since anyone able to
+// create a DatabaseBackup could otherwise have the operator send requests,
+// with an integration key, to any address it can reach
+func NewIncidentNotifier(allowedEndpoints []string) IncidentNotifier {
+	allowed := map[string]bool{
+		pagerDutyEventsURL:  true,
+		opsgenieAlertsURL:   true,
+		opsgenieEUAlertsURL: true,
+	}
+	for _, endpoint := range allowedEndpoints {
+		allowed[strings.TrimSuffix(endpoint, "/")] = true
+	}
+	return &httpIncidentNotifier{
+		client: &http.Client{
+			Timeout: incidentRequestTimeout,
+			// A redirect would get around the endpoint allowlist
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		allowed: allowed,
+	}
+}
+
+func (n *httpIncidentNotifier) Send(ctx context.Context, event IncidentEvent) error {
+	if event.Endpoint != "" && !n.allowed[strings.TrimSuffix(event.Endpoint, "/")] {
+		return fmt.Errorf("endpoint %s is not allowed, the operator has to be started with it in --incident-endpoints", event.Endpoint)
+	}
+
+	var (
+		endpoint string
+		body     interface{}
+		header   = http.Header{}
+	)
+	switch event.Provider {
+	case "opsgenie":
+		endpoint = event.Endpoint
+		if endpoint == "" {
+			endpoint = opsgenieAlertsURL
+		}
+		header.Set("Authorization", "GenieKey "+event.IntegrationKey)
+		if event.Resolve {
+			endpoint = fmt.Sprintf("%s/%s/close?identifierType=alias", strings.TrimSuffix(endpoint, "/"), url.PathEscape(event.DedupKey))
+			body = map[string]string{"source": event.Source}
+		} else {
+			body = map[string]string{
+				"message":     truncate(event.Summary, 130),
+				"alias":       event.DedupKey,
+				"description": event.Summary,
+				"source":      event.Source,
+			}
+		}
+	default:
+		endpoint = event.Endpoint
+		if endpoint == "" {
+			endpoint = pagerDutyEventsURL
+		}
+		action := "trigger"
+		if event.Resolve {
+			action = "resolve"
+		}
+		body = map[string]interface{}{
+			"routing_key":  event.IntegrationKey,
+			"event_action": action,
+			"dedup_key":    event.DedupKey,
+			"payload": map[string]string{
+				"summary":  truncate(event.Summary, 1024),
+				"source":   event.Source,
+				"severity": "error",
+			},
+		}
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, incidentResponseLimit))
+		return fmt.Errorf("%s returned %s: %s", event.Provider, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// Helper function to cut a string to at most max bytes
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
+
+// Helper function to open an incident once consecutive failures reach the
+// threshold, and resolve it after the next success. Returns how soon to
+// retry when the provider couldn't be reached, or zero
+func (r *DatabaseBackupReconciler) syncIncident(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (time.Duration, error) {
+	log := log.FromContext(ctx)
+	spec := dbBackup.Spec.Incident
+	status := &dbBackup.Status
+
+	afterFailures := dbbackupv1alpha1.DefaultIncidentAfterFailures
+	if spec != nil && spec.AfterFailures > 0 {
+		afterFailures = spec.AfterFailures
+	}
+
+	// A resume resets the failure count without a success, and the next run
+	// having started says nothing about whether it works, so only a
+	// succeeded run resolves the incident
+	recovered := status.ConsecutiveFailures == 0 &&
+		(status.LastBackupStatus == "Succeeded" || status.LastBackupStatus == "SkippedUnchanged")
+	open := spec != nil && status.OpenIncident == "" && status.ConsecutiveFailures >= afterFailures
+	resolve := status.OpenIncident != "" && (spec == nil || recovered)
+	if r.Incidents == nil || (!open && !resolve) {
+		return 0, nil
+	}
+
+	// Without the spec there is no integration key to resolve an incident
+	// opened before incidents were turned off with
+	if spec == nil {
+		log.Info("Incidents disabled while one is open, dropping it", "dedup_key", status.OpenIncident)
+		status.OpenIncident = ""
+		return 0, nil
+	}
+
+	var secret corev1.Secret
+	if err := r.apiReader().Get(ctx, types.NamespacedName{Name: spec.SecretName, Namespace: dbBackup.Namespace}, &secret); err != nil {
+		if !errors.IsNotFound(err) {
+			return 0, err
+		}
+		r.Recorder.Eventf(dbBackup, corev1.EventTypeWarning, "IncidentFailed", "Incident secret %s not found", spec.SecretName)
+		return incidentRetryInterval, nil
+	}
+	key := spec.Key
+	if key == "" {
+		key = dbbackupv1alpha1.DefaultIncidentKey
+	}
+	integrationKey, ok := secret.Data[key]
+	if !ok {
+		r.Recorder.Eventf(dbBackup, corev1.EventTypeWarning, "IncidentFailed", "Incident secret %s has no %q key", spec.SecretName, key)
+		return incidentRetryInterval, nil
+	}
+
+	provider := spec.Provider
+	if provider == "" {
+		provider = dbbackupv1alpha1.DefaultIncidentProvider
+	}
+	event := IncidentEvent{
+		Provider:       provider,
+		Endpoint:       spec.Endpoint,
+		IntegrationKey: strings.TrimSpace(string(integrationKey)),
+		DedupKey:       status.OpenIncident,
+		Source:         dbBackup.Namespace + "/" + dbBackup.Name,
+		Resolve:        resolve,
+	}
+	if open {
+		// A fresh key per incident, so a later one isn't folded into a resolved one
+		event.DedupKey = fmt.Sprintf("db-backup/%s/%s/%d", dbBackup.Namespace, dbBackup.Name, time.Now().Unix())
+		event.Summary = fmt.Sprintf("Backup %s failed %d times in a row: %s", event.Source, status.ConsecutiveFailures, status.FailureReason)
+	}
+
+	if err := r.Incidents.Send(ctx, event); err != nil {
+		log.Error(err, "Failed to send incident event", "provider", provider, "resolve", resolve)
+		r.Recorder.Eventf(dbBackup, corev1.EventTypeWarning, "IncidentFailed", "Failed to send incident event to %s: %v", provider, err)
+		return incidentRetryInterval, nil
+	}
+
+	if open {
+		r.Recorder.Eventf(dbBackup, corev1.EventTypeWarning, "IncidentOpened", "Opened %s incident after %d consecutive failures", provider, status.ConsecutiveFailures)
+		status.OpenIncident = event.DedupKey
+	} else {
+		r.Recorder.Eventf(dbBackup, corev1.EventTypeNormal, "IncidentResolved", "Resolved %s incident after a successful backup", provider)
+		status.OpenIncident = ""
+	}
+	return 0, nil
+}
@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// incidentRequest is a request received by the fake incident provider
+type incidentRequest struct {
+	path   string
+	auth   string
+	fields map[string]interface{}
+}
+
+// Helper function to start a fake incident provider answering with status
+func incidentServer(t *testing.T, status int) (*httptest.Server, func() []incidentRequest) {
+	t.Helper()
+	var (
+		mu       sync.Mutex
+		requests []incidentRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var fields map[string]interface{}
+		if err := json.NewDecoder(req.Body).Decode(&fields); err != nil {
+			t.Errorf("decoding incident request: %v", err)
+		}
+		mu.Lock()
+		requests = append(requests, incidentRequest{path: req.URL.RequestURI(), auth: req.Header.Get("Authorization"), fields: fields})
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []incidentRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		sent := requests
+		requests = nil
+		return sent
+	}
+}
+
+func incidentBackup(provider, endpoint string) *dbbackupv1alpha1.DatabaseBackup {
+	return &dbbackupv1alpha1.DatabaseBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+		Spec: dbbackupv1alpha1.DatabaseBackupSpec{
+			Incident: &dbbackupv1alpha1.IncidentSpec{
+				Provider:      provider,
+				SecretName:    "incident-key",
+				AfterFailures: 3,
+				Endpoint:      endpoint,
+			},
+		},
+	}
+}
+
+func incidentSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "incident-key", Namespace: "default"},
+		Data:       map[string][]byte{dbbackupv1alpha1.DefaultIncidentKey: []byte("routing-key\n")},
+	}
+}
+
+func TestSyncIncident(t *testing.T) {
+	server, received := incidentServer(t, http.StatusAccepted)
+	r := newTestReconciler(t, incidentSecret())
+	r.Incidents = NewIncidentNotifier([]string{server.URL})
+	dbBackup := incidentBackup("pagerduty", server.URL)
+	ctx := context.Background()
+
+	fail := func(failures int32) {
+		dbBackup.Status.ConsecutiveFailures = failures
+		dbBackup.Status.LastBackupStatus = "Failed"
+		dbBackup.Status.FailureReason = "Backup job failed"
+	}
+	sync := func() []incidentRequest {
+		t.Helper()
+		if retry, err := r.syncIncident(ctx, dbBackup); err != nil || retry != 0 {
+			t.Fatalf("syncIncident = %s, %v, want no retry", retry, err)
+		}
+		return received()
+	}
+
+	fail(2)
+	if sent := sync(); len(sent) != 0 {
+		t.Fatalf("incident sent below the threshold: %+v", sent)
+	}
+
+	fail(3)
+	sent := sync()
+	if len(sent) != 1 {
+		t.Fatalf("%d requests at the threshold, want 1", len(sent))
+	}
+	if sent[0].fields["event_action"] != "trigger" || sent[0].fields["routing_key"] != "routing-key" {
+		t.Errorf("open request = %+v, want a trigger with the routing key", sent[0].fields)
+	}
+	dedupKey := dbBackup.Status.OpenIncident
+	if dedupKey == "" || sent[0].fields["dedup_key"] != dedupKey {
+		t.Errorf("OpenIncident = %q, request dedup_key = %v", dedupKey, sent[0].fields["dedup_key"])
+	}
+
+	// Further failures don't open another incident
+	fail(4)
+	if sent := sync(); len(sent) != 0 {
+		t.Errorf("incident opened again: %+v", sent)
+	}
+
+	dbBackup.Status.ConsecutiveFailures = 0
+	dbBackup.Status.LastBackupStatus = "Succeeded"
+	sent = sync()
+	if len(sent) != 1 {
+		t.Fatalf("%d requests on recovery, want 1", len(sent))
+	}
+	if sent[0].fields["event_action"] != "resolve" || sent[0].fields["dedup_key"] != dedupKey {
+		t.Errorf("resolve request = %+v, want a resolve of %s", sent[0].fields, dedupKey)
+	}
+	if dbBackup.Status.OpenIncident != "" {
+		t.Errorf("OpenIncident = %q after recovery, want it cleared", dbBackup.Status.OpenIncident)
+	}
+
+	if sent := sync(); len(sent) != 0 {
+		t.Errorf("incident resolved again: %+v", sent)
+	}
+}
+
+func TestSyncIncidentOpsgenie(t *testing.T) {
+	server, received := incidentServer(t, http.StatusAccepted)
+	r := newTestReconciler(t, incidentSecret())
+	r.Incidents = NewIncidentNotifier([]string{server.URL})
+	dbBackup := incidentBackup("opsgenie", server.URL)
+	ctx := context.Background()
+
+	dbBackup.Status.ConsecutiveFailures = 3
+	if _, err := r.syncIncident(ctx, dbBackup); err != nil {
+		t.Fatalf("opening: %v", err)
+	}
+	sent := received()
+	if len(sent) != 1 || sent[0].path != "/" || sent[0].auth != "GenieKey routing-key" || sent[0].fields["alias"] != dbBackup.Status.OpenIncident {
+		t.Fatalf("open requests = %+v, want one alert aliased %s", sent, dbBackup.Status.OpenIncident)
+	}
+	alias := dbBackup.Status.OpenIncident
+
+	dbBackup.Status.ConsecutiveFailures = 0
+	dbBackup.Status.LastBackupStatus = "Succeeded"
+	if _, err := r.syncIncident(ctx, dbBackup); err != nil {
+		t.Fatalf("resolving: %v", err)
+	}
+	sent = received()
+	if wantPath := "/" + url.PathEscape(alias) + "/close?identifierType=alias"; len(sent) != 1 || sent[0].path != wantPath {
+		t.Errorf("close requests = %+v, want one to %s", sent, wantPath)
+	}
+}
+
+func TestSyncIncidentEndpointNotAllowed(t *testing.T) {
+	server, received := incidentServer(t, http.StatusAccepted)
+	r := newTestReconciler(t, incidentSecret())
+	r.Incidents = NewIncidentNotifier(nil)
+	dbBackup := incidentBackup("pagerduty", server.URL)
+	dbBackup.Status.ConsecutiveFailures = 3
+
+	if retry, err := r.syncIncident(context.Background(), dbBackup); err != nil || retry != incidentRetryInterval {
+		t.Fatalf("syncIncident = %s, %v, want a retry in %s", retry, err, incidentRetryInterval)
+	}
+	if sent := received(); len(sent) != 0 {
+		t.Errorf("request sent to an endpoint not in the allowlist: %+v", sent)
+	}
+	if dbBackup.Status.OpenIncident != "" {
+		t.Errorf("OpenIncident = %q, want none", dbBackup.Status.OpenIncident)
+	}
+}
+
+func TestSyncIncidentProviderError(t *testing.T) {
+	server, received := incidentServer(t, http.StatusInternalServerError)
+	r := newTestReconciler(t, incidentSecret())
+	r.Incidents = NewIncidentNotifier([]string{server.URL})
+	dbBackup := incidentBackup("pagerduty", server.URL)
+	dbBackup.Status.ConsecutiveFailures = 3
+
+	retry, err := r.syncIncident(context.Background(), dbBackup)
+	if err != nil {
+		t.Fatalf("syncIncident: %v", err)
+	}
+	if retry != incidentRetryInterval {
+		t.Errorf("retry = %s, want %s", retry, incidentRetryInterval)
+	}
+	// Not recorded as open, so the next attempt opens it again
+	if dbBackup.Status.OpenIncident != "" {
+		t.Errorf("OpenIncident = %q after a failed request", dbBackup.Status.OpenIncident)
+	}
+	if sent := received(); len(sent) != 1 {
+		t.Errorf("%d requests, want 1", len(sent))
+	}
+}
@@ -0,0 +1,302 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/robfig/cron"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+const (
+	// integrityCheckTTL is how long finished integrity-check Jobs are kept
+	// around for inspection
+	integrityCheckTTL int32 = 3600
+
+	// integrityCheckPollInterval is how often a due integrity check waiting
+	// on a running one is re-checked
+	integrityCheckPollInterval = time.Minute
+)
+
+// integrityReport is what an integrity-check container writes to its
+// termination message
+type integrityReport struct {
+	Artifacts []dbbackupv1alpha1.ArtifactIntegrity `json:"artifacts"`
+}
+
+// Helper function to schedule integrity-check Jobs and record their
+// outcome. Returns how long until the next integrity check is due, or zero
+// if none is scheduled.
+func (r *DatabaseBackupReconciler) reconcileIntegrityCheck(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (time.Duration, error) {
+	log := log.FromContext(ctx)
+	integrityCheck := dbBackup.Spec.IntegrityCheck
+
+	// Check on a running integrity check
+	if dbBackup.Status.ActiveIntegrityCheckJob != "" {
+		var job batchv1.Job
+		jobName := types.NamespacedName{
+			Name:      dbBackup.Status.ActiveIntegrityCheckJob,
+			Namespace: dbBackup.Namespace,
+		}
+
+		err := r.Get(ctx, jobName, &job)
+		if err != nil && !errors.IsNotFound(err) {
+			return 0, err
+		}
+
+		pullFailure := ""
+		if err == nil && !isJobComplete(&job) {
+			if pullFailure, err = r.abortImagePull(ctx, dbBackup, &job); err != nil {
+				return 0, err
+			}
+		}
+
+		if errors.IsNotFound(err) || isJobComplete(&job) || pullFailure != "" {
+			var result *dbbackupv1alpha1.IntegrityCheckStatus
+			if errors.IsNotFound(err) {
+				result = &dbbackupv1alpha1.IntegrityCheckStatus{Result: "Failed", Message: "Integrity check job disappeared before finishing"}
+			} else if pullFailure != "" {
+				result = &dbbackupv1alpha1.IntegrityCheckStatus{Result: "Failed", Message: pullFailure}
+			} else if result, err = r.readIntegrityCheckResult(ctx, &job); err != nil {
+				return 0, err
+			}
+			result.Time = metav1.Now()
+			switch result.Result {
+			case "Failed":
+				r.Recorder.Eventf(dbBackup, corev1.EventTypeWarning, "IntegrityCheckFailed", "Integrity check %s failed: %s", job.Name, integrityFailureSummary(result))
+			case "Unknown":
+				r.Recorder.Eventf(dbBackup, corev1.EventTypeWarning, "IntegrityCheckUnknown", "Integrity check %s verified nothing: %s", job.Name, result.Message)
+			}
+			dbBackup.Status.LastIntegrityCheck = result
+			dbBackup.Status.ActiveIntegrityCheckJob = ""
+		}
+	}
+
+	if integrityCheck == nil {
+		return 0, nil
+	}
+
+	schedule, err := cron.ParseStandard(integrityCheck.Schedule)
+	if err != nil {
+		// Rejected by validateSpec before getting here
+		log.Error(err, "Failed to parse integrity check schedule", "schedule", integrityCheck.Schedule)
+		return 0, nil
+	}
+
+	// Start the schedule from now the first time round, and again when it
+	// was edited
+	if dbBackup.Status.NextIntegrityCheck == nil || dbBackup.Status.IntegrityCheckSchedule != integrityCheck.Schedule {
+		next := metav1.NewTime(schedule.Next(time.Now()))
+		dbBackup.Status.NextIntegrityCheck = &next
+		dbBackup.Status.IntegrityCheckSchedule = integrityCheck.Schedule
+	}
+
+	if !isTimeToBackup(dbBackup.Status.NextIntegrityCheck) {
+		return time.Until(dbBackup.Status.NextIntegrityCheck.Time), nil
+	}
+	if dbBackup.Status.ActiveIntegrityCheckJob != "" {
+		return integrityCheckPollInterval, nil
+	}
+
+	// Nothing to verify before the first successful backup
+	if dbBackup.Status.LastSuccessfulBackup != nil {
+		job, err := r.createIntegrityCheckJob(ctx, dbBackup, dbBackup.Status.NextIntegrityCheck.Time)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create integrity check job: %w", err)
+		}
+		dbBackup.Status.ActiveIntegrityCheckJob = job.Name
+	} else {
+		log.Info("No successful backup to verify, skipping integrity check")
+	}
+
+	next := metav1.NewTime(schedule.Next(time.Now()))
+	dbBackup.Status.NextIntegrityCheck = &next
+	return time.Until(next.Time), nil
+}
+
+// Helper function to turn a finished integrity-check Job into its result.
+// A job that failed without reporting, or reported a failed artifact, failed
+// the check. One that succeeded without verifying any artifact is Unknown
+func (r *DatabaseBackupReconciler) readIntegrityCheckResult(ctx context.Context, job *batchv1.Job) (*dbbackupv1alpha1.IntegrityCheckStatus, error) {
+	result := &dbbackupv1alpha1.IntegrityCheckStatus{Result: "Passed"}
+
+	message, err := r.latestTerminationMessage(ctx, job, "integrity-check")
+	if err != nil {
+		return nil, err
+	}
+	if message != "" {
+		var report integrityReport
+		if err := json.Unmarshal([]byte(message), &report); err != nil {
+			result.Message = fmt.Sprintf("Unparseable integrity report: %v", err)
+		}
+		result.Artifacts = report.Artifacts
+	}
+
+	for _, artifact := range result.Artifacts {
+		if artifact.Result != "Pass" {
+			result.Result = "Failed"
+		}
+	}
+	if !isJobSuccessful(job) {
+		result.Result = "Failed"
+		if result.Message == "" && len(result.Artifacts) == 0 {
+			result.Message = "Integrity check job failed without reporting results"
+		}
+	} else if len(result.Artifacts) == 0 && result.Result == "Passed" {
+		result.Result = "Unknown"
+		if result.Message == "" {
+			result.Message = "Integrity check found no artifacts to verify"
+		}
+	}
+	return result, nil
+}
+
+// Helper function to describe a failed integrity check in one line
+func integrityFailureSummary(result *dbbackupv1alpha1.IntegrityCheckStatus) string {
+	var failed []string
+	for _, artifact := range result.Artifacts {
+		if artifact.Result != "Pass" {
+			failed = append(failed, artifact.Name)
+		}
+	}
+	if len(failed) == 0 {
+		return result.Message
+	}
+	return fmt.Sprintf("%d artifact(s) failed verification: %v", len(failed), failed)
+}
+
+// Helper function to create a Job that downloads the most recent artifacts,
+// decompresses them and checks them against their recorded checksums
+func (r *DatabaseBackupReconciler) createIntegrityCheckJob(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, scheduledTime time.Time) (*batchv1.Job, error) {
+	integrityCheck := dbBackup.Spec.IntegrityCheck
+
+	image := integrityCheck.Image
+	if image == "" {
+		image = getIntegrityCheckImage(dbBackup.Spec.DatabaseType)
+	}
+	maxArtifacts := integrityCheck.MaxArtifacts
+	if maxArtifacts == 0 {
+		maxArtifacts = dbbackupv1alpha1.DefaultIntegrityCheckArtifacts
+	}
+
+	backoffLimit := int32(0)
+	ttl := integrityCheckTTL
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-integrity-%s", dbBackup.Name, scheduledTime.UTC().Format("200601021504")),
+			Namespace: dbBackup.Namespace,
+			Labels: map[string]string{
+				"app":           "db-backup-operator",
+				backupNameLabel: dbBackup.Name,
+				jobKindLabel:    "integrity-check",
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:    corev1.RestartPolicyNever,
+					ImagePullSecrets: dbBackup.Spec.ImagePullSecrets,
+					Containers: []corev1.Container{
+						{
+							Name:  "integrity-check",
+							Image: image,
+							Env: []corev1.EnvVar{
+								{
+									Name:  "DB_TYPE",
+									Value: dbBackup.Spec.DatabaseType,
+								},
+								{
+									Name:  "STORAGE_TYPE",
+									Value: dbBackup.Spec.StorageDestination.Type,
+								},
+								{
+									Name:  "BUCKET",
+									Value: dbBackup.Spec.StorageDestination.Bucket,
+								},
+								{
+									Name:  "PATH",
+									Value: dbBackup.Spec.StorageDestination.Path,
+								},
+								{
+									Name:  "MAX_ARTIFACTS",
+									Value: strconv.Itoa(int(maxArtifacts)),
+								},
+								{
+									Name:  "FILE_EXTENSION",
+									Value: artifactExtension(dbBackup),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	container := &job.Spec.Template.Spec.Containers[0]
+	if dbBackup.Status.LastArtifactSHA256 != "" {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "EXPECTED_SHA256",
+			Value: dbBackup.Status.LastArtifactSHA256,
+		})
+	}
+
+	// The manifest has the checksums of older artifacts
+	if manifest := dbBackup.Spec.Manifest; manifest != nil {
+		manifestPath := manifest.Path
+		if manifestPath == "" {
+			manifestPath = dbbackupv1alpha1.DefaultManifestPath
+		}
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "MANIFEST_PATH",
+			Value: path.Join(dbBackup.Spec.StorageDestination.Path, manifestPath),
+		})
+	}
+
+	addStorageVolumes(&job.Spec.Template.Spec, r.withStorageDefaults(dbBackup))
+	r.addImagePullSecret(&job.Spec.Template.Spec, dbBackup)
+
+	// Encrypted artifacts have to be decrypted before they can be decompressed
+	if dbBackup.Spec.Encryption != nil {
+		addEncryptionKey(&job.Spec.Template.Spec, dbBackup, dbBackup.Status.LastBackupKeyID)
+	}
+
+	if err := ctrl.SetControllerReference(dbBackup, job, r.Scheme); err != nil {
+		return nil, err
+	}
+
+	if err := r.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Helper function to get the integrity-check image based on DB type
+func getIntegrityCheckImage(dbType string) string {
+	switch dbType {
+	case "postgres":
+		return "ghcr.io/example/postgres-integrity-check:latest"
+	case "mysql":
+		return "ghcr.io/example/mysql-integrity-check:latest"
+	case "mongodb":
+		return "ghcr.io/example/mongodb-integrity-check:latest"
+	case "sqlite":
+		return "ghcr.io/example/sqlite-integrity-check:latest"
+	default:
+		return "ghcr.io/example/generic-integrity-check:latest"
+	}
+}
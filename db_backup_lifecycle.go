@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// Helper function to check that lifecycle rules are in increasing age order,
+// transition to a storage class, and end in at most one delete
+func validateLifecycleRules(spec *dbbackupv1alpha1.DatabaseBackupSpec) error {
+	if len(spec.LifecycleRules) == 0 {
+		return nil
+	}
+	if spec.StorageDestination.Type == "pvc" {
+		return fmt.Errorf("lifecycleRules require an object storage destination")
+	}
+
+	var lastAfter int64
+	for i, rule := range spec.LifecycleRules {
+		if rule.AfterHours <= lastAfter {
+			return fmt.Errorf("lifecycleRules[%d]: afterHours must be greater than the previous rule's", i)
+		}
+		lastAfter = rule.AfterHours
+
+		switch rule.Action {
+		case dbbackupv1alpha1.LifecycleTransition:
+			if rule.StorageClass == "" {
+				return fmt.Errorf("lifecycleRules[%d]: storageClass is required for Transition", i)
+			}
+		case dbbackupv1alpha1.LifecycleDelete:
+			if rule.StorageClass != "" {
+				return fmt.Errorf("lifecycleRules[%d]: storageClass is only valid for Transition", i)
+			}
+			if i != len(spec.LifecycleRules)-1 {
+				return fmt.Errorf("lifecycleRules[%d]: Delete must be the last rule", i)
+			}
+		default:
+			return fmt.Errorf("lifecycleRules[%d]: unknown action %q", i, rule.Action)
+		}
+	}
+	return nil
+}
+
+// Helper function to get how long backups are kept. Lifecycle rules
+// supersede BackupRetention; without a Delete rule backups are kept forever,
+// which is reported as false
+func backupRetention(dbBackup *dbbackupv1alpha1.DatabaseBackup) (time.Duration, bool) {
+	if rules := dbBackup.Spec.LifecycleRules; len(rules) > 0 {
+		last := rules[len(rules)-1]
+		if last.Action != dbbackupv1alpha1.LifecycleDelete {
+			return 0, false
+		}
+		return time.Duration(last.AfterHours) * time.Hour, true
+	}
+
+	retention := dbBackup.Spec.BackupRetention
+	if retention == 0 {
+		retention = dbbackupv1alpha1.DefaultBackupRetention
+	}
+	return time.Duration(retention) * time.Hour, true
+}
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"unicode/utf8"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=core,resources=pods/log,verbs=get
+
+const (
+	// DefaultFailureLogLines is how many log lines of a failed backup pod are
+	// captured when FailureLogLines is unset
+	DefaultFailureLogLines int64 = 20
+
+	// failureLogLimit caps the excerpt stored in status
+	failureLogLimit = 2048
+
+	// successMarkerLogLines is how many final log lines are searched for
+	// SuccessLogPattern. Backup tools keep logging after the dump itself
+	// finished (upload progress, cleanup, summaries), so the marker can be
+	// well before the last line
+	successMarkerLogLines int64 = 500
+)
+
+// PodLogReader fetches the tail of a container's logs
+type PodLogReader interface {
+	TailLogs(ctx context.Context, namespace, pod, container string, lines int64) (string, error)
+}
+
+// clientsetLogReader reads logs through the pods/log subresource
+type clientsetLogReader struct {
+	clientset kubernetes.Interface
+}
+
+// NewPodLogReader returns a PodLogReader backed by the API server
+func NewPodLogReader(config *rest.Config) (PodLogReader, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &clientsetLogReader{clientset: clientset}, nil
+}
+
+// TailLogs takes the last lines of the log. LimitBytes isn't used, since it
+// keeps the first bytes of the tail and would cut off its end
+func (l *clientsetLogReader) TailLogs(ctx context.Context, namespace, pod, container string, lines int64) (string, error) {
+	stream, err := l.clientset.CoreV1().Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container: container,
+		TailLines: &lines,
+	}).Stream(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	logs, err := io.ReadAll(stream)
+	return string(logs), err
+}
+
+// Helper function to check that every succeeded pod of a backup Job logged
+// SuccessLogPattern. Returns an error describing why the backup can't be
+// trusted when the marker is missing or the logs can't be read.
+func (r *DatabaseBackupReconciler) checkSuccessMarker(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, job *batchv1.Job) error {
+	pattern, err := regexp.Compile(dbBackup.Spec.SuccessLogPattern)
+	if err != nil {
+		return fmt.Errorf("invalid success log pattern: %w", err)
+	}
+	if r.LogReader == nil {
+		return fmt.Errorf("success marker not checked, the controller can't read pod logs")
+	}
+
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList,
+		client.InNamespace(job.Namespace),
+		client.MatchingLabels{"job-name": job.Name},
+	); err != nil {
+		return fmt.Errorf("success marker not checked: %w", err)
+	}
+
+	checked := 0
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		logs, err := r.LogReader.TailLogs(ctx, pod.Namespace, pod.Name, "backup", successMarkerLogLines)
+		if err != nil {
+			return fmt.Errorf("success marker not checked, failed to read logs of pod %s: %w", pod.Name, err)
+		}
+		if !pattern.MatchString(logs) {
+			return fmt.Errorf("success marker not found in the logs of pod %s", pod.Name)
+		}
+		checked++
+	}
+	if checked == 0 {
+		return fmt.Errorf("success marker not checked, no succeeded pod left to read logs from")
+	}
+	return nil
+}
+
+// Helper function to capture the log tail of a failed backup Job's most
+// recently failed pod into status. Errors are returned for logging only;
+// they must not replace the job failure itself.
+func (r *DatabaseBackupReconciler) captureFailureLog(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, job *batchv1.Job) error {
+	dbBackup.Status.LastFailureLog = ""
+
+	lines := r.FailureLogLines
+	if lines == 0 {
+		lines = DefaultFailureLogLines
+	}
+	if r.LogReader == nil || lines < 0 {
+		return nil
+	}
+
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList,
+		client.InNamespace(job.Namespace),
+		client.MatchingLabels{"job-name": job.Name},
+	); err != nil {
+		return err
+	}
+
+	var failed *corev1.Pod
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Status.Phase != corev1.PodFailed {
+			continue
+		}
+		if failed == nil || failed.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			failed = pod
+		}
+	}
+	if failed == nil {
+		return nil
+	}
+
+	logs, err := r.LogReader.TailLogs(ctx, failed.Namespace, failed.Name, "backup", lines)
+	if err != nil {
+		return err
+	}
+	dbBackup.Status.LastFailureLog = trimLogHead(logs, failureLogLimit)
+	return nil
+}
+
+// Helper function to keep the last limit bytes of a log, where the error
+// that failed the backup usually is, without splitting a UTF-8 character
+func trimLogHead(logs string, limit int) string {
+	if len(logs) <= limit {
+		return logs
+	}
+	logs = logs[len(logs)-limit:]
+	for len(logs) > 0 && !utf8.RuneStart(logs[0]) {
+		logs = logs[1:]
+	}
+	return logs
+}
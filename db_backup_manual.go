@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// backupNowAnnotation triggers an immediate backup. Set it to a new value
+// (e.g. a timestamp) for every backup wanted; a value that was already
+// acted on is ignored.
+const backupNowAnnotation = "db.example.io/backup-now"
+
+// Helper function to get the name of the Job or VolumeSnapshot of the
+// pending triggered backup. It is keyed on the trigger rather than the
+// minute, so it never collides with a scheduled run starting in the same
+// minute, and retried reconciles of one trigger resolve to the same object
+func manualRunName(dbBackup *dbbackupv1alpha1.DatabaseBackup) string {
+	sum := sha256.Sum256([]byte(dbBackup.Status.LastManualTrigger))
+	return fmt.Sprintf("%s-manual-%s", dbBackup.Name, hex.EncodeToString(sum[:])[:8])
+}
+
+// Helper function to pick up a new backup-now trigger. Returns true if the
+// status changed.
+func acceptManualTrigger(dbBackup *dbbackupv1alpha1.DatabaseBackup) bool {
+	trigger := dbBackup.Annotations[backupNowAnnotation]
+	if trigger == "" || trigger == dbBackup.Status.LastManualTrigger {
+		return false
+	}
+
+	dbBackup.Status.LastManualTrigger = trigger
+	dbBackup.Status.ManualBackupPending = true
+	dbBackup.Status.ManualBackupRun = ""
+	dbBackup.Status.LastManualBackupResult = ""
+	meta.SetStatusCondition(&dbBackup.Status.Conditions, metav1.Condition{
+		Type:               dbbackupv1alpha1.ConditionComplete,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: dbBackup.Generation,
+		Reason:             "Triggered",
+		Message:            fmt.Sprintf("Backup requested (%s)", trigger),
+	})
+	return true
+}
+
+// Helper function to record that the triggered backup is running as runName
+func startManualBackup(dbBackup *dbbackupv1alpha1.DatabaseBackup, runName string) {
+	dbBackup.Status.ManualBackupPending = false
+	dbBackup.Status.ManualBackupRun = runName
+	meta.SetStatusCondition(&dbBackup.Status.Conditions, metav1.Condition{
+		Type:               dbbackupv1alpha1.ConditionComplete,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: dbBackup.Generation,
+		Reason:             "Running",
+		Message:            fmt.Sprintf("Backup %s is running", runName),
+	})
+}
+
+// Helper function to complete a triggered backup that was refused before it
+// could start, with result as its outcome
+func rejectManualBackup(dbBackup *dbbackupv1alpha1.DatabaseBackup, result, message string) {
+	dbBackup.Status.ManualBackupPending = false
+	dbBackup.Status.LastManualBackupResult = result
+	meta.SetStatusCondition(&dbBackup.Status.Conditions, metav1.Condition{
+		Type:               dbbackupv1alpha1.ConditionComplete,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: dbBackup.Generation,
+		Reason:             "Backup" + result,
+		Message:            message,
+	})
+}
+
+// Helper function to mark the triggered backup complete once runName, the
+// run that just finished, is the one it started. LastBackupStatus must
+// already hold the run's outcome.
+func finishManualBackup(dbBackup *dbbackupv1alpha1.DatabaseBackup, runName string) {
+	if dbBackup.Status.ManualBackupRun == "" || dbBackup.Status.ManualBackupRun != runName {
+		return
+	}
+
+	result := dbBackup.Status.LastBackupStatus
+	if result == "Running" {
+		// The run vanished without reporting an outcome
+		result = "Failed"
+	}
+	dbBackup.Status.ManualBackupRun = ""
+	dbBackup.Status.LastManualBackupResult = result
+	meta.SetStatusCondition(&dbBackup.Status.Conditions, metav1.Condition{
+		Type:               dbbackupv1alpha1.ConditionComplete,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: dbBackup.Generation,
+		Reason:             "Backup" + result,
+		Message:            fmt.Sprintf("Backup %s finished: %s", runName, result),
+	})
+}
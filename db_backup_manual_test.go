@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+func triggeredBackup(trigger string) *dbbackupv1alpha1.DatabaseBackup {
+	return &dbbackupv1alpha1.DatabaseBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "db",
+			Namespace:   "default",
+			Annotations: map[string]string{backupNowAnnotation: trigger},
+		},
+	}
+}
+
+// Helper function to check the Complete condition's status and reason
+func expectComplete(t *testing.T, dbBackup *dbbackupv1alpha1.DatabaseBackup, status metav1.ConditionStatus, reason string) {
+	t.Helper()
+	condition := meta.FindStatusCondition(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionComplete)
+	if condition == nil {
+		t.Fatalf("no %s condition", dbbackupv1alpha1.ConditionComplete)
+	}
+	if condition.Status != status || condition.Reason != reason {
+		t.Errorf("%s = %s/%s, want %s/%s", dbbackupv1alpha1.ConditionComplete, condition.Status, condition.Reason, status, reason)
+	}
+}
+
+func TestManualBackupLifecycle(t *testing.T) {
+	t.Run("accept, start and finish", func(t *testing.T) {
+		dbBackup := triggeredBackup("2026-01-01T00:00:00Z")
+		if !acceptManualTrigger(dbBackup) {
+			t.Fatal("new trigger not accepted")
+		}
+		if !dbBackup.Status.ManualBackupPending {
+			t.Error("accepted trigger isn't pending")
+		}
+		expectComplete(t, dbBackup, metav1.ConditionFalse, "Triggered")
+
+		runName := manualRunName(dbBackup)
+		startManualBackup(dbBackup, runName)
+		if dbBackup.Status.ManualBackupPending || dbBackup.Status.ManualBackupRun != runName {
+			t.Errorf("started backup pending = %v, run = %q, want false, %q", dbBackup.Status.ManualBackupPending, dbBackup.Status.ManualBackupRun, runName)
+		}
+		expectComplete(t, dbBackup, metav1.ConditionFalse, "Running")
+
+		// A scheduled run finishing in the meantime isn't the triggered one
+		dbBackup.Status.LastBackupStatus = "Succeeded"
+		finishManualBackup(dbBackup, "db-scheduled")
+		expectComplete(t, dbBackup, metav1.ConditionFalse, "Running")
+
+		finishManualBackup(dbBackup, runName)
+		if dbBackup.Status.ManualBackupRun != "" || dbBackup.Status.LastManualBackupResult != "Succeeded" {
+			t.Errorf("finished backup run = %q, result = %q, want cleared and Succeeded", dbBackup.Status.ManualBackupRun, dbBackup.Status.LastManualBackupResult)
+		}
+		expectComplete(t, dbBackup, metav1.ConditionTrue, "BackupSucceeded")
+	})
+
+	t.Run("run vanished while running", func(t *testing.T) {
+		dbBackup := triggeredBackup("2026-01-01T00:00:00Z")
+		acceptManualTrigger(dbBackup)
+		startManualBackup(dbBackup, manualRunName(dbBackup))
+		dbBackup.Status.LastBackupStatus = "Running"
+		finishManualBackup(dbBackup, manualRunName(dbBackup))
+		expectComplete(t, dbBackup, metav1.ConditionTrue, "BackupFailed")
+	})
+
+	t.Run("accept and reject", func(t *testing.T) {
+		dbBackup := triggeredBackup("2026-01-01T00:00:00Z")
+		acceptManualTrigger(dbBackup)
+		rejectManualBackup(dbBackup, "SizeLimitExceeded", "Database over size limit")
+		if dbBackup.Status.ManualBackupPending || dbBackup.Status.LastManualBackupResult != "SizeLimitExceeded" {
+			t.Errorf("rejected backup pending = %v, result = %q, want false, SizeLimitExceeded", dbBackup.Status.ManualBackupPending, dbBackup.Status.LastManualBackupResult)
+		}
+		expectComplete(t, dbBackup, metav1.ConditionTrue, "BackupSizeLimitExceeded")
+	})
+
+	t.Run("trigger acted on once", func(t *testing.T) {
+		dbBackup := triggeredBackup("2026-01-01T00:00:00Z")
+		acceptManualTrigger(dbBackup)
+		first := manualRunName(dbBackup)
+		startManualBackup(dbBackup, first)
+		dbBackup.Status.LastBackupStatus = "Succeeded"
+		finishManualBackup(dbBackup, first)
+
+		if acceptManualTrigger(dbBackup) {
+			t.Error("trigger accepted again")
+		}
+		if manualRunName(dbBackup) != first {
+			t.Error("run name changed without a new trigger")
+		}
+
+		dbBackup.Annotations[backupNowAnnotation] = "2026-01-02T00:00:00Z"
+		if !acceptManualTrigger(dbBackup) {
+			t.Fatal("new trigger not accepted")
+		}
+		if manualRunName(dbBackup) == first {
+			t.Errorf("new trigger reuses run name %s", first)
+		}
+		if dbBackup.Status.LastManualBackupResult != "" {
+			t.Errorf("new trigger kept the previous result %q", dbBackup.Status.LastManualBackupResult)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=create;update
+
+// metadataRetryInterval is how soon publishing backup metadata is retried after a failure
+const metadataRetryInterval = time.Minute
+
+// Helper function to get where the last artifact was written. Images that
+// don't report it get the destination it was written under
+func artifactLocation(dbBackup *dbbackupv1alpha1.DatabaseBackup, report *backupReport) string {
+	if report != nil && report.Location != "" {
+		return report.Location
+	}
+	dest := dbBackup.Spec.StorageDestination
+	if dest.Type == "pvc" {
+		return "pvc://" + path.Join(dest.PVCName, dest.Path)
+	}
+	return dest.Type + "://" + path.Join(dest.Bucket, dest.Path)
+}
+
+// Helper function to publish the latest successful backup in a ConfigMap
+// named after the DatabaseBackup, for tooling that can't read DatabaseBackups.
+// A ConfigMap of that name not owned by the DatabaseBackup is left alone.
+func (r *DatabaseBackupReconciler) publishBackupMetadata(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, report *backupReport, completed time.Time) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      dbBackup.Name,
+			Namespace: dbBackup.Namespace,
+		},
+	}
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(configMap), configMap)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && !metav1.IsControlledBy(configMap, dbBackup) {
+		r.Recorder.Eventf(dbBackup, corev1.EventTypeWarning, "MetadataConflict", "ConfigMap %s exists and isn't owned by this DatabaseBackup, not publishing backup metadata", configMap.Name)
+		return nil
+	}
+
+	data := map[string]string{
+		"location":  artifactLocation(dbBackup, report),
+		"timestamp": completed.UTC().Format(time.RFC3339),
+	}
+	if report != nil && report.SizeBytes != nil {
+		data["sizeBytes"] = strconv.FormatInt(*report.SizeBytes, 10)
+	}
+	if report != nil && sha256Pattern.MatchString(report.ArtifactSHA256) {
+		data["sha256"] = report.ArtifactSHA256
+	}
+
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels[backupNameLabel] = dbBackup.Name
+		configMap.Data = data
+		return ctrl.SetControllerReference(dbBackup, configMap, r.Scheme)
+	})
+	return err
+}
+
+// Helper function to record in the MetadataPublished condition whether
+// publishing the backup metadata worked
+func (r *DatabaseBackupReconciler) recordMetadataPublished(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, err error) {
+	condition := metav1.Condition{
+		Type:               dbbackupv1alpha1.ConditionMetadataPublished,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: dbBackup.Generation,
+		Reason:             "Published",
+		Message:            "Backup metadata is published in ConfigMap " + dbBackup.Name,
+	}
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to publish backup metadata")
+		r.Recorder.Eventf(dbBackup, corev1.EventTypeWarning, "MetadataPublishFailed", "Failed to publish backup metadata: %v", err)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "PublishFailed"
+		condition.Message = "Failed to publish backup metadata: " + err.Error()
+	}
+	meta.SetStatusCondition(&dbBackup.Status.Conditions, condition)
+}
+
+// Helper function to retry publishing the metadata of the last successful
+// backup when it failed as the backup finished. Status doesn't keep the
+// artifact size, so a retried ConfigMap goes without sizeBytes. Returns how
+// soon to retry again, or zero
+func (r *DatabaseBackupReconciler) syncBackupMetadata(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (time.Duration, error) {
+	condition := meta.FindStatusCondition(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionMetadataPublished)
+	if condition == nil || condition.Status != metav1.ConditionFalse {
+		return 0, nil
+	}
+
+	// Nothing is owed once publishing has been turned off
+	if !dbBackup.Spec.PublishMetadata || dbBackup.Status.LastSuccessfulBackup == nil {
+		meta.RemoveStatusCondition(&dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionMetadataPublished)
+		return 0, nil
+	}
+
+	report := &backupReport{
+		Location:       dbBackup.Status.LastBackupLocation,
+		ArtifactSHA256: dbBackup.Status.LastArtifactSHA256,
+	}
+	publishErr := r.publishBackupMetadata(ctx, dbBackup, report, dbBackup.Status.LastSuccessfulBackup.Time)
+	r.recordMetadataPublished(ctx, dbBackup, publishErr)
+	if publishErr != nil {
+		return metadataRetryInterval, nil
+	}
+	return 0, nil
+}
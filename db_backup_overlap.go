@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+const (
+	// scheduleOverlapWindow is how close runs of two DatabaseBackups of the
+	// same database have to be to count as near-simultaneous
+	scheduleOverlapWindow = 15 * time.Minute
+
+	// scheduleOverlapHorizon is how far ahead schedules are compared. A
+	// year covers a full period of weekly, monthly and yearly schedules
+	scheduleOverlapHorizon = 366 * 24 * time.Hour
+
+	// scheduleOverlapMaxRuns caps the runs checked per pair of schedules
+	scheduleOverlapMaxRuns = 10000
+)
+
+// Helper function to set the PotentialConflict condition when another
+// DatabaseBackup in the namespace backs up one of the same database pods on a
+// schedule that runs within scheduleOverlapWindow of this one. It is advisory
+// only and never holds back a backup. Returns true if the condition changed
+func (r *DatabaseBackupReconciler) checkScheduleOverlap(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, now time.Time) (bool, error) {
+	condition := metav1.Condition{
+		Type:               dbbackupv1alpha1.ConditionPotentialConflict,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: dbBackup.Generation,
+		Reason:             "NoOverlap",
+		Message:            "No other DatabaseBackup backs up the same database at around the same time",
+	}
+
+	conflicts, err := r.findScheduleOverlaps(ctx, dbBackup, now)
+	if err != nil {
+		return false, err
+	}
+	if len(conflicts) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "OverlappingSchedule"
+		condition.Message = fmt.Sprintf("DatabaseBackups %s back up the same database within %s of this one and may contend for locks and load",
+			strings.Join(conflicts, ", "), scheduleOverlapWindow)
+	}
+
+	existing := meta.FindStatusCondition(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionPotentialConflict)
+	if existing != nil && existing.Status == condition.Status && existing.Message == condition.Message {
+		return false, nil
+	}
+	meta.SetStatusCondition(&dbBackup.Status.Conditions, condition)
+	return true, nil
+}
+
+// Helper function to get the names of the other DatabaseBackups selecting
+// any of this one's database pods with a near-simultaneous schedule
+func (r *DatabaseBackupReconciler) findScheduleOverlaps(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, now time.Time) ([]string, error) {
+	schedule := parseSchedule(dbBackup)
+	if schedule == nil || !hasDatabaseSelector(dbBackup) {
+		return nil, nil
+	}
+
+	pods, err := r.findTargetPods(ctx, dbBackup)
+	if err != nil || len(pods) == 0 {
+		return nil, err
+	}
+
+	var backups dbbackupv1alpha1.DatabaseBackupList
+	if err := r.List(ctx, &backups, client.InNamespace(dbBackup.Namespace)); err != nil {
+		return nil, err
+	}
+
+	var conflicts []string
+	for i := range backups.Items {
+		other := &backups.Items[i]
+		if other.Name == dbBackup.Name || !other.DeletionTimestamp.IsZero() ||
+			meta.IsStatusConditionTrue(other.Status.Conditions, dbbackupv1alpha1.ConditionAutoSuspended) {
+			continue
+		}
+		if !selectsAnyPod(other, pods) {
+			continue
+		}
+		if otherSchedule := parseSchedule(other); otherSchedule != nil && schedulesOverlap(schedule, otherSchedule, now) {
+			conflicts = append(conflicts, other.Name)
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts, nil
+}
+
+// Helper function to check if a DatabaseBackup selects its database by
+// labels. Ones connecting through a DatabaseRef alone can't be compared
+func hasDatabaseSelector(dbBackup *dbbackupv1alpha1.DatabaseBackup) bool {
+	selector := dbBackup.Spec.DatabaseSelector
+	return len(selector.MatchLabels) > 0 || len(selector.MatchExpressions) > 0
+}
+
+// Helper function to check if a DatabaseBackup's selector matches any of the
+// given pods
+func selectsAnyPod(dbBackup *dbbackupv1alpha1.DatabaseBackup, pods []corev1.Pod) bool {
+	if !hasDatabaseSelector(dbBackup) {
+		return false
+	}
+	parsed, err := metav1.LabelSelectorAsSelector(&dbBackup.Spec.DatabaseSelector)
+	if err != nil {
+		return false
+	}
+	for i := range pods {
+		if parsed.Matches(labels.Set(pods[i].Labels)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Helper function to parse a DatabaseBackup's schedule. Returns nil when it
+// isn't a valid cron schedule
+func parseSchedule(dbBackup *dbbackupv1alpha1.DatabaseBackup) cron.Schedule {
+	schedule, err := cron.ParseStandard(dbBackup.Spec.Schedule)
+	if err != nil {
+		return nil
+	}
+	return schedule
+}
+
+// Helper function to get the gap between a schedule's next two runs. Returns
+// zero when it doesn't run twice more
+func schedulePeriod(schedule cron.Schedule, now time.Time) time.Duration {
+	first := schedule.Next(now)
+	if first.IsZero() {
+		return 0
+	}
+	second := schedule.Next(first)
+	if second.IsZero() {
+		return 0
+	}
+	return second.Sub(first)
+}
+
+// Helper function to check if two schedules have runs within
+// scheduleOverlapWindow of each other over the next scheduleOverlapHorizon.
+// The runs of the less frequent schedule are walked and each one is checked
+// against the other schedule, so a monthly schedule is compared over whole
+// months without listing every run of a minutely one
+func schedulesOverlap(a, b cron.Schedule, now time.Time) bool {
+	if schedulePeriod(a, now) < schedulePeriod(b, now) {
+		a, b = b, a
+	}
+	end := now.Add(scheduleOverlapHorizon)
+	checked := 0
+	for next := a.Next(now); !next.IsZero() && next.Before(end) && checked < scheduleOverlapMaxRuns; next = a.Next(next) {
+		if runsNear(b, next) {
+			return true
+		}
+		checked++
+	}
+	return false
+}
+
+// Helper function to check if a schedule has a run within
+// scheduleOverlapWindow of the given time
+func runsNear(schedule cron.Schedule, t time.Time) bool {
+	next := schedule.Next(t.Add(-scheduleOverlapWindow))
+	return !next.IsZero() && next.Before(t.Add(scheduleOverlapWindow))
+}
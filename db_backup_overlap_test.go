@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+func mustParseSchedule(t *testing.T, spec string) cron.Schedule {
+	t.Helper()
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		t.Fatalf("parsing schedule %q: %v", spec, err)
+	}
+	return schedule
+}
+
+func TestSchedulesOverlap(t *testing.T) {
+	// A Thursday, so weekly and monthly schedules don't line up right away
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{name: "same schedule", a: "0 2 * * *", b: "0 2 * * *", want: true},
+		{name: "hourly within window", a: "0 * * * *", b: "10 * * * *", want: true},
+		{name: "hourly half an hour apart", a: "0 * * * *", b: "30 * * * *", want: false},
+		{name: "hourly exactly a window apart", a: "0 * * * *", b: "15 * * * *", want: false},
+		{name: "quarter-hourly against hourly", a: "*/15 * * * *", b: "7 * * * *", want: true},
+		{name: "weekly against daily at the same time", a: "0 3 * * 0", b: "0 3 * * *", want: true},
+		{name: "weekly against daily an hour later", a: "0 3 * * 0", b: "0 4 * * *", want: false},
+		{name: "monthly against daily an hour apart", a: "0 2 1 * *", b: "0 3 * * *", want: false},
+		// Only meet on a Sunday the 1st, which is a month out
+		{name: "weekly against monthly", a: "0 3 * * 0", b: "5 3 1 * *", want: true},
+		// The next leap day is past the horizon
+		{name: "leap day against daily", a: "0 0 29 2 *", b: "0 0 * * *", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := mustParseSchedule(t, tt.a), mustParseSchedule(t, tt.b)
+			if got := schedulesOverlap(a, b, now); got != tt.want {
+				t.Errorf("schedulesOverlap(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if got := schedulesOverlap(b, a, now); got != tt.want {
+				t.Errorf("schedulesOverlap(%q, %q) = %v, want %v", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedulePeriod(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		schedule string
+		want     time.Duration
+	}{
+		{schedule: "*/15 * * * *", want: 15 * time.Minute},
+		{schedule: "0 * * * *", want: time.Hour},
+		{schedule: "0 2 * * *", want: 24 * time.Hour},
+		{schedule: "0 3 * * 0", want: 7 * 24 * time.Hour},
+		{schedule: "0 2 1 * *", want: 31 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.schedule, func(t *testing.T) {
+			if got := schedulePeriod(mustParseSchedule(t, tt.schedule), now); got != tt.want {
+				t.Errorf("schedulePeriod(%q) = %s, want %s", tt.schedule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunsNear(t *testing.T) {
+	hourly := mustParseSchedule(t, "0 * * * *")
+	midnight := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{name: "on a run", t: midnight, want: true},
+		{name: "just after a run", t: midnight.Add(14 * time.Minute), want: true},
+		{name: "a window after a run", t: midnight.Add(scheduleOverlapWindow), want: false},
+		{name: "just before a run", t: midnight.Add(-10 * time.Minute), want: true},
+		{name: "between runs", t: midnight.Add(30 * time.Minute), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runsNear(hourly, tt.t); got != tt.want {
+				t.Errorf("runsNear(%s) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
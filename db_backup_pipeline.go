@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+const (
+	// pipelineUploadContainer is the sidecar that uploads the dump while
+	// the backup container is still writing it
+	pipelineUploadContainer = "upload"
+
+	// pipelineDir is where the dump is handed from the backup container to
+	// the upload sidecar
+	pipelineDir = "/pipeline"
+)
+
+// pipelineUploadEnv are the backup container's env vars the upload sidecar
+// gets: where and how to store the artifact, never how to reach the database
+var pipelineUploadEnv = map[string]bool{
+	"DB_TYPE":                    true,
+	"BACKUP_TYPE":                true,
+	"STORAGE_TYPE":               true,
+	"BUCKET":                     true,
+	"PATH":                       true,
+	"POD_NAME":                   true,
+	"POD_NAMESPACE":              true,
+	"NODE_NAME":                  true,
+	"ARTIFACT_NAME":              true,
+	"FILE_EXTENSION":             true,
+	"OBJECT_LOCK_DAYS":           true,
+	"CLEANUP_SKIP_LOCKED":        true,
+	"UPDATE_MANIFEST":            true,
+	"MANIFEST_PATH":              true,
+	"BACKUP_TAGS":                true,
+	"LIFECYCLE_RULES":            true,
+	"RATE_LIMIT":                 true,
+	"RECORD_CHECKSUM":            true,
+	"STORE_LOGS":                 true,
+	"STORAGE_ENDPOINT":           true,
+	"STORAGE_CA_BUNDLE":          true,
+	"STORAGE_TOKEN_FILE":         true,
+	"STORAGE_DESTINATIONS":       true,
+	"STAGING_DIR":                true,
+	"STAGING_RUN":                true,
+	"SUPERSEDED_STAGED_ARTIFACT": true,
+	"ENCRYPTION_KEY_FILE":        true,
+	"ENCRYPTION_KEY_ID":          true,
+	"SHARD_INDEX":                true,
+	"SHARD_COUNT":                true,
+	"JOB_COMPLETION_INDEX":       true,
+	"TARGET_NAME":                true,
+	"GROUP_RUN":                  true,
+}
+
+// Helper function to check if a backup container volume holds storage
+// settings or credentials, or the staged artifact, rather than anything
+// about the database
+func isPipelineUploadVolume(name string) bool {
+	return name == "staging" || name == "encryption-key" || isStorageVolume(name)
+}
+
+// Helper function to split a backup pod into a dumper and an upload sidecar
+// sharing an emptyDir, when PipelineMode is set. The backup container writes
+// the dump to a FIFO in PIPELINE_DIR and the sidecar ships it to storage as
+// it arrives. Either one exiting non-zero fails the pod, and the dumper
+// leaves a "failed" marker so the sidecar stops waiting on the FIFO.
+//
+// The sidecar only gets the backup container's storage env vars and mounts,
+// so it must run once the backup container is final, encryption key
+// included.
+func addUploadPipeline(podSpec *corev1.PodSpec, dbBackup *dbbackupv1alpha1.DatabaseBackup) {
+	if dbBackup.Spec.PipelineMode == nil || !*dbBackup.Spec.PipelineMode {
+		return
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "pipeline",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	mount := corev1.VolumeMount{
+		Name:      "pipeline",
+		MountPath: pipelineDir,
+	}
+
+	backup := &podSpec.Containers[0]
+	upload := corev1.Container{
+		Name:            pipelineUploadContainer,
+		Image:           backup.Image,
+		Command:         backup.Command,
+		Args:            backup.Args,
+		SecurityContext: backup.SecurityContext,
+		Resources:       backup.Resources,
+	}
+	for _, env := range backup.Env {
+		if pipelineUploadEnv[env.Name] {
+			upload.Env = append(upload.Env, env)
+		}
+	}
+	for _, volumeMount := range backup.VolumeMounts {
+		if isPipelineUploadVolume(volumeMount.Name) {
+			upload.VolumeMounts = append(upload.VolumeMounts, volumeMount)
+		}
+	}
+
+	backup.VolumeMounts = append(backup.VolumeMounts, mount)
+	backup.Env = append(backup.Env,
+		corev1.EnvVar{
+			Name:  "PIPELINE_DIR",
+			Value: pipelineDir,
+		},
+		corev1.EnvVar{
+			Name:  "PIPELINE_ROLE",
+			Value: "dump",
+		},
+	)
+	upload.VolumeMounts = append(upload.VolumeMounts, mount)
+	upload.Env = append(upload.Env,
+		corev1.EnvVar{
+			Name:  "PIPELINE_DIR",
+			Value: pipelineDir,
+		},
+		corev1.EnvVar{
+			Name:  "PIPELINE_ROLE",
+			Value: "upload",
+		},
+	)
+	podSpec.Containers = append(podSpec.Containers, upload)
+}
+
+// Helper function to get the container that reports a backup Job's outcome.
+// In pipeline mode only the upload sidecar knows where the artifact went
+func backupReportContainer(job *batchv1.Job) string {
+	for _, container := range job.Spec.Template.Spec.Containers {
+		if container.Name == pipelineUploadContainer {
+			return pipelineUploadContainer
+		}
+	}
+	return "backup"
+}
@@ -0,0 +1,332 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// policyNameLabel is set on every DatabaseBackup created by a DatabaseBackupPolicy
+const policyNameLabel = "db.example.io/policy"
+
+// lastAppliedTemplateAnnotation records the template last applied to a
+// policy's DatabaseBackup, as JSON. Fields it has and the current template
+// doesn't were removed from the template and are cleared
+const lastAppliedTemplateAnnotation = "db.example.io/last-applied-template"
+
+// DatabaseBackupPolicyReconciler reconciles a DatabaseBackupPolicy object,
+// keeping one DatabaseBackup per selected namespace
+type DatabaseBackupPolicyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Selector limits this controller to DatabaseBackupPolicies with
+	// matching labels, the same shard selector the DatabaseBackup
+	// controller uses. All policies are reconciled when nil
+	Selector labels.Selector
+}
+
+//+kubebuilder:rbac:groups=db.example.io,resources=databasebackuppolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=db.example.io,resources=databasebackuppolicies/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+
+func (r *DatabaseBackupPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	var policy dbbackupv1alpha1.DatabaseBackupPolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		// Owned DatabaseBackups are garbage collected with the policy
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if policy.DeletionTimestamp != nil || !r.ownsPolicy(&policy) {
+		return ctrl.Result{}, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.NamespaceSelector)
+	if err != nil {
+		// Not retried, the selector has to be fixed in the spec
+		log.Error(err, "Invalid namespace selector")
+		policy.Status.ObservedGeneration = policy.Generation
+		meta.SetStatusCondition(&policy.Status.Conditions, metav1.Condition{
+			Type:               dbbackupv1alpha1.ConditionSelectorValid,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: policy.Generation,
+			Reason:             "InvalidSelector",
+			Message:            fmt.Sprintf("Invalid namespace selector: %v", err),
+		})
+		if err := r.Status().Update(ctx, &policy); err != nil {
+			log.Error(err, "Failed to update DatabaseBackupPolicy status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	var namespaces corev1.NamespaceList
+	if err := r.List(ctx, &namespaces, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	matched := map[string]bool{}
+	var applied, conflicts []string
+	for _, namespace := range namespaces.Items {
+		// Nothing can be created in a namespace that is going away
+		if namespace.DeletionTimestamp != nil {
+			continue
+		}
+		matched[namespace.Name] = true
+
+		owned, err := r.applyPolicyBackup(ctx, &policy, namespace.Name)
+		if err != nil {
+			log.Error(err, "Failed to apply DatabaseBackup", "namespace", namespace.Name)
+			return ctrl.Result{}, err
+		}
+		if owned {
+			applied = append(applied, namespace.Name)
+		} else {
+			conflicts = append(conflicts, namespace.Name)
+		}
+	}
+
+	// Prune the DatabaseBackups of namespaces that stopped matching
+	var children dbbackupv1alpha1.DatabaseBackupList
+	if err := r.List(ctx, &children, client.MatchingLabels{policyNameLabel: policy.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	for i := range children.Items {
+		child := &children.Items[i]
+		if matched[child.Namespace] || !metav1.IsControlledBy(child, &policy) {
+			continue
+		}
+		log.Info("Namespace no longer matches, deleting DatabaseBackup", "namespace", child.Namespace, "name", child.Name)
+		if err := r.Delete(ctx, child); err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+	}
+
+	sort.Strings(applied)
+	sort.Strings(conflicts)
+	policy.Status.ObservedGeneration = policy.Generation
+	policy.Status.Namespaces = applied
+	policy.Status.Conflicts = conflicts
+	policy.Status.MatchingNamespaces = int32(len(matched))
+	meta.SetStatusCondition(&policy.Status.Conditions, metav1.Condition{
+		Type:               dbbackupv1alpha1.ConditionSelectorValid,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: policy.Generation,
+		Reason:             "SelectorParsed",
+		Message:            "Namespace selector is valid",
+	})
+	if err := r.Status().Update(ctx, &policy); err != nil {
+		log.Error(err, "Failed to update DatabaseBackupPolicy status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// Helper function to get the name of the DatabaseBackups created by a policy
+func policyBackupName(policy *dbbackupv1alpha1.DatabaseBackupPolicy) string {
+	if policy.Spec.BackupName != "" {
+		return policy.Spec.BackupName
+	}
+	return policy.Name
+}
+
+// Helper function to create or update the policy's DatabaseBackup in a
+// namespace. Returns false, without touching it, when a DatabaseBackup of
+// the same name exists that the policy doesn't control
+func (r *DatabaseBackupPolicyReconciler) applyPolicyBackup(ctx context.Context, policy *dbbackupv1alpha1.DatabaseBackupPolicy, namespace string) (bool, error) {
+	dbBackup := &dbbackupv1alpha1.DatabaseBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      policyBackupName(policy),
+			Namespace: namespace,
+		},
+	}
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(dbBackup), dbBackup)
+	if err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	if err == nil && !metav1.IsControlledBy(dbBackup, policy) {
+		return false, nil
+	}
+
+	template := policy.Spec.Template
+	applied, err := json.Marshal(template)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode the template of %s: %w", policy.Name, err)
+	}
+	spec := template.Spec.DeepCopy()
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, dbBackup, func() error {
+		last := lastAppliedTemplate(dbBackup)
+
+		if dbBackup.Labels == nil {
+			dbBackup.Labels = map[string]string{}
+		}
+		for key := range last.Labels {
+			if _, ok := template.Labels[key]; !ok {
+				delete(dbBackup.Labels, key)
+			}
+		}
+		for key, value := range template.Labels {
+			dbBackup.Labels[key] = value
+		}
+		dbBackup.Labels[policyNameLabel] = policy.Name
+
+		if dbBackup.Annotations == nil {
+			dbBackup.Annotations = map[string]string{}
+		}
+		for key := range last.Annotations {
+			if _, ok := template.Annotations[key]; !ok {
+				delete(dbBackup.Annotations, key)
+			}
+		}
+		for key, value := range template.Annotations {
+			dbBackup.Annotations[key] = value
+		}
+		dbBackup.Annotations[lastAppliedTemplateAnnotation] = string(applied)
+
+		// The template owns the fields it sets or last set, the rest are
+		// left alone, so defaults filled in on the DatabaseBackup survive
+		// and unchanged children aren't updated again on every reconcile
+		mergeTemplateSpec(&dbBackup.Spec, spec, &last.Spec)
+		return ctrl.SetControllerReference(policy, dbBackup, r.Scheme)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to apply DatabaseBackup %s/%s: %w", namespace, dbBackup.Name, err)
+	}
+	return true, nil
+}
+
+// Helper function to get the template last applied to a policy's
+// DatabaseBackup. DatabaseBackups applied before the annotation existed, or
+// whose annotation can't be parsed, count as having none, so nothing is cleared
+func lastAppliedTemplate(dbBackup *dbbackupv1alpha1.DatabaseBackup) dbbackupv1alpha1.DatabaseBackupTemplate {
+	var last dbbackupv1alpha1.DatabaseBackupTemplate
+	data, ok := dbBackup.Annotations[lastAppliedTemplateAnnotation]
+	if !ok {
+		return last
+	}
+	if err := json.Unmarshal([]byte(data), &last); err != nil {
+		return dbbackupv1alpha1.DatabaseBackupTemplate{}
+	}
+	return last
+}
+
+// Helper function to apply a template's spec onto a DatabaseBackupSpec.
+// Fields the template sets replace the existing value, including false,
+// zero or empty values the previous template had set otherwise. Fields
+// only the last applied template set were removed from it and are cleared.
+// Fields neither sets are left alone, so values set on the DatabaseBackup
+// itself survive. Structs of the API package are merged field by field
+func mergeTemplateSpec(dst, src, last *dbbackupv1alpha1.DatabaseBackupSpec) {
+	mergeValue(reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem(), reflect.ValueOf(last).Elem())
+}
+
+// Helper function to merge src into dst, clearing what only last set
+func mergeValue(dst, src, last reflect.Value) {
+	if dst.Kind() == reflect.Pointer && dst.Type().Elem().Kind() == reflect.Struct && isAPIType(dst.Type().Elem()) {
+		if src.IsNil() && (last.IsNil() || dst.IsNil()) {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		mergeValue(dst.Elem(), elemOrZero(src), elemOrZero(last))
+		// A struct removed from the template goes once nothing set on
+		// the DatabaseBackup itself is left in it
+		if src.IsNil() && dst.Elem().IsZero() {
+			dst.Set(reflect.Zero(dst.Type()))
+		}
+		return
+	}
+	if dst.Kind() == reflect.Struct && isAPIType(dst.Type()) {
+		for i := 0; i < dst.NumField(); i++ {
+			if dst.Type().Field(i).IsExported() {
+				mergeValue(dst.Field(i), src.Field(i), last.Field(i))
+			}
+		}
+		return
+	}
+	switch {
+	case !src.IsZero():
+		if !equality.Semantic.DeepEqual(dst.Interface(), src.Interface()) {
+			dst.Set(src)
+		}
+	case !last.IsZero():
+		dst.Set(reflect.Zero(dst.Type()))
+	}
+}
+
+// Helper function to get the struct a pointer points to, or a zero struct for nil
+func elemOrZero(ptr reflect.Value) reflect.Value {
+	if ptr.IsNil() {
+		return reflect.Zero(ptr.Type().Elem())
+	}
+	return ptr.Elem()
+}
+
+// Helper function to check if a type is defined in the API package
+func isAPIType(t reflect.Type) bool {
+	return t.PkgPath() == reflect.TypeOf(dbbackupv1alpha1.DatabaseBackupSpec{}).PkgPath()
+}
+
+// Helper function to check if a DatabaseBackupPolicy belongs to this controller's shard
+func (r *DatabaseBackupPolicyReconciler) ownsPolicy(obj client.Object) bool {
+	return r.Selector == nil || r.Selector.Matches(labels.Set(obj.GetLabels()))
+}
+
+// Helper function to map a namespace change to every DatabaseBackupPolicy,
+// since any of them may start or stop selecting it
+func (r *DatabaseBackupPolicyReconciler) findPoliciesForNamespace(obj client.Object) []reconcile.Request {
+	selector := labels.Everything()
+	if r.Selector != nil {
+		selector = r.Selector
+	}
+	var policies dbbackupv1alpha1.DatabaseBackupPolicyList
+	if err := r.List(context.Background(), &policies, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil
+	}
+
+	requests := make([]reconcile.Request, len(policies.Items))
+	for i, policy := range policies.Items {
+		requests[i] = reconcile.Request{NamespacedName: types.NamespacedName{Name: policy.Name}}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *DatabaseBackupPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&dbbackupv1alpha1.DatabaseBackupPolicy{}, builder.WithPredicates(predicate.NewPredicateFuncs(r.ownsPolicy))).
+		// Status updates of the DatabaseBackups don't concern the policy,
+		// spec edits and deletions do
+		Owns(&dbbackupv1alpha1.DatabaseBackup{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&source.Kind{Type: &corev1.Namespace{}},
+			handler.EnqueueRequestsFromMapFunc(r.findPoliciesForNamespace),
+		).
+		Complete(r)
+}
@@ -0,0 +1,285 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+func TestMergeTemplateSpec(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+
+	tests := []struct {
+		name           string
+		dst, src, last dbbackupv1alpha1.DatabaseBackupSpec
+		want           dbbackupv1alpha1.DatabaseBackupSpec
+	}{
+		{
+			name: "empty template keeps the backup's fields",
+			dst: dbbackupv1alpha1.DatabaseBackupSpec{
+				Schedule:        "0 2 * * *",
+				BackupRetention: 48,
+				BackupTags:      map[string]string{"team": "payments"},
+			},
+			want: dbbackupv1alpha1.DatabaseBackupSpec{
+				Schedule:        "0 2 * * *",
+				BackupRetention: 48,
+				BackupTags:      map[string]string{"team": "payments"},
+			},
+		},
+		{
+			name: "set fields replace the backup's",
+			dst:  dbbackupv1alpha1.DatabaseBackupSpec{Schedule: "0 2 * * *", BackupRetention: 48},
+			src:  dbbackupv1alpha1.DatabaseBackupSpec{Schedule: "0 3 * * *"},
+			want: dbbackupv1alpha1.DatabaseBackupSpec{Schedule: "0 3 * * *", BackupRetention: 48},
+		},
+		{
+			name: "maps and slices are replaced whole",
+			dst: dbbackupv1alpha1.DatabaseBackupSpec{
+				BackupTags:    map[string]string{"team": "payments", "tier": "gold"},
+				IncludeTables: []string{"orders", "invoices"},
+			},
+			src: dbbackupv1alpha1.DatabaseBackupSpec{
+				BackupTags:    map[string]string{"team": "platform"},
+				IncludeTables: []string{"users"},
+			},
+			want: dbbackupv1alpha1.DatabaseBackupSpec{
+				BackupTags:    map[string]string{"team": "platform"},
+				IncludeTables: []string{"users"},
+			},
+		},
+		{
+			name: "pointers to other types are replaced",
+			dst:  dbbackupv1alpha1.DatabaseBackupSpec{StreamToStorage: boolPtr(true)},
+			src:  dbbackupv1alpha1.DatabaseBackupSpec{StreamToStorage: boolPtr(false)},
+			want: dbbackupv1alpha1.DatabaseBackupSpec{StreamToStorage: boolPtr(false)},
+		},
+		{
+			name: "nested API structs are merged field by field",
+			dst: dbbackupv1alpha1.DatabaseBackupSpec{
+				IntegrityCheck: &dbbackupv1alpha1.IntegrityCheckSpec{Schedule: "0 5 * * 0", MaxArtifacts: 5},
+				BackupWindow:   &dbbackupv1alpha1.BackupWindowSpec{Start: "01:00", End: "05:00"},
+			},
+			src: dbbackupv1alpha1.DatabaseBackupSpec{
+				IntegrityCheck: &dbbackupv1alpha1.IntegrityCheckSpec{Image: "integrity:v2"},
+				BackupWindow:   &dbbackupv1alpha1.BackupWindowSpec{Timezone: "Europe/Berlin"},
+			},
+			want: dbbackupv1alpha1.DatabaseBackupSpec{
+				IntegrityCheck: &dbbackupv1alpha1.IntegrityCheckSpec{Schedule: "0 5 * * 0", MaxArtifacts: 5, Image: "integrity:v2"},
+				BackupWindow:   &dbbackupv1alpha1.BackupWindowSpec{Start: "01:00", End: "05:00", Timezone: "Europe/Berlin"},
+			},
+		},
+		{
+			name: "nested API structs the backup lacks are added",
+			src: dbbackupv1alpha1.DatabaseBackupSpec{
+				IntegrityCheck: &dbbackupv1alpha1.IntegrityCheckSpec{Schedule: "0 5 * * 0"},
+			},
+			want: dbbackupv1alpha1.DatabaseBackupSpec{
+				IntegrityCheck: &dbbackupv1alpha1.IntegrityCheckSpec{Schedule: "0 5 * * 0"},
+			},
+		},
+		{
+			name: "template field turned from true to false",
+			dst:  dbbackupv1alpha1.DatabaseBackupSpec{RecordChecksum: true, StreamToStorage: boolPtr(true)},
+			last: dbbackupv1alpha1.DatabaseBackupSpec{RecordChecksum: true, StreamToStorage: boolPtr(true)},
+			src:  dbbackupv1alpha1.DatabaseBackupSpec{StreamToStorage: boolPtr(false)},
+			want: dbbackupv1alpha1.DatabaseBackupSpec{StreamToStorage: boolPtr(false)},
+		},
+		{
+			name: "fields removed from the template are cleared",
+			dst: dbbackupv1alpha1.DatabaseBackupSpec{
+				Schedule:        "0 2 * * *",
+				BackupRetention: 48,
+				IncludeTables:   []string{"orders"},
+				BandwidthLimit:  "50MB/s",
+			},
+			last: dbbackupv1alpha1.DatabaseBackupSpec{
+				Schedule:        "0 2 * * *",
+				BackupRetention: 48,
+				IncludeTables:   []string{"orders"},
+			},
+			src: dbbackupv1alpha1.DatabaseBackupSpec{Schedule: "0 2 * * *"},
+			// BandwidthLimit was set on the DatabaseBackup itself
+			want: dbbackupv1alpha1.DatabaseBackupSpec{Schedule: "0 2 * * *", BandwidthLimit: "50MB/s"},
+		},
+		{
+			name: "template list emptied",
+			dst:  dbbackupv1alpha1.DatabaseBackupSpec{ExcludeTables: []string{"audit_log"}},
+			last: dbbackupv1alpha1.DatabaseBackupSpec{ExcludeTables: []string{"audit_log"}},
+			src:  dbbackupv1alpha1.DatabaseBackupSpec{ExcludeTables: []string{}},
+			want: dbbackupv1alpha1.DatabaseBackupSpec{ExcludeTables: []string{}},
+		},
+		{
+			name: "template overwrites a field it owns",
+			dst:  dbbackupv1alpha1.DatabaseBackupSpec{Schedule: "*/5 * * * *"},
+			last: dbbackupv1alpha1.DatabaseBackupSpec{Schedule: "0 2 * * *"},
+			src:  dbbackupv1alpha1.DatabaseBackupSpec{Schedule: "0 2 * * *"},
+			want: dbbackupv1alpha1.DatabaseBackupSpec{Schedule: "0 2 * * *"},
+		},
+		{
+			name: "nested API struct removed from the template",
+			dst: dbbackupv1alpha1.DatabaseBackupSpec{
+				IntegrityCheck: &dbbackupv1alpha1.IntegrityCheckSpec{Schedule: "0 5 * * 0", MaxArtifacts: 5},
+				BackupWindow:   &dbbackupv1alpha1.BackupWindowSpec{Start: "01:00", End: "05:00"},
+			},
+			last: dbbackupv1alpha1.DatabaseBackupSpec{
+				IntegrityCheck: &dbbackupv1alpha1.IntegrityCheckSpec{Schedule: "0 5 * * 0"},
+				BackupWindow:   &dbbackupv1alpha1.BackupWindowSpec{Start: "01:00", End: "05:00"},
+			},
+			// MaxArtifacts was set on the DatabaseBackup itself and keeps its struct
+			want: dbbackupv1alpha1.DatabaseBackupSpec{
+				IntegrityCheck: &dbbackupv1alpha1.IntegrityCheckSpec{MaxArtifacts: 5},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := tt.src
+			mergeTemplateSpec(&tt.dst, &src, &tt.last)
+			if !equality.Semantic.DeepEqual(tt.dst, tt.want) {
+				t.Errorf("mergeTemplateSpec =\n%+v\nwant\n%+v", tt.dst, tt.want)
+			}
+			if tt.src.IntegrityCheck != nil && tt.dst.IntegrityCheck == tt.src.IntegrityCheck {
+				t.Errorf("merged spec shares the template's IntegrityCheck")
+			}
+		})
+	}
+}
+
+func newTestPolicyReconciler(t *testing.T, objs ...runtime.Object) *DatabaseBackupPolicyReconciler {
+	t.Helper()
+	r := newTestReconciler(t, objs...)
+	return &DatabaseBackupPolicyReconciler{Client: r.Client, Scheme: r.Scheme}
+}
+
+func TestPolicyTemplateChanges(t *testing.T) {
+	policy := &dbbackupv1alpha1.DatabaseBackupPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "fleet", UID: "policy-uid"},
+		Spec: dbbackupv1alpha1.DatabaseBackupPolicySpec{
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"backup": "true"}},
+			Template: dbbackupv1alpha1.DatabaseBackupTemplate{
+				Labels: map[string]string{"tier": "gold"},
+				Spec: dbbackupv1alpha1.DatabaseBackupSpec{
+					Schedule:        "0 2 * * *",
+					BackupRetention: 48,
+					RecordChecksum:  true,
+				},
+			},
+		},
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"backup": "true"}}}
+	r := newTestPolicyReconciler(t, policy, namespace)
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "fleet"}}
+	childKey := types.NamespacedName{Name: "fleet", Namespace: "team-a"}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("first reconcile: %v", err)
+	}
+
+	// The team sets a field of its own on the DatabaseBackup
+	var child dbbackupv1alpha1.DatabaseBackup
+	if err := r.Get(ctx, childKey, &child); err != nil {
+		t.Fatalf("getting the DatabaseBackup: %v", err)
+	}
+	if _, ok := child.Annotations[lastAppliedTemplateAnnotation]; !ok {
+		t.Errorf("no %s annotation on the DatabaseBackup", lastAppliedTemplateAnnotation)
+	}
+	child.Spec.BandwidthLimit = "50MB/s"
+	if err := r.Update(ctx, &child); err != nil {
+		t.Fatal(err)
+	}
+
+	// The template stops checksumming, drops retention and the tier label
+	if err := r.Get(ctx, client.ObjectKeyFromObject(policy), policy); err != nil {
+		t.Fatal(err)
+	}
+	policy.Spec.Template.Labels = nil
+	policy.Spec.Template.Spec.RecordChecksum = false
+	policy.Spec.Template.Spec.BackupRetention = 0
+	if err := r.Update(ctx, policy); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("second reconcile: %v", err)
+	}
+
+	if err := r.Get(ctx, childKey, &child); err != nil {
+		t.Fatal(err)
+	}
+	if child.Spec.RecordChecksum || child.Spec.BackupRetention != 0 {
+		t.Errorf("recordChecksum = %v, backupRetention = %d, want both cleared", child.Spec.RecordChecksum, child.Spec.BackupRetention)
+	}
+	if _, ok := child.Labels["tier"]; ok {
+		t.Error("label removed from the template is still set")
+	}
+	if child.Spec.Schedule != "0 2 * * *" || child.Spec.BandwidthLimit != "50MB/s" {
+		t.Errorf("schedule = %q, bandwidthLimit = %q, want the template's schedule and the team's limit", child.Spec.Schedule, child.Spec.BandwidthLimit)
+	}
+	if child.Labels[policyNameLabel] != "fleet" {
+		t.Errorf("%s = %q, want fleet", policyNameLabel, child.Labels[policyNameLabel])
+	}
+}
+
+func TestPolicyInvalidSelector(t *testing.T) {
+	policy := &dbbackupv1alpha1.DatabaseBackupPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "fleet", Generation: 2},
+		Spec: dbbackupv1alpha1.DatabaseBackupPolicySpec{
+			NamespaceSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "backup", Operator: "Contains", Values: []string{"true"}},
+			}},
+		},
+	}
+	r := newTestPolicyReconciler(t, policy, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}})
+	ctx := context.Background()
+
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "fleet"}})
+	if err != nil || result.Requeue || result.RequeueAfter != 0 {
+		t.Fatalf("Reconcile = %+v, %v, want no retry", result, err)
+	}
+
+	if err := r.Get(ctx, client.ObjectKeyFromObject(policy), policy); err != nil {
+		t.Fatal(err)
+	}
+	condition := meta.FindStatusCondition(policy.Status.Conditions, dbbackupv1alpha1.ConditionSelectorValid)
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "InvalidSelector" || condition.ObservedGeneration != 2 {
+		t.Fatalf("%s = %+v, want False/InvalidSelector for generation 2", dbbackupv1alpha1.ConditionSelectorValid, condition)
+	}
+	if !strings.Contains(condition.Message, "Contains") {
+		t.Errorf("message = %q, want the selector error", condition.Message)
+	}
+
+	var children dbbackupv1alpha1.DatabaseBackupList
+	if err := r.List(ctx, &children); err != nil {
+		t.Fatal(err)
+	}
+	if len(children.Items) != 0 {
+		t.Errorf("%d DatabaseBackups created with an invalid selector", len(children.Items))
+	}
+
+	// Fixing the selector flips the condition
+	policy.Spec.NamespaceSelector = metav1.LabelSelector{}
+	if err := r.Update(ctx, policy); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "fleet"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(policy), policy); err != nil {
+		t.Fatal(err)
+	}
+	if !meta.IsStatusConditionTrue(policy.Status.Conditions, dbbackupv1alpha1.ConditionSelectorValid) {
+		t.Errorf("%s not true once the selector is fixed", dbbackupv1alpha1.ConditionSelectorValid)
+	}
+}
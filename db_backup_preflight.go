@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/robfig/cron"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// preflightAnnotation set to "true" runs the preflight checks once. The
+// controller removes it when the results are in status
+const preflightAnnotation = "db.example.io/preflight"
+
+// Helper function to build a preflight check result
+func preflightCheck(name, result, format string, args ...interface{}) dbbackupv1alpha1.PreflightCheck {
+	return dbbackupv1alpha1.PreflightCheck{
+		Name:    name,
+		Result:  result,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// Helper function to check a DatabaseBackup's configuration without running
+// a backup. Checks that don't apply to the spec are reported as Skipped
+func (r *DatabaseBackupReconciler) runPreflight(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) ([]dbbackupv1alpha1.PreflightCheck, error) {
+	spec := &dbBackup.Spec
+	var checks []dbbackupv1alpha1.PreflightCheck
+
+	if err := validateSpec(spec); err != nil {
+		checks = append(checks, preflightCheck("Spec", dbbackupv1alpha1.PreflightFail, "%v", err))
+	} else {
+		checks = append(checks, preflightCheck("Spec", dbbackupv1alpha1.PreflightPass, "Spec is valid"))
+	}
+
+	if _, err := cron.ParseStandard(spec.Schedule); err != nil {
+		checks = append(checks, preflightCheck("Schedule", dbbackupv1alpha1.PreflightFail, "Schedule %q does not parse: %v", spec.Schedule, err))
+	} else {
+		checks = append(checks, preflightCheck("Schedule", dbbackupv1alpha1.PreflightPass, "Schedule %q parses", spec.Schedule))
+	}
+
+	if spec.DatabaseRef != nil {
+		if resolved, err := r.resolveDatabaseRef(ctx, dbBackup); err != nil {
+			checks = append(checks, preflightCheck("DatabaseRef", dbbackupv1alpha1.PreflightFail, "%v", err))
+		} else {
+			checks = append(checks, preflightCheck("DatabaseRef", dbbackupv1alpha1.PreflightPass, "Resolved to host %s", resolved.Host))
+		}
+	} else {
+		checks = append(checks, preflightCheck("DatabaseRef", dbbackupv1alpha1.PreflightSkipped, "No databaseRef configured"))
+	}
+
+	selector := spec.DatabaseSelector
+	pods, err := r.findTargetPods(ctx, dbBackup)
+	switch {
+	case spec.DatabaseRef != nil && len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0:
+		checks = append(checks, preflightCheck("TargetPods", dbbackupv1alpha1.PreflightSkipped, "No databaseSelector set, the database comes from databaseRef"))
+	case err != nil:
+		checks = append(checks, preflightCheck("TargetPods", dbbackupv1alpha1.PreflightFail, "%v", err))
+	case len(pods) == 0:
+		checks = append(checks, preflightCheck("TargetPods", dbbackupv1alpha1.PreflightFail, "No pods match the database selector"))
+	default:
+		checks = append(checks, preflightCheck("TargetPods", dbbackupv1alpha1.PreflightPass, "%d pods match the database selector", len(pods)))
+	}
+
+	// The storage secret is read from its source, since the local copy of a
+	// cross-namespace secret only exists once reconciled
+	defaulted := r.withStorageDefaults(dbBackup)
+	dest := defaulted.Spec.StorageDestination
+	var storageSecret *corev1.Secret
+	if dest.SecretName == "" {
+		checks = append(checks, preflightCheck("StorageSecret", dbbackupv1alpha1.PreflightSkipped, "No storage secret configured"))
+	} else {
+		secretName := types.NamespacedName{Name: dest.SecretName, Namespace: dbBackup.Namespace}
+		if dest.SecretNamespace != "" {
+			secretName.Namespace = dest.SecretNamespace
+		}
+		var secret corev1.Secret
+		if err := r.apiReader().Get(ctx, secretName, &secret); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, err
+			}
+			checks = append(checks, preflightCheck("StorageSecret", dbbackupv1alpha1.PreflightFail, "Storage secret %s not found", secretName))
+		} else {
+			storageSecret = &secret
+			checks = append(checks, preflightCheck("StorageSecret", dbbackupv1alpha1.PreflightPass, "Storage secret %s found", secretName))
+		}
+	}
+
+	if dest.Type != "pvc" {
+		checks = append(checks, preflightCheck("StoragePVC", dbbackupv1alpha1.PreflightSkipped, "Storage type is %s", dest.Type))
+	} else if dest.PVCName == "" {
+		checks = append(checks, preflightCheck("StoragePVC", dbbackupv1alpha1.PreflightFail, "No pvcName set for pvc storage"))
+	} else {
+		var pvc corev1.PersistentVolumeClaim
+		if err := r.Get(ctx, types.NamespacedName{Name: dest.PVCName, Namespace: dbBackup.Namespace}, &pvc); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, err
+			}
+			checks = append(checks, preflightCheck("StoragePVC", dbbackupv1alpha1.PreflightFail, "PVC %s not found", dest.PVCName))
+		} else if pvc.Status.Phase != corev1.ClaimBound {
+			checks = append(checks, preflightCheck("StoragePVC", dbbackupv1alpha1.PreflightFail, "PVC %s is %s, not Bound", dest.PVCName, pvc.Status.Phase))
+		} else {
+			checks = append(checks, preflightCheck("StoragePVC", dbbackupv1alpha1.PreflightPass, "PVC %s is Bound", dest.PVCName))
+		}
+	}
+
+	checks = append(checks, r.preflightCredentialKeys(ctx, defaulted, storageSecret)...)
+	return checks, nil
+}
+
+// Helper function to check that the secrets backup pods read keys from
+// actually hold them
+func (r *DatabaseBackupReconciler) preflightCredentialKeys(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, storageSecret *corev1.Secret) []dbbackupv1alpha1.PreflightCheck {
+	var checks []dbbackupv1alpha1.PreflightCheck
+
+	switch {
+	case dbBackup.Spec.StorageDestination.SecretName == "":
+		checks = append(checks, preflightCheck("StorageCredentials", dbbackupv1alpha1.PreflightSkipped, "No storage secret configured"))
+	case storageSecret == nil:
+		checks = append(checks, preflightCheck("StorageCredentials", dbbackupv1alpha1.PreflightFail, "Storage secret is missing"))
+	case len(storageSecret.Data) == 0:
+		checks = append(checks, preflightCheck("StorageCredentials", dbbackupv1alpha1.PreflightFail, "Storage secret %s has no keys", storageSecret.Name))
+	default:
+		checks = append(checks, preflightCheck("StorageCredentials", dbbackupv1alpha1.PreflightPass, "Storage secret %s has %d keys", storageSecret.Name, len(storageSecret.Data)))
+	}
+
+	if tls := dbBackup.Spec.TLSConfig; tls == nil {
+		checks = append(checks, preflightCheck("DatabaseTLS", dbbackupv1alpha1.PreflightSkipped, "Database TLS is not configured"))
+	} else if err := r.validateTLSSecret(ctx, dbBackup); err != nil {
+		checks = append(checks, preflightCheck("DatabaseTLS", dbbackupv1alpha1.PreflightFail, "%v", err))
+	} else {
+		checks = append(checks, preflightCheck("DatabaseTLS", dbbackupv1alpha1.PreflightPass, "TLS secret %s has ca.crt, tls.crt and tls.key", tls.SecretName))
+	}
+
+	encryption := dbBackup.Spec.Encryption
+	if encryption == nil {
+		return append(checks, preflightCheck("EncryptionKey", dbbackupv1alpha1.PreflightSkipped, "Encryption is not configured"))
+	}
+	key := encryption.Key
+	if key == "" {
+		key = dbbackupv1alpha1.DefaultEncryptionKey
+	}
+	var secret corev1.Secret
+	if err := r.apiReader().Get(ctx, types.NamespacedName{Name: encryption.SecretName, Namespace: dbBackup.Namespace}, &secret); err != nil {
+		return append(checks, preflightCheck("EncryptionKey", dbbackupv1alpha1.PreflightFail, "Encryption key secret %s: %v", encryption.SecretName, err))
+	}
+	if len(secret.Data[key]) == 0 {
+		return append(checks, preflightCheck("EncryptionKey", dbbackupv1alpha1.PreflightFail, "Encryption key secret %s has no %q key", encryption.SecretName, key))
+	}
+	return append(checks, preflightCheck("EncryptionKey", dbbackupv1alpha1.PreflightPass, "Encryption key secret %s has key %q", encryption.SecretName, key))
+}
+
+// Helper function to check if every preflight check passed or was skipped
+func preflightPassed(checks []dbbackupv1alpha1.PreflightCheck) bool {
+	for _, check := range checks {
+		if check.Result == dbbackupv1alpha1.PreflightFail {
+			return false
+		}
+	}
+	return true
+}
+
+// Helper function to record preflight results in status
+func recordPreflight(dbBackup *dbbackupv1alpha1.DatabaseBackup, checks []dbbackupv1alpha1.PreflightCheck) {
+	now := metav1.Now()
+	dbBackup.Status.Preflight = &dbbackupv1alpha1.PreflightStatus{
+		Time:   now,
+		Passed: preflightPassed(checks),
+		Checks: checks,
+	}
+}
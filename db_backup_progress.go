@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"path"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+const (
+	// progressDir is the shared volume the backup image writes progress to
+	progressDir = "/progress"
+
+	// progressFile is the progress file within progressDir
+	progressFile = "progress.json"
+
+	// progressReadTimeout bounds reading the progress file of a running backup
+	progressReadTimeout = 5 * time.Second
+)
+
+// backupProgress is what the backup image writes to its progress file
+type backupProgress struct {
+	BytesTransferred *int64 `json:"bytesTransferred,omitempty"`
+	Percent          *int32 `json:"percent,omitempty"`
+}
+
+// Helper function to give the backup container a progress file to write to
+func addProgressFile(podSpec *corev1.PodSpec) {
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "backup-progress",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      "backup-progress",
+		MountPath: progressDir,
+	})
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  "PROGRESS_FILE",
+		Value: path.Join(progressDir, progressFile),
+	})
+}
+
+// Helper function to read the progress of a running backup Job. Returns nil
+// if the image hasn't written any (or doesn't support progress)
+func (r *DatabaseBackupReconciler) readBackupProgress(ctx context.Context, job *batchv1.Job) (*backupProgress, error) {
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList,
+		client.InNamespace(job.Namespace),
+		client.MatchingLabels{"job-name": job.Name},
+	); err != nil {
+		return nil, err
+	}
+
+	var pod *corev1.Pod
+	for i := range podList.Items {
+		if podList.Items[i].Status.Phase == corev1.PodRunning {
+			pod = &podList.Items[i]
+			break
+		}
+	}
+	if pod == nil {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, progressReadTimeout)
+	defer cancel()
+	var stdout bytes.Buffer
+	command := []string{"cat", path.Join(progressDir, progressFile)}
+	if err := r.Executor.Exec(ctx, pod.Namespace, pod.Name, "backup", command, &stdout, io.Discard); err != nil {
+		// Most likely no progress written yet
+		return nil, nil
+	}
+
+	var progress backupProgress
+	if err := json.Unmarshal(stdout.Bytes(), &progress); err != nil {
+		return nil, nil
+	}
+	if progress.Percent != nil && (*progress.Percent < 0 || *progress.Percent > 100) {
+		progress.Percent = nil
+	}
+	return &progress, nil
+}
+
+// Helper function to record backup progress in status. Returns true if it changed
+func recordBackupProgress(dbBackup *dbbackupv1alpha1.DatabaseBackup, progress *backupProgress) bool {
+	var percent *int32
+	var bytesTransferred *int64
+	if progress != nil {
+		percent = progress.Percent
+		bytesTransferred = progress.BytesTransferred
+	}
+
+	status := &dbBackup.Status
+	if equalInt32Ptr(status.BackupProgressPercent, percent) && equalInt64Ptr(status.BackupBytesTransferred, bytesTransferred) {
+		return false
+	}
+	status.BackupProgressPercent = percent
+	status.BackupBytesTransferred = bytesTransferred
+	return true
+}
+
+// Helper function to compare optional int32 values
+func equalInt32Ptr(a, b *int32) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+// Helper function to compare optional int64 values
+func equalInt64Ptr(a, b *int64) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
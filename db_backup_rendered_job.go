@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// renderedJobLimit caps the rendered Job kept in status
+const renderedJobLimit = 8192
+
+// renderedJobTruncated marks a rendered Job cut short at renderedJobLimit
+const renderedJobTruncated = "# truncated\n"
+
+// Helper function to render a created backup Job as YAML for support
+// requests, without server-managed fields and cut at renderedJobLimit
+func renderJob(job *batchv1.Job) (string, error) {
+	rendered := job.DeepCopy()
+	rendered.APIVersion = batchv1.SchemeGroupVersion.String()
+	rendered.Kind = "Job"
+	rendered.ManagedFields = nil
+	rendered.Status = batchv1.JobStatus{}
+
+	data, err := yaml.Marshal(rendered)
+	if err != nil {
+		return "", err
+	}
+	return truncateRenderedJob(string(data)), nil
+}
+
+// Helper function to cut a rendered Job at the last full line that fits in
+// renderedJobLimit, marking it truncated
+func truncateRenderedJob(rendered string) string {
+	if len(rendered) <= renderedJobLimit {
+		return rendered
+	}
+	cut := rendered[:renderedJobLimit-len(renderedJobTruncated)]
+	if i := strings.LastIndexByte(cut, '\n'); i >= 0 {
+		cut = cut[:i+1]
+	}
+	return cut + renderedJobTruncated
+}
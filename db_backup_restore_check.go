@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+const (
+	// restoreTestTTL is how long finished restore-test Jobs (and their
+	// throwaway database) are kept around for inspection
+	restoreTestTTL int32 = 3600
+
+	// restoreTestPollInterval is how often a due restore test waiting on a
+	// running one is re-checked
+	restoreTestPollInterval = time.Minute
+
+	// restoreArtifactAnnotation records on a restore-test Job which artifact
+	// it restores
+	restoreArtifactAnnotation = "db.example.io/restore-artifact"
+)
+
+// Helper function to schedule restore-test Jobs and record their outcome.
+// Returns how long until the next restore test is due, or zero if none is
+// scheduled.
+func (r *DatabaseBackupReconciler) reconcileRestoreTest(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (time.Duration, error) {
+	log := log.FromContext(ctx)
+	restoreTest := dbBackup.Spec.RestoreTest
+
+	// Check on a running restore test
+	if dbBackup.Status.ActiveRestoreTestJob != "" {
+		var job batchv1.Job
+		jobName := types.NamespacedName{
+			Name:      dbBackup.Status.ActiveRestoreTestJob,
+			Namespace: dbBackup.Namespace,
+		}
+
+		err := r.Get(ctx, jobName, &job)
+		if err != nil && !errors.IsNotFound(err) {
+			return 0, err
+		}
+
+		pullFailure := ""
+		if err == nil && !isJobComplete(&job) {
+			if pullFailure, err = r.abortImagePull(ctx, dbBackup, &job); err != nil {
+				return 0, err
+			}
+		}
+
+		if errors.IsNotFound(err) || isJobComplete(&job) || pullFailure != "" {
+			if err == nil && pullFailure == "" && isJobSuccessful(&job) {
+				now := metav1.Now()
+				dbBackup.Status.LastSuccessfulRestoreTest = &now
+				dbBackup.Status.LastRestoreTestStatus = "Succeeded"
+				dbBackup.Status.LastVerifiedArtifact = job.Annotations[restoreArtifactAnnotation]
+			} else {
+				dbBackup.Status.LastRestoreTestStatus = "Failed"
+			}
+			dbBackup.Status.ActiveRestoreTestJob = ""
+		}
+	}
+
+	if restoreTest == nil {
+		return 0, nil
+	}
+
+	schedule, err := cron.ParseStandard(restoreTest.Schedule)
+	if err != nil {
+		log.Error(err, "Failed to parse restore test schedule", "schedule", restoreTest.Schedule)
+		dbBackup.Status.LastRestoreTestStatus = "Error"
+		return 0, nil
+	}
+
+	// Start the schedule from now the first time round
+	if dbBackup.Status.NextRestoreTest == nil {
+		next := metav1.NewTime(schedule.Next(time.Now()))
+		dbBackup.Status.NextRestoreTest = &next
+	}
+
+	if !isTimeToBackup(dbBackup.Status.NextRestoreTest) {
+		return time.Until(dbBackup.Status.NextRestoreTest.Time), nil
+	}
+	if dbBackup.Status.ActiveRestoreTestJob != "" {
+		return restoreTestPollInterval, nil
+	}
+
+	// Only test restores when there is a backup recent enough to be retained
+	if isRecentBackup(dbBackup) {
+		job, err := r.createRestoreTestJob(ctx, dbBackup, dbBackup.Status.NextRestoreTest.Time)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create restore test job: %w", err)
+		}
+		dbBackup.Status.ActiveRestoreTestJob = job.Name
+		dbBackup.Status.LastRestoreTestStatus = "Running"
+	} else {
+		log.Info("No recent backup to restore, skipping restore test")
+	}
+
+	next := metav1.NewTime(schedule.Next(time.Now()))
+	dbBackup.Status.NextRestoreTest = &next
+	return time.Until(next.Time), nil
+}
+
+// Helper function to check if the last successful backup is still within
+// the retention period
+func isRecentBackup(dbBackup *dbbackupv1alpha1.DatabaseBackup) bool {
+	if dbBackup.Status.LastSuccessfulBackup == nil {
+		return false
+	}
+	retention, expires := backupRetention(dbBackup)
+	return !expires || time.Since(dbBackup.Status.LastSuccessfulBackup.Time) < retention
+}
+
+// Helper function to create a Job that restores the latest backup into an
+// ephemeral database, runs a sanity query and exits
+func (r *DatabaseBackupReconciler) createRestoreTestJob(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, scheduledTime time.Time) (*batchv1.Job, error) {
+	restoreTest := dbBackup.Spec.RestoreTest
+
+	image := restoreTest.Image
+	if image == "" {
+		image = getRestoreTestImage(dbBackup.Spec.DatabaseType)
+	}
+
+	backoffLimit := int32(0)
+	ttl := restoreTestTTL
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-restore-%s", dbBackup.Name, scheduledTime.UTC().Format("200601021504")),
+			Namespace:   dbBackup.Namespace,
+			Annotations: map[string]string{},
+			Labels: map[string]string{
+				"app":           "db-backup-operator",
+				backupNameLabel: dbBackup.Name,
+				jobKindLabel:    "restore-test",
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:    corev1.RestartPolicyNever,
+					ImagePullSecrets: dbBackup.Spec.ImagePullSecrets,
+					Containers: []corev1.Container{
+						{
+							Name:  "restore-test",
+							Image: image,
+							Env: []corev1.EnvVar{
+								{
+									Name:  "DB_TYPE",
+									Value: dbBackup.Spec.DatabaseType,
+								},
+								{
+									Name:  "STORAGE_TYPE",
+									Value: dbBackup.Spec.StorageDestination.Type,
+								},
+								{
+									Name:  "BUCKET",
+									Value: dbBackup.Spec.StorageDestination.Bucket,
+								},
+								{
+									Name:  "PATH",
+									Value: dbBackup.Spec.StorageDestination.Path,
+								},
+								{
+									Name:  "SANITY_QUERY",
+									Value: restoreTest.SanityQuery,
+								},
+								{
+									Name:  "FILE_EXTENSION",
+									Value: artifactExtension(dbBackup),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	addStorageVolumes(&job.Spec.Template.Spec, r.withStorageDefaults(dbBackup))
+	r.addImagePullSecret(&job.Spec.Template.Spec, dbBackup)
+
+	// Restore the artifact of the last successful backup rather than
+	// whatever is newest in storage, so it matches the key id below
+	if location := dbBackup.Status.LastBackupLocation; location != "" {
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "BACKUP_LOCATION",
+			Value: location,
+		})
+		job.Annotations[restoreArtifactAnnotation] = location
+	}
+
+	// Decrypt with the key version the latest backup was encrypted with
+	if dbBackup.Spec.Encryption != nil {
+		addEncryptionKey(&job.Spec.Template.Spec, dbBackup, dbBackup.Status.LastBackupKeyID)
+	}
+
+	if err := ctrl.SetControllerReference(dbBackup, job, r.Scheme); err != nil {
+		return nil, err
+	}
+
+	if err := r.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Helper function to get the restore-test image based on DB type
+func getRestoreTestImage(dbType string) string {
+	switch dbType {
+	case "postgres":
+		return "ghcr.io/example/postgres-restore-test:latest"
+	case "mysql":
+		return "ghcr.io/example/mysql-restore-test:latest"
+	case "mongodb":
+		return "ghcr.io/example/mongodb-restore-test:latest"
+	case "sqlite":
+		return "ghcr.io/example/sqlite-restore-test:latest"
+	default:
+		return "ghcr.io/example/generic-restore-test:latest"
+	}
+}
@@ -0,0 +1,312 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+const (
+	// reuploadTTL is how long finished re-upload Jobs are kept around for inspection
+	reuploadTTL int32 = 3600
+
+	// reuploadPollInterval is how often a running re-upload is re-checked
+	reuploadPollInterval = 30 * time.Second
+
+	// reuploadBackoff is how long after a failed re-upload the next one is
+	// tried, multiplied by the attempts so far
+	reuploadBackoff = time.Minute
+
+	// stagingMountPath is where the staging volume is mounted in backup
+	// and re-upload pods
+	stagingMountPath = "/staging"
+)
+
+// Helper function to mount the staging volume backups keep their artifact
+// on until every destination has it. Each run stages under its own directory
+func addStagingVolume(podSpec *corev1.PodSpec, dbBackup *dbbackupv1alpha1.DatabaseBackup, run string) {
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "staging",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: dbBackup.Spec.ReuploadFailedDestinations.StagingPVCName,
+			},
+		},
+	})
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      "staging",
+		MountPath: stagingMountPath,
+	})
+	podSpec.Containers[0].Env = append(podSpec.Containers[0].Env,
+		corev1.EnvVar{
+			Name:  "STAGING_DIR",
+			Value: stagingMountPath,
+		},
+		corev1.EnvVar{
+			Name:  "STAGING_RUN",
+			Value: run,
+		},
+	)
+}
+
+// Helper function to have a new backup run remove the staged artifact of a
+// re-upload it supersedes, which nothing would clean up otherwise
+func addSupersededStagedArtifact(podSpec *corev1.PodSpec, dbBackup *dbbackupv1alpha1.DatabaseBackup) {
+	pending := dbBackup.Status.PendingReupload
+	if pending == nil {
+		return
+	}
+	podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, corev1.EnvVar{
+		Name:  "SUPERSEDED_STAGED_ARTIFACT",
+		Value: path.Join(stagingMountPath, pending.Artifact),
+	})
+}
+
+// Helper function to queue a re-upload of a partially failed backup's
+// staged artifact. Without a staged artifact the failure stands until the
+// next backup
+func queueReupload(dbBackup *dbbackupv1alpha1.DatabaseBackup, report *backupReport, failed []string) bool {
+	if dbBackup.Spec.ReuploadFailedDestinations == nil || report == nil || report.StagedArtifact == "" {
+		return false
+	}
+	dbBackup.Status.PendingReupload = &dbbackupv1alpha1.PendingReupload{
+		Artifact:     report.StagedArtifact,
+		Destinations: failed,
+	}
+	return true
+}
+
+// Helper function to re-upload a staged artifact to the destinations that
+// failed to receive it, and record the outcome. Returns how soon to check
+// back on a running or backed-off re-upload, or zero if none is pending
+func (r *DatabaseBackupReconciler) reconcileReupload(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (time.Duration, error) {
+	log := log.FromContext(ctx)
+	status := &dbBackup.Status
+
+	// Check on a running re-upload
+	if status.ActiveReuploadJob != "" {
+		var job batchv1.Job
+		err := r.Get(ctx, types.NamespacedName{Name: status.ActiveReuploadJob, Namespace: dbBackup.Namespace}, &job)
+		if err != nil && !errors.IsNotFound(err) {
+			return 0, err
+		}
+		pullFailure := ""
+		if err == nil && !isJobComplete(&job) {
+			if pullFailure, err = r.abortImagePull(ctx, dbBackup, &job); err != nil {
+				return 0, err
+			}
+			if pullFailure == "" {
+				return reuploadPollInterval, nil
+			}
+		}
+
+		var report *backupReport
+		if err == nil && pullFailure == "" {
+			if report, err = r.readBackupReport(ctx, &job); err != nil {
+				return 0, err
+			}
+		}
+		jobSucceeded := err == nil && pullFailure == "" && isJobSuccessful(&job)
+		status.ActiveReuploadJob = ""
+		if status.PendingReupload != nil {
+			r.recordReuploadOutcome(dbBackup, report, jobSucceeded)
+		}
+	}
+
+	pending := status.PendingReupload
+	if pending == nil {
+		return 0, nil
+	}
+	if dbBackup.Spec.ReuploadFailedDestinations == nil {
+		log.Info("Re-uploads disabled, dropping pending re-upload", "artifact", pending.Artifact)
+		status.PendingReupload = nil
+		return 0, nil
+	}
+	if pending.LastAttemptTime != nil {
+		if wait := time.Until(pending.LastAttemptTime.Add(time.Duration(pending.Attempts) * reuploadBackoff)); wait > 0 {
+			return wait, nil
+		}
+	}
+
+	// Re-uploads are held during a global pause like backups. Clearing the
+	// pause reconciles every DatabaseBackup again
+	if meta.IsStatusConditionTrue(status.Conditions, dbbackupv1alpha1.ConditionGloballyPaused) {
+		log.V(1).Info("Backups globally paused, deferring re-upload", "artifact", pending.Artifact)
+		return 0, nil
+	}
+
+	job, err := r.createReuploadJob(ctx, dbBackup)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create re-upload job: %w", err)
+	}
+	log.Info("Re-uploading staged artifact", "artifact", pending.Artifact, "destinations", pending.Destinations, "job_name", job.Name)
+	status.ActiveReuploadJob = job.Name
+	return reuploadPollInterval, nil
+}
+
+// Helper function to record which destinations a finished re-upload
+// reached. The backup counts as succeeded once every destination has it;
+// after MaxAttempts the re-upload is given up on
+func (r *DatabaseBackupReconciler) recordReuploadOutcome(dbBackup *dbbackupv1alpha1.DatabaseBackup, report *backupReport, jobSucceeded bool) {
+	status := &dbBackup.Status
+	pending := status.PendingReupload
+	pending.Attempts++
+	now := metav1.Now()
+	pending.LastAttemptTime = &now
+
+	reported := map[string]destinationReport{}
+	if report != nil {
+		for _, dest := range report.Destinations {
+			reported[dest.Name] = dest
+		}
+	}
+
+	var failed []string
+	for _, name := range pending.Destinations {
+		succeeded, message := jobSucceeded, ""
+		if dest, ok := reported[name]; ok {
+			succeeded, message = dest.Status == "Succeeded", dest.Message
+		}
+		if !succeeded {
+			failed = append(failed, name)
+		}
+		for i := range status.Destinations {
+			if status.Destinations[i].Name != name {
+				continue
+			}
+			status.Destinations[i].Message = message
+			if succeeded {
+				status.Destinations[i].LastBackupStatus = "Succeeded"
+				status.Destinations[i].LastSuccessfulBackup = &now
+			}
+		}
+	}
+
+	switch {
+	case len(failed) == 0:
+		r.Recorder.Eventf(dbBackup, corev1.EventTypeNormal, "ReuploadSucceeded", "Re-uploaded %s to %v", pending.Artifact, pending.Destinations)
+		status.PendingReupload = nil
+		if status.LastBackupStatus == "PartiallyFailed" {
+			status.LastSuccessfulBackup = &now
+			status.LastBackupStatus = "Succeeded"
+			status.FailureReason = ""
+			status.FailureCode = ""
+			status.ConsecutiveFailures = 0
+		}
+	case pending.Attempts >= reuploadAttempts(dbBackup):
+		r.Recorder.Eventf(dbBackup, corev1.EventTypeWarning, "ReuploadFailed", "Giving up re-uploading %s after %d attempts", pending.Artifact, pending.Attempts)
+		status.PendingReupload = nil
+		if status.LastBackupStatus == "PartiallyFailed" {
+			status.FailureReason = describeFailedDestinations(failed)
+		}
+	default:
+		pending.Destinations = failed
+	}
+}
+
+// Helper function to get how many re-uploads are tried
+func reuploadAttempts(dbBackup *dbbackupv1alpha1.DatabaseBackup) int32 {
+	if spec := dbBackup.Spec.ReuploadFailedDestinations; spec != nil && spec.MaxAttempts > 0 {
+		return spec.MaxAttempts
+	}
+	return dbbackupv1alpha1.DefaultReuploadAttempts
+}
+
+// Helper function to create a Job that uploads the staged artifact to the
+// pending destinations only, without dumping the database
+func (r *DatabaseBackupReconciler) createReuploadJob(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (*batchv1.Job, error) {
+	pending := dbBackup.Status.PendingReupload
+
+	backoffLimit := int32(0)
+	ttl := reuploadTTL
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-reupload-%s", dbBackup.Name, time.Now().UTC().Format("20060102150405")),
+			Namespace: dbBackup.Namespace,
+			Labels: map[string]string{
+				"app":           "db-backup-operator",
+				backupNameLabel: dbBackup.Name,
+				jobKindLabel:    "reupload",
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy:    corev1.RestartPolicyNever,
+					ImagePullSecrets: dbBackup.Spec.ImagePullSecrets,
+					Containers: []corev1.Container{
+						{
+							// Named like the backup container so its report is read the same way
+							Name:  "backup",
+							Image: getBackupImage(dbBackup.Spec.DatabaseType),
+							Env: []corev1.EnvVar{
+								{
+									Name:  "DB_TYPE",
+									Value: dbBackup.Spec.DatabaseType,
+								},
+								{
+									Name:  "REUPLOAD_ONLY",
+									Value: "true",
+								},
+								{
+									Name:  "STAGED_ARTIFACT",
+									Value: path.Join(stagingMountPath, pending.Artifact),
+								},
+								{
+									Name:  "STORAGE_TYPE",
+									Value: dbBackup.Spec.StorageDestination.Type,
+								},
+								{
+									Name:  "BUCKET",
+									Value: dbBackup.Spec.StorageDestination.Bucket,
+								},
+								{
+									Name:  "PATH",
+									Value: dbBackup.Spec.StorageDestination.Path,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// The last attempt removes the staged artifact whatever the outcome
+	if pending.Attempts+1 >= reuploadAttempts(dbBackup) {
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{
+			Name:  "CLEANUP_STAGED",
+			Value: "true",
+		})
+	}
+
+	addStorageVolumes(&job.Spec.Template.Spec, r.withStorageDefaults(dbBackup))
+	addStagingVolume(&job.Spec.Template.Spec, dbBackup, path.Dir(pending.Artifact))
+	if err := addSomeDestinationsConfig(&job.Spec.Template.Spec, dbBackup, pending.Destinations); err != nil {
+		return nil, fmt.Errorf("failed to encode storage destinations: %w", err)
+	}
+	r.addImagePullSecret(&job.Spec.Template.Spec, dbBackup)
+
+	if err := ctrl.SetControllerReference(dbBackup, job, r.Scheme); err != nil {
+		return nil, err
+	}
+
+	if err := r.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
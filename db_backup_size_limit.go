@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// Helper function to get the size of the target database, as the capacity
+// of the PVC backing it. The capacity bounds the data on it, so a database
+// is never judged smaller than it could be
+func (r *DatabaseBackupReconciler) databaseSize(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (int64, error) {
+	pvcName, err := r.findTargetPVC(ctx, dbBackup)
+	if err != nil {
+		return 0, err
+	}
+
+	var pvc corev1.PersistentVolumeClaim
+	if err := r.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: dbBackup.Namespace}, &pvc); err != nil {
+		return 0, err
+	}
+
+	// Capacity is only reported once bound; fall back to the request
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		return capacity.Value(), nil
+	}
+	if request, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		return request.Value(), nil
+	}
+	return 0, fmt.Errorf("pvc %s reports no storage size", pvcName)
+}
+
+// Helper function to check the target database against MaxDatabaseSizeBytes.
+// Returns the size and whether it is over the limit
+func (r *DatabaseBackupReconciler) exceedsSizeLimit(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (int64, bool, error) {
+	size, err := r.databaseSize(ctx, dbBackup)
+	if err != nil {
+		return 0, false, err
+	}
+	return size, size > *dbBackup.Spec.MaxDatabaseSizeBytes, nil
+}
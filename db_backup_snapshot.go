@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// snapshotPollInterval is how often an in-progress VolumeSnapshot is checked.
+// Snapshots are polled rather than watched so the controller still starts on
+// clusters without the snapshot CRDs installed.
+const snapshotPollInterval = 15 * time.Second
+
+//+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch
+
+// Helper function to check if a DatabaseBackup uses VolumeSnapshots
+func isSnapshotMode(dbBackup *dbbackupv1alpha1.DatabaseBackup) bool {
+	return dbBackup.Spec.Mode == "snapshot"
+}
+
+// Helper function to find the PVC backing the target database pod
+func (r *DatabaseBackupReconciler) findTargetPVC(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (string, error) {
+	pods, err := r.findTargetPods(ctx, dbBackup)
+	if err != nil {
+		return "", err
+	}
+	if len(pods) == 0 {
+		return "", fmt.Errorf("no pods match the database selector")
+	}
+
+	// Prefer a ready pod, but any matching pod identifies the volume. A
+	// preferred role is honored since replicas have volumes of their own
+	pod := pods[0]
+	if dbBackup.Spec.PreferRole != nil {
+		target, err := selectTargetPod(pods, dbBackup.Spec.PreferRole)
+		if err != nil {
+			return "", err
+		}
+		pod = *target
+	} else {
+		for i := range pods {
+			if isPodReady(&pods[i]) {
+				pod = pods[i]
+				break
+			}
+		}
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			return volume.PersistentVolumeClaim.ClaimName, nil
+		}
+	}
+	return "", fmt.Errorf("pod %s has no persistent volume claim", pod.Name)
+}
+
+// Helper function to create a VolumeSnapshot of the database's PVC
+func (r *DatabaseBackupReconciler) createVolumeSnapshot(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, scheduledTime time.Time, manual bool) (*snapshotv1.VolumeSnapshot, error) {
+	pvcName, err := r.findTargetPVC(ctx, dbBackup)
+	if err != nil {
+		return nil, err
+	}
+
+	name := backupJobName(dbBackup, scheduledTime)
+	if manual {
+		name = manualRunName(dbBackup)
+	}
+	snapshot := &snapshotv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: dbBackup.Namespace,
+			Labels: map[string]string{
+				"app":           "db-backup-operator",
+				backupNameLabel: dbBackup.Name,
+			},
+		},
+		Spec: snapshotv1.VolumeSnapshotSpec{
+			Source: snapshotv1.VolumeSnapshotSource{
+				PersistentVolumeClaimName: &pvcName,
+			},
+			VolumeSnapshotClassName: dbBackup.Spec.VolumeSnapshotClassName,
+		},
+	}
+
+	if err := ctrl.SetControllerReference(dbBackup, snapshot, r.Scheme); err != nil {
+		return nil, err
+	}
+
+	if err := r.Create(ctx, snapshot); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return nil, err
+		}
+
+		// A previous reconcile already created the snapshot for this slot, adopt it
+		var existing snapshotv1.VolumeSnapshot
+		if err := r.Get(ctx, client.ObjectKeyFromObject(snapshot), &existing); err != nil {
+			return nil, err
+		}
+		if !metav1.IsControlledBy(&existing, dbBackup) {
+			return nil, fmt.Errorf("volume snapshot %s already exists and is not owned by this DatabaseBackup", snapshot.Name)
+		}
+		return &existing, nil
+	}
+
+	return snapshot, nil
+}
+
+// Helper function to abort the VolumeSnapshot in progress on request by
+// deleting it
+func (r *DatabaseBackupReconciler) cancelSnapshot(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) error {
+	snapshotName := dbBackup.Status.ActiveSnapshot
+	snapshot := &snapshotv1.VolumeSnapshot{ObjectMeta: metav1.ObjectMeta{Name: snapshotName, Namespace: dbBackup.Namespace}}
+	if err := r.Delete(ctx, snapshot); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Cancelled volume snapshot on request", "snapshot", snapshotName)
+	r.Recorder.Eventf(dbBackup, corev1.EventTypeNormal, "BackupCancelled", "Cancelled volume snapshot %s on request", snapshotName)
+
+	dbBackup.Status.LastBackupStatus = "Cancelled"
+	finishManualBackup(dbBackup, snapshotName)
+	dbBackup.Status.ActiveSnapshot = ""
+	return nil
+}
+
+// Helper function to record the outcome of the active VolumeSnapshot once it
+// is ready or has failed. In-progress snapshots are left untouched.
+func (r *DatabaseBackupReconciler) syncActiveSnapshot(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) error {
+	var snapshot snapshotv1.VolumeSnapshot
+	snapshotName := types.NamespacedName{
+		Name:      dbBackup.Status.ActiveSnapshot,
+		Namespace: dbBackup.Namespace,
+	}
+
+	err := r.Get(ctx, snapshotName, &snapshot)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	switch {
+	case errors.IsNotFound(err):
+		dbBackup.Status.LastBackupStatus = "Failed"
+		dbBackup.Status.FailureReason = "Volume snapshot was deleted before it became ready"
+		dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureSnapshotFailed
+	case snapshot.Status != nil && snapshot.Status.ReadyToUse != nil && *snapshot.Status.ReadyToUse:
+		now := metav1.Now()
+		dbBackup.Status.LastSuccessfulBackup = &now
+		dbBackup.Status.LastBackupStatus = "Succeeded"
+		dbBackup.Status.FailureReason = ""
+		dbBackup.Status.FailureCode = ""
+		dbBackup.Status.ConsecutiveFailures = 0
+	case snapshot.Status != nil && snapshot.Status.Error != nil:
+		dbBackup.Status.LastBackupStatus = "Failed"
+		dbBackup.Status.FailureReason = "Volume snapshot failed"
+		dbBackup.Status.FailureCode = dbbackupv1alpha1.FailureSnapshotFailed
+		if snapshot.Status.Error.Message != nil {
+			dbBackup.Status.FailureReason = fmt.Sprintf("Volume snapshot failed: %s", *snapshot.Status.Error.Message)
+		}
+		recordBackupFailure(dbBackup)
+	default:
+		return nil
+	}
+
+	if dbBackup.Spec.AuditLog {
+		entry := newAuditEntry(dbBackup, dbBackup.Status.ActiveSnapshot, dbBackup.Status.ActiveSnapshot,
+			dbBackup.Status.ManualBackupRun == dbBackup.Status.ActiveSnapshot)
+		if snapshot.Status != nil && snapshot.Status.RestoreSize != nil {
+			size := snapshot.Status.RestoreSize.Value()
+			entry.Spec.SizeBytes = &size
+		}
+		if err := r.recordAuditEntry(ctx, dbBackup, entry); err != nil {
+			return err
+		}
+	}
+
+	finishManualBackup(dbBackup, dbBackup.Status.ActiveSnapshot)
+	dbBackup.Status.ActiveSnapshot = ""
+	return nil
+}
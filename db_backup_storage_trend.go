@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// Helper function to compute the storage usage trend from audit entries.
+// Only successful runs with a reported size that are still within retention
+// count. Returns nil when there are none
+func computeStorageUsageTrend(entries []dbbackupv1alpha1.BackupAuditLog, retention time.Duration, expires bool, now time.Time) *dbbackupv1alpha1.StorageUsageTrend {
+	var retained []dbbackupv1alpha1.BackupAuditLogSpec
+	for _, entry := range entries {
+		spec := entry.Spec
+		if spec.SizeBytes == nil || (spec.Outcome != "Succeeded" && spec.Outcome != "PartiallyFailed") {
+			continue
+		}
+		if expires && now.Sub(spec.CompletionTime.Time) >= retention {
+			continue
+		}
+		retained = append(retained, spec)
+	}
+	if len(retained) == 0 {
+		return nil
+	}
+	sort.Slice(retained, func(i, j int) bool {
+		return retained[i].CompletionTime.Before(&retained[j].CompletionTime)
+	})
+
+	oldest, latest := retained[0], retained[len(retained)-1]
+	trend := &dbbackupv1alpha1.StorageUsageTrend{
+		RetainedBackups: int32(len(retained)),
+		LatestBytes:     *latest.SizeBytes,
+		Since:           oldest.CompletionTime,
+	}
+	for _, spec := range retained {
+		trend.TotalBytes += *spec.SizeBytes
+	}
+
+	// Growth over less than a day would be mostly noise
+	if days := latest.CompletionTime.Sub(oldest.CompletionTime.Time).Hours() / 24; days >= 1 {
+		growth := int64(float64(*latest.SizeBytes-*oldest.SizeBytes) / days)
+		trend.AverageDailyGrowthBytes = &growth
+	}
+	return trend
+}
+
+// Helper function to recompute the storage usage trend from the
+// DatabaseBackup's audit log. latest is the entry just written, which the
+// cache may not have caught up with yet
+func (r *DatabaseBackupReconciler) updateStorageUsageTrend(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, latest *dbbackupv1alpha1.BackupAuditLog) error {
+	var entries dbbackupv1alpha1.BackupAuditLogList
+	if err := r.List(ctx, &entries,
+		client.InNamespace(dbBackup.Namespace),
+		client.MatchingLabels{backupNameLabel: dbBackup.Name},
+	); err != nil {
+		return err
+	}
+
+	found := false
+	for _, entry := range entries.Items {
+		if entry.Name == latest.Name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		entries.Items = append(entries.Items, *latest)
+	}
+
+	retention, expires := backupRetention(dbBackup)
+	dbBackup.Status.StorageUsageTrend = computeStorageUsageTrend(entries.Items, retention, expires, time.Now())
+	return nil
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// storageWaitContainer is the init container that blocks until storage is reachable
+const storageWaitContainer = "wait-for-storage"
+
+// Helper function to prepend an init container that waits for the backup
+// storage to become reachable. It runs the backup image with
+// WAIT_FOR_STORAGE_ONLY set, which probes bucket reachability or PVC
+// writability and exits. It must run after addStorageVolumes so it can
+// share the storage settings and mounts of the backup container.
+func addStorageWait(podSpec *corev1.PodSpec, dbBackup *dbbackupv1alpha1.DatabaseBackup) {
+	timeout := dbbackupv1alpha1.DefaultStorageWaitTimeout
+	if dbBackup.Spec.StorageWaitTimeout != nil {
+		timeout = dbBackup.Spec.StorageWaitTimeout.Duration
+	}
+
+	dest := dbBackup.Spec.StorageDestination
+	env := []corev1.EnvVar{
+		{
+			Name:  "STORAGE_TYPE",
+			Value: dest.Type,
+		},
+		{
+			Name:  "BUCKET",
+			Value: dest.Bucket,
+		},
+		{
+			Name:  "STORAGE_PATH",
+			Value: dest.Path,
+		},
+		{
+			Name:  "TIMEOUT_SECONDS",
+			Value: strconv.Itoa(int(timeout.Seconds())),
+		},
+		{
+			Name:  "WAIT_FOR_STORAGE_ONLY",
+			Value: "true",
+		},
+	}
+
+	// Share the storage connection settings and mounts of the backup container
+	backup := &podSpec.Containers[0]
+	for _, e := range backup.Env {
+		switch e.Name {
+		case "STORAGE_ENDPOINT", "S3_PATH_STYLE", "STORAGE_TOKEN_FILE", "STORAGE_CA_BUNDLE":
+			env = append(env, e)
+		}
+	}
+	var mounts []corev1.VolumeMount
+	for _, mount := range backup.VolumeMounts {
+		if isStorageVolume(mount.Name) {
+			mounts = append(mounts, mount)
+		}
+	}
+
+	podSpec.InitContainers = append([]corev1.Container{{
+		Name:            storageWaitContainer,
+		Image:           backup.Image,
+		SecurityContext: dbBackup.Spec.ContainerSecurityContext,
+		Env:             env,
+		VolumeMounts:    mounts,
+	}}, podSpec.InitContainers...)
+}
+
+// Helper function to check if a volume carries the storage destination or
+// its credentials, as added by addStorageVolumes
+func isStorageVolume(name string) bool {
+	return strings.HasPrefix(name, "backup-storage") || strings.HasPrefix(name, "storage-")
+}
+
+// Helper function to check if a failed backup Job failed in the given init
+// container (e.g. storage never became available), rather than in the
+// backup itself. Init containers that never ran because an earlier one
+// failed don't count
+func (r *DatabaseBackupReconciler) isInitContainerFailure(ctx context.Context, job *batchv1.Job, container string) (bool, error) {
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList,
+		client.InNamespace(job.Namespace),
+		client.MatchingLabels{"job-name": job.Name},
+	); err != nil {
+		return false, err
+	}
+
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != corev1.PodFailed {
+			continue
+		}
+		for _, status := range pod.Status.InitContainerStatuses {
+			if status.Name != container {
+				continue
+			}
+			if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func backupPod(name, jobName string, phase corev1.PodPhase, initStatuses ...corev1.ContainerStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{"job-name": jobName},
+		},
+		Status: corev1.PodStatus{
+			Phase:                 phase,
+			InitContainerStatuses: initStatuses,
+		},
+	}
+}
+
+func terminatedStatus(name string, exitCode int32) corev1.ContainerStatus {
+	return corev1.ContainerStatus{
+		Name: name,
+		State: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode},
+		},
+	}
+}
+
+func TestIsInitContainerFailure(t *testing.T) {
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "backup-1", Namespace: "default"}}
+
+	tests := []struct {
+		name string
+		pods []runtime.Object
+		want bool
+	}{
+		{
+			name: "no pods",
+			want: false,
+		},
+		{
+			name: "init container failed",
+			pods: []runtime.Object{
+				backupPod("backup-1-a", "backup-1", corev1.PodFailed, terminatedStatus(storageWaitContainer, 1)),
+			},
+			want: true,
+		},
+		{
+			name: "init container succeeded",
+			pods: []runtime.Object{
+				backupPod("backup-1-a", "backup-1", corev1.PodFailed, terminatedStatus(storageWaitContainer, 0)),
+			},
+			want: false,
+		},
+		{
+			name: "another init container failed",
+			pods: []runtime.Object{
+				backupPod("backup-1-a", "backup-1", corev1.PodFailed,
+					terminatedStatus(storageWaitContainer, 0), terminatedStatus(consistencyCheckContainer, 1)),
+			},
+			want: false,
+		},
+		{
+			name: "pod still running",
+			pods: []runtime.Object{
+				backupPod("backup-1-a", "backup-1", corev1.PodRunning, terminatedStatus(storageWaitContainer, 1)),
+			},
+			want: false,
+		},
+		{
+			name: "pod of another job",
+			pods: []runtime.Object{
+				backupPod("backup-2-a", "backup-2", corev1.PodFailed, terminatedStatus(storageWaitContainer, 1)),
+			},
+			want: false,
+		},
+		{
+			name: "failed on a retry",
+			pods: []runtime.Object{
+				backupPod("backup-1-a", "backup-1", corev1.PodFailed, terminatedStatus(storageWaitContainer, 0)),
+				backupPod("backup-1-b", "backup-1", corev1.PodFailed, terminatedStatus(storageWaitContainer, 2)),
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler(t, tt.pods...)
+			got, err := r.isInitContainerFailure(context.Background(), job, storageWaitContainer)
+			if err != nil {
+				t.Fatalf("isInitContainerFailure: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("isInitContainerFailure = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// groupRunLabel is set on consistency group target Jobs to the name of the
+// backup Job they were launched with
+const groupRunLabel = "db.example.io/group-run"
+
+// Helper function to get the DatabaseBackup a target is backed up as: the
+// DatabaseBackup with the target's database, writing under the target's name
+func targetBackup(dbBackup *dbbackupv1alpha1.DatabaseBackup, target *dbbackupv1alpha1.TargetSpec) *dbbackupv1alpha1.DatabaseBackup {
+	backup := dbBackup.DeepCopy()
+	backup.Spec.DatabaseType = target.DatabaseType
+	backup.Spec.DatabaseSelector = target.DatabaseSelector
+	backup.Spec.EnvFrom = target.EnvFrom
+	backup.Spec.DatabaseRef = nil
+	backup.Spec.Targets = nil
+	backup.Status.ResolvedDatabase = nil
+
+	backup.Spec.StorageDestination.Path = path.Join(backup.Spec.StorageDestination.Path, target.Name)
+	for i := range backup.Spec.StorageDestinations {
+		backup.Spec.StorageDestinations[i].Path = path.Join(backup.Spec.StorageDestinations[i].Path, target.Name)
+	}
+	return backup
+}
+
+// Helper function to launch the target Jobs of a consistency group together
+// with its backup Job, and start tracking them in status. Jobs a previous
+// attempt already created are kept
+func (r *DatabaseBackupReconciler) createTargetJobs(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup, primary *batchv1.Job, scheduledTime time.Time) error {
+	var statuses []dbbackupv1alpha1.TargetStatus
+	for i := range dbBackup.Spec.Targets {
+		target := &dbBackup.Spec.Targets[i]
+		targetCopy := targetBackup(r.withStorageDefaults(dbBackup), target)
+
+		job, err := buildBackupJob(targetCopy, scheduledTime)
+		if err != nil {
+			return fmt.Errorf("target %s: %w", target.Name, err)
+		}
+		job.Name = fmt.Sprintf("%s-%s", primary.Name, target.Name)
+		job.Labels[jobKindLabel] = "target"
+		job.Labels[groupRunLabel] = primary.Name
+		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env,
+			corev1.EnvVar{
+				Name:  "TARGET_NAME",
+				Value: target.Name,
+			},
+			corev1.EnvVar{
+				Name:  "GROUP_RUN",
+				Value: primary.Name,
+			},
+		)
+		r.addImagePullSecret(&job.Spec.Template.Spec, targetCopy)
+
+		// Encrypt with the key the backup Job was given
+		if keyID, ok := primary.Annotations[encryptionKeyIDAnnotation]; ok {
+			addEncryptionKey(&job.Spec.Template.Spec, targetCopy, keyID)
+			job.Annotations[encryptionKeyIDAnnotation] = keyID
+		}
+		addUploadPipeline(&job.Spec.Template.Spec, targetCopy)
+
+		if err := ctrl.SetControllerReference(dbBackup, job, r.Scheme); err != nil {
+			return err
+		}
+		if err := r.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("target %s: %w", target.Name, err)
+		}
+
+		statuses = append(statuses, dbbackupv1alpha1.TargetStatus{
+			Name:             target.Name,
+			Job:              job.Name,
+			LastBackupStatus: "Running",
+		})
+	}
+	dbBackup.Status.Targets = statuses
+	return nil
+}
+
+// Helper function to record the outcome of the running targets of a
+// consistency group. Returns whether any target is still running and the
+// names of the targets that failed
+func (r *DatabaseBackupReconciler) syncTargets(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (bool, []string, error) {
+	running := false
+	var failed []string
+	for i := range dbBackup.Status.Targets {
+		status := &dbBackup.Status.Targets[i]
+		if status.LastBackupStatus == "Running" {
+			var job batchv1.Job
+			err := r.Get(ctx, types.NamespacedName{Name: status.Job, Namespace: dbBackup.Namespace}, &job)
+			switch {
+			case errors.IsNotFound(err):
+				status.LastBackupStatus = "Failed"
+				status.Message = "Target job disappeared before finishing"
+			case err != nil:
+				return false, nil, err
+			case isJobSuccessful(&job):
+				status.LastBackupStatus = "Succeeded"
+			case isJobComplete(&job):
+				status.LastBackupStatus = "Failed"
+				status.Message = "Target job failed, check job logs for details"
+			default:
+				pullFailure, err := r.abortImagePull(ctx, dbBackup, &job)
+				if err != nil {
+					return false, nil, err
+				}
+				if pullFailure != "" {
+					status.LastBackupStatus = "Failed"
+					status.Message = pullFailure
+				} else {
+					running = true
+				}
+			}
+		}
+		if status.LastBackupStatus == "Failed" {
+			failed = append(failed, status.Name)
+		}
+	}
+	return running, failed, nil
+}
+
+// Helper function to delete the still running target Jobs of a cancelled run
+func (r *DatabaseBackupReconciler) cancelTargetJobs(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) error {
+	for i := range dbBackup.Status.Targets {
+		status := &dbBackup.Status.Targets[i]
+		if status.LastBackupStatus != "Running" {
+			continue
+		}
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: status.Job, Namespace: dbBackup.Namespace}}
+		if err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationForeground)); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		status.LastBackupStatus = "Cancelled"
+	}
+	return nil
+}
+
+// Helper function to describe the targets of a consistency group that failed
+func describeFailedTargets(failed []string) string {
+	return fmt.Sprintf("Consistency group targets failed: %s", strings.Join(failed, ", "))
+}
+
+// Helper function to check if the database and every consistency group
+// target with a database selector are ready to be backed up
+func (r *DatabaseBackupReconciler) isGroupReady(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) (bool, string, error) {
+	ready, reason, err := r.isDatabaseReady(ctx, dbBackup)
+	if err != nil || !ready {
+		return ready, reason, err
+	}
+	for i := range dbBackup.Spec.Targets {
+		target := &dbBackup.Spec.Targets[i]
+		if len(target.DatabaseSelector.MatchLabels) == 0 && len(target.DatabaseSelector.MatchExpressions) == 0 {
+			continue
+		}
+		ready, reason, err := r.isDatabaseReady(ctx, targetBackup(dbBackup, target))
+		if err != nil || !ready {
+			return ready, fmt.Sprintf("target %s: %s", target.Name, reason), err
+		}
+	}
+	return true, "", nil
+}
+
+// Helper function to validate the consistency group targets. Targets are
+// backed up as plain Jobs alongside the backup Job, so features tied to the
+// primary database or to a single Job aren't supported with them
+func validateTargets(spec *dbbackupv1alpha1.DatabaseBackupSpec) error {
+	switch {
+	case spec.Mode == "snapshot" || spec.Mode == "exec":
+		return fmt.Errorf("targets are not supported in %s mode", spec.Mode)
+	case spec.BackupType == "incremental":
+		return fmt.Errorf("targets are not supported with incremental backups")
+	case spec.ReuploadFailedDestinations != nil:
+		return fmt.Errorf("targets and reuploadFailedDestinations are mutually exclusive")
+	}
+
+	seen := map[string]bool{}
+	for _, target := range spec.Targets {
+		if seen[target.Name] {
+			return fmt.Errorf("duplicate target name %q", target.Name)
+		}
+		seen[target.Name] = true
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// databaseTLSDir is where the database client certificate secret is mounted
+const databaseTLSDir = "/db-tls"
+
+// storageCADir is where the storage CA bundle is mounted
+const storageCADir = "/storage-ca"
+
+// databaseTLSKeys are the keys the database TLS secret must hold
+var databaseTLSKeys = []string{"ca.crt", corev1.TLSCertKey, corev1.TLSPrivateKeyKey}
+
+// Helper function to check that the database TLS secret holds the CA,
+// client certificate and key
+func (r *DatabaseBackupReconciler) validateTLSSecret(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) error {
+	secretName := dbBackup.Spec.TLSConfig.SecretName
+	var secret corev1.Secret
+	if err := r.apiReader().Get(ctx, types.NamespacedName{Name: secretName, Namespace: dbBackup.Namespace}, &secret); err != nil {
+		return fmt.Errorf("failed to get TLS secret %s: %w", secretName, err)
+	}
+	for _, key := range databaseTLSKeys {
+		if len(secret.Data[key]) == 0 {
+			return fmt.Errorf("TLS secret %s is missing key %s", secretName, key)
+		}
+	}
+	return nil
+}
+
+// Helper function to check that the storage CA bundle secret holds a CA
+func (r *DatabaseBackupReconciler) validateStorageCABundle(ctx context.Context, dbBackup *dbbackupv1alpha1.DatabaseBackup) error {
+	secretName := dbBackup.Spec.StorageCABundleSecret
+	var secret corev1.Secret
+	if err := r.apiReader().Get(ctx, types.NamespacedName{Name: secretName, Namespace: dbBackup.Namespace}, &secret); err != nil {
+		return fmt.Errorf("failed to get storage CA bundle secret %s: %w", secretName, err)
+	}
+	if len(secret.Data["ca.crt"]) == 0 {
+		return fmt.Errorf("storage CA bundle secret %s is missing key ca.crt", secretName)
+	}
+	return nil
+}
+
+// Helper function to mount the storage CA bundle into a pod that reads or
+// writes backups and point the image at it
+func addStorageCABundle(podSpec *corev1.PodSpec, secretName string) {
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "storage-ca",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+				Items:      []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+			},
+		},
+	})
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      "storage-ca",
+		MountPath: storageCADir,
+		ReadOnly:  true,
+	})
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  "STORAGE_CA_BUNDLE",
+		Value: path.Join(storageCADir, "ca.crt"),
+	})
+}
+
+// Helper function to mount the database client certificate into a backup
+// pod and point the image at it
+func addDatabaseTLS(podSpec *corev1.PodSpec, tls *dbbackupv1alpha1.DatabaseTLSSpec) {
+	sslMode := tls.SSLMode
+	if sslMode == "" {
+		sslMode = dbbackupv1alpha1.DefaultSSLMode
+	}
+
+	// Database clients refuse private keys readable by others
+	mode := int32(0440)
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "db-tls",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  tls.SecretName,
+				DefaultMode: &mode,
+			},
+		},
+	})
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      "db-tls",
+		MountPath: databaseTLSDir,
+		ReadOnly:  true,
+	})
+	container.Env = append(container.Env,
+		corev1.EnvVar{
+			Name:  "DB_SSL_MODE",
+			Value: sslMode,
+		},
+		corev1.EnvVar{
+			Name:  "DB_SSL_ROOT_CERT",
+			Value: path.Join(databaseTLSDir, "ca.crt"),
+		},
+		corev1.EnvVar{
+			Name:  "DB_SSL_CERT",
+			Value: path.Join(databaseTLSDir, corev1.TLSCertKey),
+		},
+		corev1.EnvVar{
+			Name:  "DB_SSL_KEY",
+			Value: path.Join(databaseTLSDir, corev1.TLSPrivateKeyKey),
+		},
+	)
+}
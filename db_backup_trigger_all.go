@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// adminShutdownTimeout bounds how long in-flight admin requests may take once
+// the manager stops
+const adminShutdownTimeout = 5 * time.Second
+
+// triggerAllResult is the response of POST /backups/trigger-all. Requested
+// backups wait for a slot under MaxConcurrentBackups like any other backup
+type triggerAllResult struct {
+	Requested int `json:"requested"`
+}
+
+// AdminServer returns a manager Runnable serving the admin endpoints on addr:
+//
+//	POST /backups/trigger-all[?selector=<label selector>]
+//
+// sets the backup-now annotation on every matching DatabaseBackup. The
+// backups then go through the concurrent backup limit as usual, so triggering
+// everything doesn't start more Jobs than a schedule would.
+//
+// Requests need a bearer token of a user allowed to patch DatabaseBackups in
+// every namespace, checked with a TokenReview and a SubjectAccessReview. The
+// SubjectAccessReview has no Namespace, so it is checked cluster-wide and a
+// user who may only patch DatabaseBackups in some namespaces is refused.
+func (r *DatabaseBackupReconciler) AdminServer(addr string) manager.Runnable {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/backups/trigger-all", r.serveTriggerAll)
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	return manager.RunnableFunc(func(ctx context.Context) error {
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), adminShutdownTimeout)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+		log.FromContext(ctx).Info("Serving admin endpoints", "addr", addr)
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
+}
+
+func (r *DatabaseBackupReconciler) serveTriggerAll(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !r.authorizeAdmin(w, req) {
+		return
+	}
+	selector, err := labels.Parse(req.URL.Query().Get("selector"))
+	if err != nil {
+		http.Error(w, "invalid selector: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := r.triggerAll(req.Context(), selector)
+	if err != nil {
+		log.FromContext(req.Context()).Error(err, "Failed to trigger all backups")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+//+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// Helper function to check that an admin request carries the bearer token of
+// a user allowed to patch DatabaseBackups cluster-wide, which is what
+// trigger-all does on their behalf. Writes the error response and returns
+// false otherwise
+func (r *DatabaseBackupReconciler) authorizeAdmin(w http.ResponseWriter, req *http.Request) bool {
+	ctx := req.Context()
+	header := req.Header.Get("Authorization")
+	token := strings.TrimPrefix(header, "Bearer ")
+	if token == "" || token == header {
+		http.Error(w, "missing bearer token", http.StatusUnauthorized)
+		return false
+	}
+
+	review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	if err := r.Create(ctx, review); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to review admin request token")
+		http.Error(w, "failed to authenticate request", http.StatusInternalServerError)
+		return false
+	}
+	if !review.Status.Authenticated {
+		http.Error(w, "invalid bearer token", http.StatusUnauthorized)
+		return false
+	}
+
+	user := review.Status.User
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	access := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:     "patch",
+				Group:    "db.example.io",
+				Resource: "databasebackups",
+			},
+		},
+	}
+	if err := r.Create(ctx, access); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to authorize admin request")
+		http.Error(w, "failed to authorize request", http.StatusInternalServerError)
+		return false
+	}
+	if !access.Status.Allowed {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// Helper function to request a backup of every DatabaseBackup in this
+// controller's shard matching selector
+func (r *DatabaseBackupReconciler) triggerAll(ctx context.Context, selector labels.Selector) (*triggerAllResult, error) {
+	var backups dbbackupv1alpha1.DatabaseBackupList
+	if err := r.List(ctx, &backups, r.shardSelector()); err != nil {
+		return nil, err
+	}
+
+	trigger := "trigger-all-" + time.Now().UTC().Format(time.RFC3339)
+	result := &triggerAllResult{}
+	for i := range backups.Items {
+		dbBackup := &backups.Items[i]
+		if !dbBackup.DeletionTimestamp.IsZero() || !selector.Matches(labels.Set(dbBackup.Labels)) {
+			continue
+		}
+
+		patch := client.MergeFrom(dbBackup.DeepCopy())
+		if dbBackup.Annotations == nil {
+			dbBackup.Annotations = map[string]string{}
+		}
+		dbBackup.Annotations[backupNowAnnotation] = trigger
+		if err := r.Patch(ctx, dbBackup, patch); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		result.Requested++
+	}
+	return result, nil
+}
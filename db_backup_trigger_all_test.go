@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// reviewClient answers TokenReviews and SubjectAccessReviews the way the API
+// server would for a single known token
+type reviewClient struct {
+	client.Client
+	token   string
+	allowed bool
+	access  *authorizationv1.SubjectAccessReviewSpec
+}
+
+func (c *reviewClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	switch review := obj.(type) {
+	case *authenticationv1.TokenReview:
+		if review.Spec.Token == c.token {
+			review.Status.Authenticated = true
+			review.Status.User = authenticationv1.UserInfo{Username: "admin", Groups: []string{"system:authenticated"}}
+		}
+		return nil
+	case *authorizationv1.SubjectAccessReview:
+		c.access = &review.Spec
+		review.Status.Allowed = c.allowed && review.Spec.User == "admin"
+		return nil
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func TestServeTriggerAll(t *testing.T) {
+	labeled := func(name, team string) *dbbackupv1alpha1.DatabaseBackup {
+		return &dbbackupv1alpha1.DatabaseBackup{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{"team": team},
+		}}
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		token         string
+		allowed       bool
+		query         string
+		wantCode      int
+		wantRequested int
+		wantTriggered []string
+	}{
+		{name: "wrong method", method: http.MethodGet, token: "good", allowed: true, wantCode: http.StatusMethodNotAllowed},
+		{name: "no token", method: http.MethodPost, allowed: true, wantCode: http.StatusUnauthorized},
+		{name: "token rejected by TokenReview", method: http.MethodPost, token: "bad", allowed: true, wantCode: http.StatusUnauthorized},
+		{name: "denied by SubjectAccessReview", method: http.MethodPost, token: "good", wantCode: http.StatusForbidden},
+		{name: "invalid selector", method: http.MethodPost, token: "good", allowed: true, query: "?selector=team%20in", wantCode: http.StatusBadRequest},
+		{
+			name: "all backups", method: http.MethodPost, token: "good", allowed: true,
+			wantCode: http.StatusOK, wantRequested: 3, wantTriggered: []string{"a", "b", "c"},
+		},
+		{
+			name: "selected backups", method: http.MethodPost, token: "good", allowed: true, query: "?selector=team%3Dpayments",
+			wantCode: http.StatusOK, wantRequested: 2, wantTriggered: []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestReconciler(t, labeled("a", "payments"), labeled("b", "payments"), labeled("c", "search"))
+			reviews := &reviewClient{Client: r.Client, token: "good", allowed: tt.allowed}
+			r.Client = reviews
+
+			req := httptest.NewRequest(tt.method, "/backups/trigger-all"+tt.query, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			r.serveTriggerAll(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+			if reviews.access != nil {
+				// Checked cluster-wide since trigger-all patches every namespace
+				attrs := reviews.access.ResourceAttributes
+				if attrs.Namespace != "" || attrs.Verb != "patch" || attrs.Resource != "databasebackups" {
+					t.Errorf("SubjectAccessReview for %+v, want a cluster-wide patch of databasebackups", attrs)
+				}
+			}
+
+			triggered := map[string]bool{}
+			for _, name := range []string{"a", "b", "c"} {
+				var dbBackup dbbackupv1alpha1.DatabaseBackup
+				if err := r.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, &dbBackup); err != nil {
+					t.Fatalf("getting %s: %v", name, err)
+				}
+				triggered[name] = dbBackup.Annotations[backupNowAnnotation] != ""
+			}
+			for _, name := range tt.wantTriggered {
+				if !triggered[name] {
+					t.Errorf("%s wasn't triggered", name)
+				}
+				delete(triggered, name)
+			}
+			for name, ok := range triggered {
+				if ok {
+					t.Errorf("%s was triggered", name)
+				}
+			}
+
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var result triggerAllResult
+			if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if result.Requested != tt.wantRequested {
+				t.Errorf("requested = %d, want %d", result.Requested, tt.wantRequested)
+			}
+		})
+	}
+}
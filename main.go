@@ -1,17 +1,31 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
@@ -27,6 +41,7 @@ var (
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(snapshotv1.AddToScheme(scheme))
 	utilruntime.Must(dbbackupv1alpha1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
@@ -35,39 +50,191 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var maxConcurrentBackups int
+	var runningJobPollInterval time.Duration
+	var imagePullSecret string
+	var failureLogLines int64
+	var otlpEndpoint string
+	var configMap string
+	var defaultStorageSecret string
+	var incidentEndpoints string
+	var maxConcurrentReconciles int
+	var watchSelector string
+	var leaderElectionID string
+	var adminAddr string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.IntVar(&maxConcurrentBackups, "max-concurrent-backups", 0,
+		"Maximum number of backup jobs running at once across all DatabaseBackups. 0 means no limit.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", controllers.DefaultMaxConcurrentReconciles,
+		"Number of DatabaseBackups reconciled in parallel.")
+	flag.DurationVar(&runningJobPollInterval, "running-job-poll-interval", controllers.DefaultRunningJobPollInterval,
+		"How often a running backup job is re-checked in addition to Job watch events.")
+	flag.StringVar(&imagePullSecret, "image-pull-secret", "",
+		"Image pull secret, as namespace/name, copied into each DatabaseBackup's namespace for its backup pods.")
+	flag.Int64Var(&failureLogLines, "failure-log-lines", controllers.DefaultFailureLogLines,
+		"Number of log lines of a failed backup pod kept in status. Negative disables log capture.")
+	flag.StringVar(&otlpEndpoint, "otlp-endpoint", "",
+		"OTLP gRPC endpoint (host:port) to export traces to. Tracing is disabled when empty.")
+	flag.StringVar(&defaultStorageSecret, "default-storage-secret", "",
+		"Storage secret, as name or namespace/name, used by DatabaseBackups that name no storage secret. A bare name is looked up in each DatabaseBackup's namespace.")
+	flag.StringVar(&incidentEndpoints, "incident-endpoints", "",
+		"Comma-separated incident provider endpoints DatabaseBackups may send incidents to besides the PagerDuty and Opsgenie public endpoints, e.g. a proxy.")
+	flag.StringVar(&configMap, "config-map", "",
+		"Operator ConfigMap, as namespace/name (e.g. db-operator-system/db-operator-config). Setting globalPause: \"true\" in it pauses all backups.")
+	flag.StringVar(&watchSelector, "watch-selector", "",
+		"Label selector (e.g. db-operator-shard=a) limiting which DatabaseBackups and DatabaseBackupPolicies this instance reconciles. Empty means all.")
+	flag.StringVar(&adminAddr, "admin-bind-address", "",
+		"The address the admin endpoints (POST /backups/trigger-all) bind to, e.g. 127.0.0.1:8082. Disabled when empty. "+
+			"Requests need a bearer token of a user allowed to patch databasebackups in all namespaces.")
+	flag.StringVar(&leaderElectionID, "leader-election-id", "db-backup-operator-leader-election",
+		"Leader election lease name. Instances owning different --watch-selector shards need different ids.")
+	// Info by default; --zap-log-level=debug (or a number for higher V
+	// levels) brings back the per-requeue detail
 	opts := zap.Options{
 		Development: true,
+		Level:       zapcore.InfoLevel,
 	}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if otlpEndpoint != "" {
+		shutdown, err := setupTracing(otlpEndpoint)
+		if err != nil {
+			setupLog.Error(err, "unable to set up tracing")
+			os.Exit(1)
+		}
+		defer shutdown()
+	}
+
+	var pullSecret types.NamespacedName
+	if imagePullSecret != "" {
+		namespace, name, ok := strings.Cut(imagePullSecret, "/")
+		if !ok || namespace == "" || name == "" {
+			setupLog.Error(nil, "invalid --image-pull-secret, expected namespace/name", "value", imagePullSecret)
+			os.Exit(1)
+		}
+		pullSecret = types.NamespacedName{Namespace: namespace, Name: name}
+	}
+
+	var storageSecret types.NamespacedName
+	if defaultStorageSecret != "" {
+		namespace, name, ok := strings.Cut(defaultStorageSecret, "/")
+		if !ok {
+			namespace, name = "", defaultStorageSecret
+		}
+		if name == "" || (ok && namespace == "") {
+			setupLog.Error(nil, "invalid --default-storage-secret, expected name or namespace/name", "value", defaultStorageSecret)
+			os.Exit(1)
+		}
+		storageSecret = types.NamespacedName{Namespace: namespace, Name: name}
+	}
+
+	var operatorConfig types.NamespacedName
+	if configMap != "" {
+		namespace, name, ok := strings.Cut(configMap, "/")
+		if !ok || namespace == "" || name == "" {
+			setupLog.Error(nil, "invalid --config-map, expected namespace/name", "value", configMap)
+			os.Exit(1)
+		}
+		operatorConfig = types.NamespacedName{Namespace: namespace, Name: name}
+	}
+
+	var allowedIncidentEndpoints []string
+	for _, endpoint := range strings.Split(incidentEndpoints, ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			allowedIncidentEndpoints = append(allowedIncidentEndpoints, endpoint)
+		}
+	}
+
+	var selector labels.Selector
+	if watchSelector != "" {
+		var err error
+		if selector, err = labels.Parse(watchSelector); err != nil {
+			setupLog.Error(err, "invalid --watch-selector", "value", watchSelector)
+			os.Exit(1)
+		}
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
 		Port:                   9443,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "db-backup-operator-leader-election",
+		LeaderElectionID:       leaderElectionID,
+		// Only the Secrets the operator writes are cached, the rest are
+		// read from the API server when needed
+		NewCache: cache.BuilderWithOptions(cache.Options{
+			SelectorsByObject: cache.SelectorsByObject{
+				&corev1.Secret{}: {Label: controllers.SecretCacheSelector()},
+			},
+		}),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
-	if err = (&controllers.DatabaseBackupReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	executor, err := controllers.NewPodExecutor(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create pod executor")
+		os.Exit(1)
+	}
+
+	logReader, err := controllers.NewPodLogReader(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create pod log reader")
+		os.Exit(1)
+	}
+
+	backupReconciler := &controllers.DatabaseBackupReconciler{
+		Client:                  mgr.GetClient(),
+		APIReader:               mgr.GetAPIReader(),
+		Scheme:                  mgr.GetScheme(),
+		Recorder:                mgr.GetEventRecorderFor("databasebackup-controller"),
+		MaxConcurrentBackups:    maxConcurrentBackups,
+		RunningJobPollInterval:  runningJobPollInterval,
+		ImagePullSecret:         pullSecret,
+		LogReader:               logReader,
+		FailureLogLines:         failureLogLines,
+		Executor:                executor,
+		Incidents:               controllers.NewIncidentNotifier(allowedIncidentEndpoints),
+		DefaultStorageSecret:    storageSecret,
+		ConfigMap:               operatorConfig,
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		Selector:                selector,
+	}
+	if err = backupReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DatabaseBackup")
 		os.Exit(1)
 	}
+	if adminAddr != "" {
+		if err := mgr.Add(backupReconciler.AdminServer(adminAddr)); err != nil {
+			setupLog.Error(err, "unable to set up admin server")
+			os.Exit(1)
+		}
+	}
+	if err = (&controllers.DatabaseBackupPolicyReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Selector: selector,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DatabaseBackupPolicy")
+		os.Exit(1)
+	}
+	// Webhooks need serving certificates, so they are opt-in
+	if os.Getenv("ENABLE_WEBHOOKS") == "true" {
+		if err = (&dbbackupv1alpha1.DatabaseBackup{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "DatabaseBackup")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -85,3 +252,29 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// setupTracing installs a global tracer provider exporting to an OTLP
+// collector. The returned function flushes pending spans.
+func setupTracing(endpoint string) (func(), error) {
+	exporter, err := otlptracegrpc.New(context.Background(),
+		otlptracegrpc.WithEndpoint(endpoint),
+		otlptracegrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName("db-backup-operator"),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func() {
+		if err := provider.Shutdown(context.Background()); err != nil {
+			setupLog.Error(err, "failed to flush traces")
+		}
+	}, nil
+}
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// sloBreachesTotal counts successful backups that exceeded their SLO duration
+	sloBreachesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "db_backup_slo_breaches_total",
+			Help: "Number of backups that completed but exceeded their maximum duration SLO",
+		},
+		[]string{"namespace", "name"},
+	)
+
+	// statusUpdatesSkippedTotal counts DatabaseBackup reconciles that left the
+	// status as it was, so no status update was sent
+	statusUpdatesSkippedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "db_backup_status_updates_skipped_total",
+			Help: "Number of DatabaseBackup reconciles that sent no status update because the status was unchanged",
+		},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(sloBreachesTotal, statusUpdatesSkippedTotal)
+}
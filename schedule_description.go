@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var monthNames = map[string]string{
+	"1": "January", "2": "February", "3": "March", "4": "April",
+	"5": "May", "6": "June", "7": "July", "8": "August",
+	"9": "September", "10": "October", "11": "November", "12": "December",
+	"JAN": "January", "FEB": "February", "MAR": "March", "APR": "April",
+	"MAY": "May", "JUN": "June", "JUL": "July", "AUG": "August",
+	"SEP": "September", "OCT": "October", "NOV": "November", "DEC": "December",
+}
+
+var weekdayNames = map[string]string{
+	"0": "Sunday", "1": "Monday", "2": "Tuesday", "3": "Wednesday",
+	"4": "Thursday", "5": "Friday", "6": "Saturday", "7": "Sunday",
+	"SUN": "Sunday", "MON": "Monday", "TUE": "Tuesday", "WED": "Wednesday",
+	"THU": "Thursday", "FRI": "Friday", "SAT": "Saturday",
+}
+
+// describeSchedule returns a human-readable description of a standard cron
+// expression, e.g. "At 02:00, Monday through Friday". The expression is
+// expected to have parsed already; anything it can't describe is returned as-is.
+func describeSchedule(expr string) string {
+	expr = strings.TrimSpace(expr)
+
+	switch expr {
+	case "@yearly", "@annually":
+		return "At 00:00, on day 1 of the month, in January"
+	case "@monthly":
+		return "At 00:00, on day 1 of the month"
+	case "@weekly":
+		return "At 00:00, only on Sunday"
+	case "@daily", "@midnight":
+		return "At 00:00"
+	case "@hourly":
+		return "At minute 0 past every hour"
+	}
+	if strings.HasPrefix(expr, "@every ") {
+		return "Every " + strings.TrimPrefix(expr, "@every ")
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return expr
+	}
+	minute, hour, dom, month, dow := fields[0], fields[1], fields[2], fields[3], fields[4]
+
+	parts := []string{describeTimeOfDay(minute, hour)}
+	if !isWildcard(dom) {
+		parts = append(parts, "on day "+describeCronField(dom, nil)+" of the month")
+	}
+	if !isWildcard(month) {
+		parts = append(parts, "in "+describeCronField(month, monthNames))
+	}
+	if !isWildcard(dow) {
+		desc := describeCronField(dow, weekdayNames)
+		if strings.Contains(dow, ",") || !strings.Contains(dow, "-") {
+			desc = "only on " + desc
+		}
+		parts = append(parts, desc)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeTimeOfDay describes the minute and hour fields of a cron expression
+func describeTimeOfDay(minute, hour string) string {
+	m, minuteErr := strconv.Atoi(minute)
+	h, hourErr := strconv.Atoi(hour)
+
+	switch {
+	case minuteErr == nil && hourErr == nil:
+		return fmt.Sprintf("At %02d:%02d", h, m)
+	case isWildcard(minute) && isWildcard(hour):
+		return "Every minute"
+	case strings.HasPrefix(minute, "*/") && isWildcard(hour):
+		return fmt.Sprintf("Every %s minutes", strings.TrimPrefix(minute, "*/"))
+	case minuteErr == nil && isWildcard(hour):
+		return fmt.Sprintf("At minute %d past every hour", m)
+	case minuteErr == nil && strings.HasPrefix(hour, "*/"):
+		return fmt.Sprintf("At minute %d past every %s hours", m, strings.TrimPrefix(hour, "*/"))
+	case isWildcard(minute):
+		return "Every minute during hour " + describeCronField(hour, nil)
+	default:
+		return fmt.Sprintf("At minute %s past hour %s", describeCronField(minute, nil), describeCronField(hour, nil))
+	}
+}
+
+// describeCronField describes a single cron field made of comma-separated
+// values, ranges and steps, translating values through names when given
+func describeCronField(field string, names map[string]string) string {
+	name := func(value string) string {
+		if n, ok := names[strings.ToUpper(value)]; ok {
+			return n
+		}
+		return value
+	}
+
+	var items []string
+	for _, item := range strings.Split(field, ",") {
+		rangePart, step, hasStep := strings.Cut(item, "/")
+		var desc string
+		if low, high, ok := strings.Cut(rangePart, "-"); ok {
+			desc = name(low) + " through " + name(high)
+		} else if isWildcard(rangePart) {
+			desc = ""
+		} else {
+			desc = name(rangePart)
+		}
+
+		if hasStep {
+			if desc == "" {
+				desc = "every " + step
+			} else {
+				desc = "every " + step + " from " + desc
+			}
+		}
+		items = append(items, desc)
+	}
+
+	if len(items) == 1 {
+		return items[0]
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+func isWildcard(field string) bool {
+	return field == "*" || field == "?"
+}
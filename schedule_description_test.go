@@ -0,0 +1,34 @@
+package controllers
+
+import "testing"
+
+func TestDescribeSchedule(t *testing.T) {
+	tests := []struct {
+		schedule string
+		want     string
+	}{
+		{schedule: "0 2 * * *", want: "At 02:00"},
+		{schedule: "0 2 * * 1-5", want: "At 02:00, Monday through Friday"},
+		{schedule: "30 3 * * 0,6", want: "At 03:30, only on Sunday and Saturday"},
+		{schedule: "0 4 1 * *", want: "At 04:00, on day 1 of the month"},
+		{schedule: "0 0 1 JAN,JUL *", want: "At 00:00, on day 1 of the month, in January and July"},
+		{schedule: "*/15 * * * *", want: "Every 15 minutes"},
+		{schedule: "5 * * * *", want: "At minute 5 past every hour"},
+		{schedule: "0 */6 * * *", want: "At minute 0 past every 6 hours"},
+		{schedule: "* * * * *", want: "Every minute"},
+		{schedule: "* 3 * * *", want: "Every minute during hour 3"},
+		{schedule: "0,30 9-17 * * MON-FRI", want: "At minute 0 and 30 past hour 9 through 17, Monday through Friday"},
+		{schedule: "@daily", want: "At 00:00"},
+		{schedule: "@weekly", want: "At 00:00, only on Sunday"},
+		{schedule: "@every 6h", want: "Every 6h"},
+		// Not describable, so left as written
+		{schedule: "0 2 * *", want: "0 2 * *"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.schedule, func(t *testing.T) {
+			if got := describeSchedule(tt.schedule); got != tt.want {
+				t.Errorf("describeSchedule(%q) = %q, want %q", tt.schedule, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// cronFields are the fields of a standard cron expression with their bounds
+var cronFields = []struct {
+	name     string
+	min, max int
+	names    map[string]string
+}{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day-of-month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: monthNames},
+	{name: "day-of-week", min: 0, max: 6, names: weekdayNames},
+}
+
+// scheduleFormat is appended to hints about the overall shape of a schedule
+const scheduleFormat = `use five fields: minute hour day-of-month month day-of-week, e.g. "0 2 * * *"`
+
+// scheduleHint inspects a schedule that failed to parse and returns what is
+// likely wrong with it, suggesting the nearest valid schedule where there is
+// one. Returns an empty string if it can't tell.
+func scheduleHint(expr string) string {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return "schedule is empty, " + scheduleFormat
+	}
+
+	if strings.HasPrefix(expr, "@") {
+		if strings.HasPrefix(expr, "@every ") {
+			if _, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, "@every "))); err != nil {
+				return `@every takes a duration such as "30m" or "6h"`
+			}
+			return ""
+		}
+		return "unknown descriptor, use @yearly, @monthly, @weekly, @daily, @hourly or @every <duration>"
+	}
+
+	fields := strings.Fields(expr)
+	switch {
+	case len(fields) == 6:
+		return fmt.Sprintf("6 fields given, but schedules have no seconds field; did you mean %q?", strings.Join(fields[1:], " "))
+	case len(fields) == 7:
+		return fmt.Sprintf("7 fields given, but schedules have no seconds or year field; did you mean %q?", strings.Join(fields[1:6], " "))
+	case len(fields) != 5:
+		return fmt.Sprintf("%d fields given, %s", len(fields), scheduleFormat)
+	}
+
+	for i, field := range fields {
+		bounds := cronFields[i]
+		if strings.Contains(field, "?") && field != "?" {
+			return fmt.Sprintf("%s field %q mixes ? with other values, use * alone", bounds.name, field)
+		}
+
+		fixed, hint := fixCronField(field, bounds.name, bounds.min, bounds.max, bounds.names)
+		if hint == "" {
+			continue
+		}
+		if fixed == "" {
+			return hint
+		}
+		suggestion := append([]string{}, fields...)
+		suggestion[i] = fixed
+		return fmt.Sprintf("%s; did you mean %q?", hint, strings.Join(suggestion, " "))
+	}
+	return ""
+}
+
+// fixCronField checks the values of a cron field against its bounds,
+// returning a hint for the first problem found and the field with
+// out-of-range values clamped to the bounds. The fixed field is empty when
+// the problem can't be fixed by clamping.
+func fixCronField(field, name string, min, max int, names map[string]string) (string, string) {
+	var hint string
+	items := strings.Split(field, ",")
+	for i, item := range items {
+		rangePart, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			if n, err := strconv.Atoi(step); err != nil || n <= 0 {
+				return "", fmt.Sprintf("%s field %q has step %q, which must be a positive number", name, field, step)
+			}
+		}
+		if isWildcard(rangePart) {
+			continue
+		}
+
+		values := strings.Split(rangePart, "-")
+		wrapSunday := false
+		if len(values) > 2 {
+			return "", fmt.Sprintf("%s field %q has a malformed range %q", name, field, rangePart)
+		}
+		for j, value := range values {
+			n, err := strconv.Atoi(value)
+			if _, ok := names[strings.ToUpper(value)]; err != nil && ok {
+				continue
+			}
+			if err != nil {
+				return "", fmt.Sprintf("%s field %q has %q, which is not a number", name, field, value)
+			}
+			if n >= min && n <= max {
+				continue
+			}
+			if hint == "" {
+				hint = fmt.Sprintf("%s %d is out of range %d-%d", name, n, min, max)
+			}
+			if name == "day-of-week" && n == 7 && j == 1 && !hasStep {
+				// A range up to 7 runs through Saturday and on to Sunday
+				n = 6
+				wrapSunday = true
+			} else if name == "day-of-week" && n == 7 {
+				// Sunday is 0, not 7
+				n = 0
+			} else if n < min {
+				n = min
+			} else {
+				n = max
+			}
+			values[j] = strconv.Itoa(n)
+		}
+		if len(values) == 2 {
+			low, lowErr := strconv.Atoi(values[0])
+			high, highErr := strconv.Atoi(values[1])
+			if lowErr == nil && highErr == nil && low > high {
+				return "", fmt.Sprintf("%s range %q starts after it ends", name, rangePart)
+			}
+		}
+
+		items[i] = strings.Join(values, "-")
+		if hasStep {
+			items[i] += "/" + step
+		}
+		if wrapSunday {
+			items[i] += ",0"
+		}
+	}
+	if hint == "" {
+		return "", ""
+	}
+	return strings.Join(items, ","), hint
+}
+
+// Helper function to describe a schedule parse error, with a hint about the
+// likely mistake when one can be found
+func describeScheduleError(expr string, err error) string {
+	reason := fmt.Sprintf("Invalid schedule: %v", err)
+	if hint := scheduleHint(expr); hint != "" {
+		reason += ": " + hint
+	}
+	return reason
+}
+
+// Helper function to set the ScheduleValid condition. A nil err marks the
+// schedule valid. Returns true if the condition changed
+func setScheduleValid(dbBackup *dbbackupv1alpha1.DatabaseBackup, err error) bool {
+	condition := metav1.Condition{
+		Type:               dbbackupv1alpha1.ConditionScheduleValid,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: dbBackup.Generation,
+		Reason:             "ScheduleParsed",
+		Message:            "Schedule is valid",
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidSchedule"
+		condition.Message = describeScheduleError(dbBackup.Spec.Schedule, err)
+	}
+
+	existing := meta.FindStatusCondition(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionScheduleValid)
+	if existing != nil && existing.Status == condition.Status && existing.Message == condition.Message &&
+		existing.ObservedGeneration == condition.ObservedGeneration {
+		return false
+	}
+	meta.SetStatusCondition(&dbBackup.Status.Conditions, condition)
+	return true
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/robfig/cron"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+func TestScheduleHint(t *testing.T) {
+	tests := []struct {
+		schedule string
+		want     string
+	}{
+		{schedule: "", want: "schedule is empty, " + scheduleFormat},
+		{schedule: "0 2 * *", want: "4 fields given, " + scheduleFormat},
+		{schedule: "0 0 2 * * *", want: `6 fields given, but schedules have no seconds field; did you mean "0 2 * * *"?`},
+		{schedule: "0 0 2 * * * 2026", want: `7 fields given, but schedules have no seconds or year field; did you mean "0 2 * * *"?`},
+		{schedule: "60 2 * * *", want: `minute 60 is out of range 0-59; did you mean "59 2 * * *"?`},
+		{schedule: "0 24 * * *", want: `hour 24 is out of range 0-23; did you mean "0 23 * * *"?`},
+		{schedule: "0 2 0 * *", want: `day-of-month 0 is out of range 1-31; did you mean "0 2 1 * *"?`},
+		{schedule: "0 2 * 13 *", want: `month 13 is out of range 1-12; did you mean "0 2 * 12 *"?`},
+		{schedule: "0 2 * * 7", want: `day-of-week 7 is out of range 0-6; did you mean "0 2 * * 0"?`},
+		{schedule: "0 2 * * 1-7", want: `day-of-week 7 is out of range 0-6; did you mean "0 2 * * 1-6,0"?`},
+		{schedule: "*/0 * * * *", want: `minute field "*/0" has step "0", which must be a positive number`},
+		{schedule: "0 5-2 * * *", want: `hour range "5-2" starts after it ends`},
+		{schedule: "0 2 * * MON-FOO", want: `day-of-week field "MON-FOO" has "FOO", which is not a number`},
+		{schedule: "0 2 ?5 * *", want: `day-of-month field "?5" mixes ? with other values, use * alone`},
+		{schedule: "@often", want: "unknown descriptor, use @yearly, @monthly, @weekly, @daily, @hourly or @every <duration>"},
+		{schedule: "@every soon", want: `@every takes a duration such as "30m" or "6h"`},
+		{schedule: "0 2 * * *", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.schedule, func(t *testing.T) {
+			if got := scheduleHint(tt.schedule); got != tt.want {
+				t.Errorf("scheduleHint(%q) = %q, want %q", tt.schedule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetScheduleValid(t *testing.T) {
+	dbBackup := &dbbackupv1alpha1.DatabaseBackup{Spec: dbbackupv1alpha1.DatabaseBackupSpec{Schedule: "0 0 2 * * *"}}
+	_, err := cron.ParseStandard(dbBackup.Spec.Schedule)
+	if err == nil {
+		t.Fatal("schedule with a seconds field parsed")
+	}
+
+	if !setScheduleValid(dbBackup, err) {
+		t.Error("invalid schedule didn't change the condition")
+	}
+	condition := meta.FindStatusCondition(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionScheduleValid)
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "InvalidSchedule" {
+		t.Fatalf("condition = %+v, want False/InvalidSchedule", condition)
+	}
+	if !strings.Contains(condition.Message, `did you mean "0 2 * * *"?`) {
+		t.Errorf("message %q has no hint", condition.Message)
+	}
+	if setScheduleValid(dbBackup, err) {
+		t.Error("same error changed the condition again")
+	}
+
+	dbBackup.Spec.Schedule = "0 2 * * *"
+	if !setScheduleValid(dbBackup, nil) {
+		t.Error("fixed schedule didn't change the condition")
+	}
+	if !meta.IsStatusConditionTrue(dbBackup.Status.Conditions, dbbackupv1alpha1.ConditionScheduleValid) {
+		t.Error("fixed schedule isn't marked valid")
+	}
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	dbbackupv1alpha1 "github.com/example/db-backup-operator/api/v1alpha1"
+)
+
+// summaryTimeFormat keeps summary timestamps short. Times are absolute so
+// the summary stays true between reconciles
+const summaryTimeFormat = "2006-01-02 15:04Z"
+
+// describeStatus returns a compact, human-scannable summary of a
+// DatabaseBackup's status, e.g. "OK, next 2024-05-01 02:00Z, last took 4m,
+// 0 failures"
+func describeStatus(dbBackup *dbbackupv1alpha1.DatabaseBackup) string {
+	status := &dbBackup.Status
+
+	var state string
+	switch {
+	case meta.IsStatusConditionTrue(status.Conditions, dbbackupv1alpha1.ConditionGloballyPaused):
+		state = "Paused globally"
+	case meta.IsStatusConditionTrue(status.Conditions, dbbackupv1alpha1.ConditionAutoSuspended):
+		state = "Suspended"
+	case status.LastBackupStatus == "Running":
+		state = "Running"
+	case status.LastBackupStatus == "Error":
+		state = "Error"
+	case status.LastBackupStatus == "Failed" || status.LastBackupStatus == "PartiallyFailed":
+		state = "Failing"
+	case status.LastBackupStatus == "SizeLimitExceeded":
+		state = "Over size limit"
+	case status.LastBackupStatus == "QuotaExceeded":
+		state = "Over quota"
+	case strings.HasPrefix(status.LastBackupStatus, "Waiting"):
+		state = status.LastBackupStatus
+	case status.LastSuccessfulBackup == nil:
+		state = "Pending"
+	default:
+		state = "OK"
+	}
+
+	parts := []string{state}
+	if status.NextScheduledBackup != nil {
+		parts = append(parts, "next "+status.NextScheduledBackup.UTC().Format(summaryTimeFormat))
+	}
+	if start, end := status.LastBackupStartTime, status.LastSuccessfulBackup; start != nil && end != nil && !end.Before(start) {
+		parts = append(parts, "last took "+end.Sub(start.Time).Round(time.Second).String())
+	}
+	parts = append(parts, fmt.Sprintf("%d failures", status.ConsecutiveFailures))
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// tracer creates the controller's spans from the global tracer provider,
+// which is a no-op unless tracing was enabled at startup
+var tracer = otel.Tracer("github.com/example/db-backup-operator/controllers")
+
+// Helper function to end a span, recording err as its outcome
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(attribute.String("outcome", "error"))
+	} else {
+		span.SetAttributes(attribute.String("outcome", "success"))
+	}
+	span.End()
+}
+
+// tracedStatusWriter wraps status writes in spans so slow reconciles can be
+// matched to API latency
+type tracedStatusWriter struct {
+	client.StatusWriter
+}
+
+func (w tracedStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	ctx, span := tracer.Start(ctx, "UpdateStatus", trace.WithAttributes(
+		attribute.String("k8s.namespace.name", obj.GetNamespace()),
+		attribute.String("k8s.object.name", obj.GetName()),
+	))
+	err := w.StatusWriter.Update(ctx, obj, opts...)
+	endSpan(span, err)
+	return err
+}
+
+func (w tracedStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	ctx, span := tracer.Start(ctx, "PatchStatus", trace.WithAttributes(
+		attribute.String("k8s.namespace.name", obj.GetNamespace()),
+		attribute.String("k8s.object.name", obj.GetName()),
+	))
+	err := w.StatusWriter.Patch(ctx, obj, patch, opts...)
+	endSpan(span, err)
+	return err
+}
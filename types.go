@@ -1,34 +1,628 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // DatabaseBackupSpec defines the desired state of DatabaseBackup
+// +kubebuilder:validation:XValidation:rule="self.databaseType != 'generic' || (has(self.command) && size(self.command) > 0)",message="command is required when databaseType is generic"
+// +kubebuilder:validation:XValidation:rule="!has(self.backupType) || self.backupType != 'incremental' || self.databaseType == 'postgres'",message="incremental backups are only supported for postgres"
+// +kubebuilder:validation:XValidation:rule="!has(self.includeTables) || !has(self.excludeTables)",message="includeTables and excludeTables are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="has(self.databaseSelector) || has(self.databaseRef)",message="databaseSelector or databaseRef is required"
 type DatabaseBackupSpec struct {
-	// DatabaseType is the type of database to backup (e.g., postgres, mysql)
-	// +kubebuilder:validation:Enum=postgres;mysql;mongodb
+	// DatabaseType is the type of database to backup (e.g., postgres, mysql).
+	// Use generic together with Command to run an arbitrary backup command
+	// +kubebuilder:validation:Enum=postgres;mysql;mongodb;sqlite;generic
 	DatabaseType string `json:"databaseType"`
 
+	// Command overrides the backup image's entrypoint, e.g. for custom
+	// images. Required when DatabaseType is generic, whose image runs it.
+	// Defaults to the image's entrypoint
+	Command []string `json:"command,omitempty"`
+
+	// Args overrides the backup image's arguments. Defaults to the image's
+	Args []string `json:"args,omitempty"`
+
+	// BackupType is full or incremental. Incremental backups (postgres only)
+	// build on the base backup recorded in status, taking a full backup first
+	// when there is none
+	// +kubebuilder:validation:Enum=full;incremental
+	// +kubebuilder:default=full
+	BackupType string `json:"backupType,omitempty"`
+
+	// ReportProgress gives the backup image a progress file (PROGRESS_FILE)
+	// and surfaces what it writes there in status while the backup runs.
+	// Requires pods/exec access; images that don't write progress are ignored
+	ReportProgress bool `json:"reportProgress,omitempty"`
+
+	// MaxDatabaseSizeBytes refuses backups of a database whose volume (the
+	// PVC backing the target pod) is larger than this, as a cost guardrail.
+	// Refused backups are marked SizeLimitExceeded and their slot skipped
+	// +kubebuilder:validation:Minimum=1
+	MaxDatabaseSizeBytes *int64 `json:"maxDatabaseSizeBytes,omitempty"`
+
+	// StreamToStorage has the image pipe the dump through compression
+	// straight to object storage (STREAM=true) without a local staging
+	// file. Only s3 and gcs destinations can be streamed to. A stream can't
+	// resume mid-upload, so a failed attempt is retried from the start
+	// under JobBackoffLimit
+	StreamToStorage *bool `json:"streamToStorage,omitempty"`
+
+	// PipelineMode runs an upload sidecar next to the backup container,
+	// sharing an emptyDir, so the dump is uploaded while it is written.
+	// The sidecar reports the backup's outcome, and either container
+	// failing fails the backup
+	PipelineMode *bool `json:"pipelineMode,omitempty"`
+
+	// BackupTags are stored with each artifact as object tags/metadata and
+	// recorded in the manifest, for cataloging. Keys may be up to 128
+	// characters and values up to 256
+	// +kubebuilder:validation:MaxProperties=10
+	BackupTags map[string]string `json:"backupTags,omitempty"`
+
+	// BandwidthLimit throttles the backup upload, as a size per second
+	// (e.g. 50MB/s, 512KiB/s)
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?([KMGT]i?)?B/s$`
+	BandwidthLimit string `json:"bandwidthLimit,omitempty"`
+
+	// IncludeTables limits the backup to these tables
+	IncludeTables []string `json:"includeTables,omitempty"`
+
+	// ExcludeTables leaves these tables out of the backup. Mutually
+	// exclusive with IncludeTables
+	ExcludeTables []string `json:"excludeTables,omitempty"`
+
 	// Schedule in Cron format, see https://en.wikipedia.org/wiki/Cron
 	// +kubebuilder:validation:Required
 	Schedule string `json:"schedule"`
 
-	// BackupRetention is how long to keep backups (in hours)
+	// Priority orders backups waiting for a slot under the controller's
+	// concurrent backup limit: a free slot goes to a higher priority first
+	// +kubebuilder:default=0
+	Priority int32 `json:"priority,omitempty"`
+
+	// JitterSeconds delays each scheduled run by a stable per-object offset
+	// of up to this many seconds, spreading out backups that share a schedule.
+	// The offset never reaches the following run
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=86400
+	JitterSeconds int32 `json:"jitterSeconds,omitempty"`
+
+	// StartingDeadlineSeconds skips a scheduled run that was missed by more
+	// than this many seconds (e.g. while the controller was down) instead of
+	// running it late. Missed runs are always caught up when unset
+	// +kubebuilder:validation:Minimum=0
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	// BackupRetention is how long to keep backups (in hours). Ignored when
+	// LifecycleRules are set
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:default=168
 	BackupRetention int64 `json:"backupRetention,omitempty"`
 
+	// LifecycleRules tier backups by age, e.g. moving them to a cold storage
+	// class after 7 days and deleting them after 30. They are applied by the
+	// backup image's cleanup step in order of increasing age, and supersede
+	// BackupRetention. Without a Delete rule backups are kept forever
+	// +kubebuilder:validation:MaxItems=10
+	LifecycleRules []LifecycleRule `json:"lifecycleRules,omitempty"`
+
 	// StorageDestination defines where to store the backup
 	StorageDestination StorageDestinationSpec `json:"storageDestination"`
 
-	// DatabaseSelector selects the target database pods using labels
+	// StorageDestinations are additional destinations each backup is written
+	// to alongside StorageDestination
+	StorageDestinations []StorageDestinationSpec `json:"storageDestinations,omitempty"`
+
+	// ReuploadFailedDestinations keeps each artifact on a staging volume so
+	// that destinations which failed are retried by re-uploading it, rather
+	// than by dumping the database again. Requires StorageDestinations
+	ReuploadFailedDestinations *ReuploadSpec `json:"reuploadFailedDestinations,omitempty"`
+
+	// DatabaseSelector selects the target database pods using labels.
+	// Optional when DatabaseRef is set, unless a feature needs the pods
+	// themselves (exec and snapshot modes, WaitForReady, PreferRole)
+	// +optional
+	DatabaseSelector metav1.LabelSelector `json:"databaseSelector,omitempty"`
+
+	// Targets are further databases backed up together with this one as a
+	// consistency group. Their Jobs are launched with the backup Job, and a
+	// run only succeeds once every target has. Each target's artifacts are
+	// written under its name in the storage path
+	// +kubebuilder:validation:MaxItems=10
+	// +listType=map
+	// +listMapKey=name
+	Targets []TargetSpec `json:"targets,omitempty"`
+
+	// DatabaseRef takes the connection details from another operator's
+	// database resource in this namespace, passed to the backup image as
+	// DB_HOST, DB_PORT and a credentials secret mounted at DB_CREDENTIALS_DIR
+	DatabaseRef *DatabaseRefSpec `json:"databaseRef,omitempty"`
+
+	// TLSConfig has the backup image connect to the database with mutual TLS
+	TLSConfig *DatabaseTLSSpec `json:"tlsConfig,omitempty"`
+
+	// StorageCABundleSecret names a secret in this namespace whose ca.crt
+	// the backup image trusts for the storage endpoint, for object stores
+	// using a private CA
+	StorageCABundleSecret string `json:"storageCABundleSecret,omitempty"`
+
+	// PreferRole targets backups at selected pods with a role label, e.g. a
+	// replica to keep load off the primary. The chosen pod is passed to the
+	// backup image as TARGET_POD and DB_HOST. DB_HOST is the pod's DNS name
+	// when it has a hostname and subdomain, its IP otherwise
+	PreferRole *PreferRoleSpec `json:"preferRole,omitempty"`
+
+	// WaitForReady defers backups until a selected database pod is Ready
+	// (and reachable on DatabasePort, if set)
+	WaitForReady *bool `json:"waitForReady,omitempty"`
+
+	// WaitForStorage runs an init container that waits for the storage
+	// destination to be reachable before the backup starts, so storage
+	// outages fail as StorageUnavailable instead of as backup failures
+	WaitForStorage *bool `json:"waitForStorage,omitempty"`
+
+	// PreBackupIntegrityCheck runs an init container with the engine's
+	// consistency check (pg_amcheck for postgres, CHECK TABLE through
+	// mysqlcheck for mysql) and fails the backup as IntegrityCheckFailed
+	// instead of dumping a corrupt database
+	PreBackupIntegrityCheck *bool `json:"preBackupIntegrityCheck,omitempty"`
+
+	// StoreLogs makes the backup image upload its complete log to the
+	// destination next to the artifact, as <artifact>.log, whether or not
+	// the backup succeeds
+	StoreLogs *bool `json:"storeLogs,omitempty"`
+
+	// StorageWaitTimeout is how long WaitForStorage waits before giving up
+	// +kubebuilder:default="5m"
+	StorageWaitTimeout *metav1.Duration `json:"storageWaitTimeout,omitempty"`
+
+	// DatabasePort is the port probed on the target pod when WaitForReady is set
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	DatabasePort int32 `json:"databasePort,omitempty"`
+
+	// NamingTemplate is a Go template for the artifact name passed to the
+	// backup image, independent of the Job name. Available fields are
+	// {{.Name}}, {{.Namespace}}, {{.DatabaseType}}, {{.Date}} (YYYYMMDD)
+	// and {{.Timestamp}} (YYYYMMDDhhmmss)
+	NamingTemplate string `json:"namingTemplate,omitempty"`
+
+	// FileExtension is the suffix of the artifact name, e.g. .dump or
+	// .archive. Defaults to the usual suffix of the database type's gzipped
+	// dump, with .enc appended when Encryption is set
+	// +kubebuilder:validation:MaxLength=32
+	// +kubebuilder:validation:Pattern=`^(\.[A-Za-z0-9]+)+$`
+	FileExtension string `json:"fileExtension,omitempty"`
+
+	// PriorityClassName is the priority class applied to backup pods
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// TerminationGracePeriodSeconds is how long a backup pod gets to flush
+	// and close its storage connection when its Job is cancelled
+	// +kubebuilder:validation:Minimum=0
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// PreemptionPolicy controls whether backup pods may preempt lower-priority pods
+	// +kubebuilder:validation:Enum=Never;PreemptLowerPriority
+	PreemptionPolicy *corev1.PreemptionPolicy `json:"preemptionPolicy,omitempty"`
+
+	// ImagePullSecrets are secrets in this namespace used to pull the backup
+	// image, in addition to any pull secret the controller is configured to copy
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// SchedulerName selects the scheduler for backup pods. Defaults to the
+	// cluster's default scheduler
+	SchedulerName string `json:"schedulerName,omitempty"`
+
+	// JobLabels are added to backup Jobs, e.g. kueue.x-k8s.io/queue-name to
+	// submit them to a Kueue queue. Labels set by the operator take precedence
+	JobLabels map[string]string `json:"jobLabels,omitempty"`
+
+	// DNSPolicy sets the DNS policy of backup pods
+	// +kubebuilder:validation:Enum=ClusterFirstWithHostNet;ClusterFirst;Default;None
+	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
+
+	// DNSConfig adds DNS parameters to backup pods, e.g. extra nameservers
+	// or search domains for databases outside cluster DNS
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// HostAliases are added to the backup pods' /etc/hosts
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
+	// PodSecurityContext is applied to backup pods
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// ContainerSecurityContext is applied to the backup container
+	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+
+	// JobBackoffLimit is the number of retries before a backup job is marked failed
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=2
+	JobBackoffLimit *int32 `json:"jobBackoffLimit,omitempty"`
+
+	// BackupWindow restricts when backup jobs may be started. Runs that come
+	// due outside the window are deferred until it opens
+	BackupWindow *BackupWindowSpec `json:"backupWindow,omitempty"`
+
+	// ExtraVolumes are added to the backup pod alongside the volumes the
+	// operator manages
+	ExtraVolumes []corev1.Volume `json:"extraVolumes,omitempty"`
+
+	// ExtraVolumeMounts are added to the backup container
+	ExtraVolumeMounts []corev1.VolumeMount `json:"extraVolumeMounts,omitempty"`
+
+	// Mode selects how backups are taken: a logical dump Job, a
+	// VolumeSnapshot of the PVC backing the target database pod, or a
+	// command exec'd in the target database pod
+	// +kubebuilder:validation:Enum=logical;snapshot;exec
+	// +kubebuilder:default=logical
+	Mode string `json:"mode,omitempty"`
+
+	// SchedulingMode selects who runs the schedule: the controller itself,
+	// or a native CronJob owned by the DatabaseBackup that keeps running
+	// while the controller is down. NativeCronJob requires logical mode and
+	// full backups
+	// +kubebuilder:validation:Enum=Controller;NativeCronJob
+	// +kubebuilder:default=Controller
+	SchedulingMode string `json:"schedulingMode,omitempty"`
+
+	// VolumeSnapshotClassName is the snapshot class used in snapshot mode
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+
+	// Exec configures the command run in the target pod in exec mode
+	Exec *ExecSpec `json:"exec,omitempty"`
+
+	// EnvFrom sources extra environment for the backup container from
+	// ConfigMaps or Secrets. Variables set by the operator take precedence
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// DependsOn lists other DatabaseBackups in the namespace whose latest
+	// backup must have succeeded since this one last succeeded before it starts
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// AutoSuspendAfterFailures suspends scheduling after this many consecutive
+	// failed backups. Editing the spec or setting the db.example.io/resume
+	// annotation resumes it. Zero disables auto-suspend
+	// +kubebuilder:validation:Minimum=0
+	AutoSuspendAfterFailures int32 `json:"autoSuspendAfterFailures,omitempty"`
+
+	// Incident opens an incident with an on-call provider after repeated
+	// failures and resolves it once a backup succeeds again
+	Incident *IncidentSpec `json:"incident,omitempty"`
+
+	// OrphanJobsOnDelete lets running backup Jobs finish when the
+	// DatabaseBackup is deleted instead of being garbage collected with it
+	OrphanJobsOnDelete *bool `json:"orphanJobsOnDelete,omitempty"`
+
+	// JobTTLSecondsAfterFinished deletes finished backup Jobs after this
+	// many seconds, including Jobs orphaned by OrphanJobsOnDelete
+	// +kubebuilder:validation:Minimum=0
+	JobTTLSecondsAfterFinished *int32 `json:"jobTTLSecondsAfterFinished,omitempty"`
+
+	// CancelOnSpecChange deletes a running backup Job when the spec changes
+	// and starts a new one with the updated spec. Otherwise the running
+	// backup finishes and the change applies from the next run
+	CancelOnSpecChange bool `json:"cancelOnSpecChange,omitempty"`
+
+	// RestoreTest periodically restores the latest backup into a throwaway
+	// database to prove it is usable
+	RestoreTest *RestoreTestSpec `json:"restoreTest,omitempty"`
+
+	// IntegrityCheck periodically downloads and verifies recent artifacts,
+	// catching storage corruption independently of new backups succeeding
+	IntegrityCheck *IntegrityCheckSpec `json:"integrityCheck,omitempty"`
+
+	// SkipIfUnchanged has the backup image skip writing an artifact when
+	// the database content checksum matches the last backup's
+	SkipIfUnchanged bool `json:"skipIfUnchanged,omitempty"`
+
+	// Encryption has backups encrypted with a key from a secret
+	Encryption *EncryptionSpec `json:"encryption,omitempty"`
+
+	// RecordChecksum has the backup image report the SHA256 of each artifact,
+	// recorded in status for later integrity audits. Artifacts made of
+	// several files report the SHA256 of their sha256sum listing, sorted by path
+	RecordChecksum bool `json:"recordChecksum,omitempty"`
+
+	// SuccessLogPattern is a regular expression that the last 500 lines of
+	// each succeeded backup pod's logs must match for the backup to count as
+	// succeeded, for tools that exit 0 on partial failures. Logical mode only
+	SuccessLogPattern string `json:"successLogPattern,omitempty"`
+
+	// AuditLog has a BackupAuditLog entry written for each finished run,
+	// recording when, why and how it ran and its outcome. Entries are owned
+	// by the DatabaseBackup
+	AuditLog bool `json:"auditLog,omitempty"`
+
+	// PublishMetadata keeps a ConfigMap named after the DatabaseBackup with
+	// the latest successful backup's location, timestamp, sizeBytes and
+	// sha256, for tooling without access to DatabaseBackups. The ConfigMap is
+	// owned by the DatabaseBackup. Failures to write it are reported in the
+	// MetadataPublished condition and retried
+	PublishMetadata bool `json:"publishMetadata,omitempty"`
+
+	// Manifest has each backup recorded in a JSON index in the destination,
+	// listing the available backups for restores
+	Manifest *ManifestSpec `json:"manifest,omitempty"`
+
+	// BackupSLO flags backups that succeed but take longer than expected
+	BackupSLO *BackupSLOSpec `json:"backupSLO,omitempty"`
+
+	// Parallelism is the number of backup pods run at once for sharded dumps
+	// +kubebuilder:validation:Minimum=1
+	Parallelism *int32 `json:"parallelism,omitempty"`
+
+	// Completions is the number of backup pods (shards) that must succeed.
+	// Must be at least Parallelism
+	// +kubebuilder:validation:Minimum=1
+	Completions *int32 `json:"completions,omitempty"`
+
+	// ShardedBackup runs the Job in Indexed completion mode, giving each of
+	// the Completions pods a stable shard index (JOB_COMPLETION_INDEX).
+	// Requires Completions greater than 1
+	ShardedBackup bool `json:"shardedBackup,omitempty"`
+
+	// Workers is the number of parallel workers (e.g. table dumps) the
+	// backup image runs within a single pod, passed as WORKERS
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=32
+	Workers int32 `json:"workers,omitempty"`
+}
+
+// TargetSpec is a further database of a consistency group
+type TargetSpec struct {
+	// Name identifies the target in status, Job names and storage paths
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=20
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// DatabaseType is the type of the target database
+	// +kubebuilder:validation:Enum=postgres;mysql;mongodb;sqlite;generic
+	DatabaseType string `json:"databaseType"`
+
+	// DatabaseSelector selects the target's database pods, e.g. for WaitForReady
+	DatabaseSelector metav1.LabelSelector `json:"databaseSelector,omitempty"`
+
+	// EnvFrom provides the target's connection details and credentials to
+	// its backup container, in place of the DatabaseBackup's EnvFrom
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+}
+
+// RestoreTestSpec defines a scheduled restore verification
+type RestoreTestSpec struct {
+	// Schedule in Cron format for restore tests
+	// +kubebuilder:validation:Required
+	Schedule string `json:"schedule"`
+
+	// SanityQuery is run against the restored database; a failing query fails the test
+	SanityQuery string `json:"sanityQuery,omitempty"`
+
+	// Image overrides the restore-test image for the database type
+	Image string `json:"image,omitempty"`
+}
+
+// IntegrityCheckSpec defines a scheduled verification of stored artifacts
+type IntegrityCheckSpec struct {
+	// Schedule in Cron format for integrity checks
+	// +kubebuilder:validation:Required
+	Schedule string `json:"schedule"`
+
+	// MaxArtifacts is how many of the most recent artifacts are verified
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=20
+	// +kubebuilder:default=3
+	MaxArtifacts int32 `json:"maxArtifacts,omitempty"`
+
+	// Image overrides the integrity-check image for the database type
+	Image string `json:"image,omitempty"`
+}
+
+// ReuploadSpec configures re-uploading an artifact to failed destinations
+type ReuploadSpec struct {
+	// StagingPVCName is the PVC backups are staged on before upload. The
+	// backup image keeps an artifact there only while some destination is
+	// missing it
+	// +kubebuilder:validation:Required
+	StagingPVCName string `json:"stagingPVCName"`
+
+	// MaxAttempts is how many re-uploads are tried before giving up
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +kubebuilder:default=3
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+}
+
+// ExecSpec configures a backup run inside the target database pod
+type ExecSpec struct {
+	// Container to exec into. Defaults to the pod's first container
+	Container string `json:"container,omitempty"`
+
+	// Command runs the backup and streams it to storage. It is run with
+	// DB_TYPE, STORAGE_TYPE, BUCKET and STORAGE_PATH set, and must bring
+	// its own storage credentials since none can be mounted into the pod.
+	// Its stdout is discarded, so the command must upload the artifact
+	// itself rather than write it to stdout
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+
+	// Timeout is how long the command may run before it is abandoned
+	// +kubebuilder:default="1h"
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// DatabaseTLSSpec configures client certificate auth to the database
+type DatabaseTLSSpec struct {
+	// SecretName is the secret in this namespace holding ca.crt, tls.crt and
+	// tls.key. It is mounted with mode 0440, so a non-root backup image needs
+	// a podSecurityContext fsGroup to read the key
+	// +kubebuilder:validation:Required
+	SecretName string `json:"secretName"`
+
+	// SSLMode is passed to the image as DB_SSL_MODE
+	// +kubebuilder:validation:Enum=require;verify-ca;verify-full
+	// +kubebuilder:default=verify-full
+	SSLMode string `json:"sslMode,omitempty"`
+}
+
+// DatabaseRefSpec references a database operator's resource. CloudNativePG
+// (postgresql.cnpg.io Cluster) and Zalando (acid.zalan.do postgresql)
+// resolve as-is; other kinds need at least HostField, and the controller
+// needs RBAC to get them
+type DatabaseRefSpec struct {
+	// APIGroup of the referenced resource, e.g. postgresql.cnpg.io
+	// +kubebuilder:validation:Required
+	APIGroup string `json:"apiGroup"`
+
+	// Kind of the referenced resource, e.g. Cluster
+	// +kubebuilder:validation:Required
+	Kind string `json:"kind"`
+
+	// Name of the referenced resource
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// HostField is the dotted path of the host in the resource, e.g. status.host
+	HostField string `json:"hostField,omitempty"`
+
+	// PortField is the dotted path of the port in the resource
+	PortField string `json:"portField,omitempty"`
+
+	// SecretField is the dotted path of the credentials secret's name in the resource
+	SecretField string `json:"secretField,omitempty"`
+}
+
+// ResolvedDatabase holds the connection details resolved from a DatabaseRef
+type ResolvedDatabase struct {
+	// Host of the database
+	Host string `json:"host"`
+
+	// Port of the database, if known
+	Port int32 `json:"port,omitempty"`
+
+	// CredentialsSecret is the secret in this namespace holding the database credentials
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// PreferRoleSpec picks the backup target among the selected pods by label
+type PreferRoleSpec struct {
+	// LabelKey is the pod label holding the role
+	// +kubebuilder:default=role
+	LabelKey string `json:"labelKey,omitempty"`
+
+	// Value of LabelKey to prefer, e.g. replica
+	// +kubebuilder:validation:Required
+	Value string `json:"value"`
+
+	// Required fails the backup when no ready pod has the role, instead of
+	// falling back to any other ready selected pod (e.g. the primary)
+	Required bool `json:"required,omitempty"`
+}
+
+// EncryptionSpec configures backup encryption
+type EncryptionSpec struct {
+	// SecretName is the secret in this namespace holding the encryption key.
+	// The key id used for each backup is the secret's db.example.io/key-id
+	// annotation, or a fingerprint of the key without one. Each key version
+	// is kept in a Secret of its own for restoring the backups it encrypted
+	// +kubebuilder:validation:Required
+	SecretName string `json:"secretName"`
+
+	// Key within the secret that holds the encryption key
+	// +kubebuilder:default=key
+	Key string `json:"key,omitempty"`
+}
+
+// LifecycleRule applies an action to backups once they reach an age
+type LifecycleRule struct {
+	// AfterHours is the backup age (in hours) at which the action applies
+	// +kubebuilder:validation:Minimum=1
+	AfterHours int64 `json:"afterHours"`
+
+	// Action is Transition, to move backups to StorageClass, or Delete
+	// +kubebuilder:validation:Enum=Transition;Delete
+	Action string `json:"action"`
+
+	// StorageClass is the destination's storage class backups transition
+	// to, e.g. GLACIER for s3 or COLDLINE for gcs
+	StorageClass string `json:"storageClass,omitempty"`
+}
+
+const (
+	// LifecycleTransition moves backups to another storage class
+	LifecycleTransition = "Transition"
+
+	// LifecycleDelete deletes backups
+	LifecycleDelete = "Delete"
+)
+
+// IncidentSpec configures incidents for repeatedly failing backups
+type IncidentSpec struct {
+	// Provider receives the incidents: PagerDuty's Events API v2 or
+	// Opsgenie's Alert API
+	// +kubebuilder:validation:Enum=pagerduty;opsgenie
+	// +kubebuilder:default=pagerduty
+	Provider string `json:"provider,omitempty"`
+
+	// SecretName is the secret in this namespace holding the integration
+	// key (PagerDuty routing key or Opsgenie API key)
+	SecretName string `json:"secretName"`
+
+	// Key within the secret that holds the integration key
+	// +kubebuilder:default=integrationKey
+	Key string `json:"key,omitempty"`
+
+	// AfterFailures is how many consecutive failed backups open an incident
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=3
+	AfterFailures int32 `json:"afterFailures,omitempty"`
+
+	// Endpoint overrides the provider's API endpoint, e.g. for Opsgenie's EU
+	// region. Besides the providers' public endpoints, only endpoints the
+	// operator was started with in --incident-endpoints are allowed
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// ManifestSpec configures the backup manifest kept in the storage destination
+type ManifestSpec struct {
+	// Path of the manifest relative to the destination path
+	// +kubebuilder:default=manifest.json
+	Path string `json:"path,omitempty"`
+}
+
+// BackupSLOSpec defines service level objectives for backups
+type BackupSLOSpec struct {
+	// MaxDuration is the longest a backup may take from start to completion
 	// +kubebuilder:validation:Required
-	DatabaseSelector metav1.LabelSelector `json:"databaseSelector"`
+	MaxDuration metav1.Duration `json:"maxDuration"`
+}
+
+// BackupWindowSpec defines a daily time window in which backups may start
+type BackupWindowSpec struct {
+	// Start of the window in HH:MM
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// End of the window in HH:MM. An End before Start spans midnight
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+
+	// Timezone the window is evaluated in (IANA name, defaults to UTC)
+	Timezone string `json:"timezone,omitempty"`
 }
 
 // StorageDestinationSpec defines storage options for backups
+// +kubebuilder:validation:XValidation:rule="!(has(self.secretName) && has(self.workloadIdentity))",message="secretName and workloadIdentity are mutually exclusive"
 type StorageDestinationSpec struct {
+	// Name identifies the destination in status. Defaults to "primary" for
+	// StorageDestination and "destination-<n>" for StorageDestinations
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=32
+	Name string `json:"name,omitempty"`
+
 	// Type of storage (s3, gcs, pvc)
 	// +kubebuilder:validation:Enum=s3;gcs;pvc
 	Type string `json:"type"`
@@ -39,38 +633,477 @@ type StorageDestinationSpec struct {
 	// Path within bucket or PVC
 	Path string `json:"path,omitempty"`
 
+	// Endpoint overrides the object storage endpoint (e.g. MinIO or an
+	// on-prem S3-compatible store)
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// UsePathStyle requests path-style S3 addressing, as most S3-compatible
+	// stores require
+	UsePathStyle *bool `json:"usePathStyle,omitempty"`
+
 	// PVCName is the name of PVC to use (for pvc type)
 	PVCName string `json:"pvcName,omitempty"`
 
 	// SecretName containing storage credentials
 	SecretName string `json:"secretName,omitempty"`
+
+	// SecretNamespace is the namespace of SecretName, if it differs from the
+	// DatabaseBackup's. The secret is copied into the backup namespace
+	// since pods cannot mount secrets across namespaces
+	SecretNamespace string `json:"secretNamespace,omitempty"`
+
+	// Immutability requests object-lock retention on uploaded artifacts
+	Immutability *ImmutabilitySpec `json:"immutability,omitempty"`
+
+	// WorkloadIdentity authenticates to storage with a projected service
+	// account token instead of static credentials. Mutually exclusive with SecretName
+	WorkloadIdentity *WorkloadIdentitySpec `json:"workloadIdentity,omitempty"`
+}
+
+// ImmutabilitySpec configures WORM retention (S3 object lock / GCS retention)
+// for backup artifacts. Retention cleanup skips artifacts that are still
+// locked instead of failing, so a lock longer than BackupRetention simply
+// keeps those artifacts until the lock expires.
+type ImmutabilitySpec struct {
+	// RetainUntilDuration is how long artifacts are locked after upload.
+	// It is rounded up to whole days
+	// +kubebuilder:validation:Required
+	RetainUntilDuration metav1.Duration `json:"retainUntilDuration"`
+}
+
+// WorkloadIdentitySpec configures a bound service account token for storage access
+type WorkloadIdentitySpec struct {
+	// Audience the token is issued for (e.g. sts.amazonaws.com)
+	// +kubebuilder:validation:Required
+	Audience string `json:"audience"`
+
+	// ExpirationSeconds is the requested token lifetime
+	// +kubebuilder:validation:Minimum=600
+	// +kubebuilder:default=3600
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
+
+	// ServiceAccountName is the service account backup pods run as
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 }
 
 // DatabaseBackupStatus defines the observed state of DatabaseBackup
 type DatabaseBackupStatus struct {
+	// ObservedGeneration is the spec generation last acted on by the controller
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// LastSuccessfulBackup is the timestamp of the last successful backup
 	LastSuccessfulBackup *metav1.Time `json:"lastSuccessfulBackup,omitempty"`
 
+	// LastBackupStartTime is when the pod of the most recent backup job began
+	// running. It stays unset until the pod actually runs
+	LastBackupStartTime *metav1.Time `json:"lastBackupStartTime,omitempty"`
+
 	// LastBackupStatus indicates if the last backup succeeded or failed
 	LastBackupStatus string `json:"lastBackupStatus,omitempty"`
 
 	// NextScheduledBackup is when the next backup is scheduled
 	NextScheduledBackup *metav1.Time `json:"nextScheduledBackup,omitempty"`
 
+	// UpcomingBackups lists the next scheduled run times, starting with
+	// NextScheduledBackup
+	UpcomingBackups []metav1.Time `json:"upcomingBackups,omitempty"`
+
+	// ScheduleDescription is a human-readable rendering of Schedule
+	ScheduleDescription string `json:"scheduleDescription,omitempty"`
+
+	// Summary is a one-line overview of the status fields, refreshed on
+	// every reconcile
+	Summary string `json:"summary,omitempty"`
+
+	// ConsecutiveFailures is the number of backups that failed in a row. It is
+	// reset by a successful backup; deleted jobs don't count as failures
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+
 	// FailureReason provides more information about failure if the 
 	// last backup failed
 	FailureReason string `json:"failureReason,omitempty"`
 
+	// FailureCode classifies FailureReason for tooling to match on
+	FailureCode FailureCode `json:"failureCode,omitempty"`
+
+	// LastFailureLog is the tail of the failed backup pod's logs, captured
+	// when the last backup Job failed
+	LastFailureLog string `json:"lastFailureLog,omitempty"`
+
 	// ActiveBackupJob is the name of the currently running backup job, if any
 	ActiveBackupJob string `json:"activeBackupJob,omitempty"`
+
+	// ActiveBackupJobUID is the UID of ActiveBackupJob, so a Job recreated
+	// under the same name isn't mistaken for it
+	ActiveBackupJobUID types.UID `json:"activeBackupJobUID,omitempty"`
+
+	// LastRenderedJob is the YAML of the last backup Job the controller
+	// created, for sharing in support requests. Cut short past 8KiB
+	LastRenderedJob string `json:"lastRenderedJob,omitempty"`
+
+	// OpenIncident is the dedup key of the incident opened for the current
+	// run of failures, if any
+	OpenIncident string `json:"openIncident,omitempty"`
+
+	// ActiveSnapshot is the name of the VolumeSnapshot being taken, if any
+	ActiveSnapshot string `json:"activeSnapshot,omitempty"`
+
+	// ActiveExec is the pod an exec-mode backup is running in, if any
+	ActiveExec string `json:"activeExec,omitempty"`
+
+	// ResolvedDatabase is what DatabaseRef last resolved to
+	ResolvedDatabase *ResolvedDatabase `json:"resolvedDatabase,omitempty"`
+
+	// CancellingJob is a cancelled backup job still terminating. Its
+	// replacement waits for it to be gone
+	CancellingJob string `json:"cancellingJob,omitempty"`
+
+	// BaseBackupRef identifies the full backup incremental backups build on
+	BaseBackupRef string `json:"baseBackupRef,omitempty"`
+
+	// LastBackupChecksum is the database content checksum reported by the
+	// last successful backup, used by SkipIfUnchanged
+	LastBackupChecksum string `json:"lastBackupChecksum,omitempty"`
+
+	// BackupProgressPercent is how far the running backup is, when its
+	// image reports progress
+	// +optional
+	BackupProgressPercent *int32 `json:"backupProgressPercent,omitempty"`
+
+	// BackupBytesTransferred is how much the running backup has
+	// transferred, when its image reports progress
+	// +optional
+	BackupBytesTransferred *int64 `json:"backupBytesTransferred,omitempty"`
+
+	// LastBackupKeyID is the id of the encryption key the last successful
+	// backup was encrypted with, needed to pick the key when restoring it
+	LastBackupKeyID string `json:"lastBackupKeyID,omitempty"`
+
+	// LastBackupLocation is where the last successful backup wrote its
+	// artifact, when the backup image reported it
+	LastBackupLocation string `json:"lastBackupLocation,omitempty"`
+
+	// LastArtifactSHA256 is the SHA256 of the artifact written by the last
+	// successful backup, when RecordChecksum is set
+	LastArtifactSHA256 string `json:"lastArtifactSHA256,omitempty"`
+
+	// LastBackupLogLocation is where the last backup's log was uploaded,
+	// when StoreLogs is set and the backup image reported it
+	LastBackupLogLocation string `json:"lastBackupLogLocation,omitempty"`
+
+	// AvailableBackups is the number of backups listed in the manifest, as
+	// reported by the last successful backup
+	AvailableBackups *int32 `json:"availableBackups,omitempty"`
+
+	// StorageUsageTrend summarizes the sizes of the retained backups, as
+	// recorded in their audit log entries. Requires AuditLog
+	StorageUsageTrend *StorageUsageTrend `json:"storageUsageTrend,omitempty"`
+
+	// LastSuccessfulRestoreTest is when a restore test last succeeded
+	LastSuccessfulRestoreTest *metav1.Time `json:"lastSuccessfulRestoreTest,omitempty"`
+
+	// LastRestoreTestStatus indicates if the last restore test succeeded or failed
+	LastRestoreTestStatus string `json:"lastRestoreTestStatus,omitempty"`
+
+	// LastVerifiedArtifact is the artifact the last successful restore test
+	// restored, and so is known to be restorable. Empty when the backup
+	// image doesn't report artifact locations
+	LastVerifiedArtifact string `json:"lastVerifiedArtifact,omitempty"`
+
+	// NextRestoreTest is when the next restore test is scheduled
+	NextRestoreTest *metav1.Time `json:"nextRestoreTest,omitempty"`
+
+	// ActiveRestoreTestJob is the name of the currently running restore-test job, if any
+	ActiveRestoreTestJob string `json:"activeRestoreTestJob,omitempty"`
+
+	// LastIntegrityCheck holds the results of the last finished integrity check
+	LastIntegrityCheck *IntegrityCheckStatus `json:"lastIntegrityCheck,omitempty"`
+
+	// NextIntegrityCheck is when the next integrity check is scheduled
+	NextIntegrityCheck *metav1.Time `json:"nextIntegrityCheck,omitempty"`
+
+	// IntegrityCheckSchedule is the schedule NextIntegrityCheck was computed
+	// from, so editing the schedule moves the next check
+	IntegrityCheckSchedule string `json:"integrityCheckSchedule,omitempty"`
+
+	// ActiveIntegrityCheckJob is the name of the currently running integrity-check job, if any
+	ActiveIntegrityCheckJob string `json:"activeIntegrityCheckJob,omitempty"`
+
+	// Preflight holds the results of the last preflight run
+	Preflight *PreflightStatus `json:"preflight,omitempty"`
+
+	// LastManualTrigger is the value of the backup-now annotation that was
+	// last acted on
+	LastManualTrigger string `json:"lastManualTrigger,omitempty"`
+
+	// ManualBackupPending is true while a triggered backup waits to start
+	ManualBackupPending bool `json:"manualBackupPending,omitempty"`
+
+	// ManualBackupRun is the Job or VolumeSnapshot running the triggered backup
+	ManualBackupRun string `json:"manualBackupRun,omitempty"`
+
+	// LastManualBackupResult is the outcome of the last triggered backup
+	LastManualBackupResult string `json:"lastManualBackupResult,omitempty"`
+
+	// Destinations records the outcome of the last backup per storage
+	// destination when multiple destinations are configured
+	// +listType=map
+	// +listMapKey=name
+	Destinations []DestinationStatus `json:"destinations,omitempty"`
+
+	// Targets records the outcome of each consistency group target in the
+	// current or last run
+	// +listType=map
+	// +listMapKey=name
+	Targets []TargetStatus `json:"targets,omitempty"`
+
+	// PendingReupload is the staged artifact of the last backup still
+	// missing from some destinations
+	PendingReupload *PendingReupload `json:"pendingReupload,omitempty"`
+
+	// ActiveReuploadJob is the name of the currently running re-upload job, if any
+	ActiveReuploadJob string `json:"activeReuploadJob,omitempty"`
+
+	// Conditions represent the latest available observations of the backup's state
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// FailureCode is a machine-readable reason for a failed or errored backup
+// +kubebuilder:validation:Enum=InvalidSpec;InvalidSchedule;InvalidBackupWindow;DependencyCycle;DatabaseRefUnresolved;SizeLimitExceeded;StorageUnavailable;QuotaExceeded;JobCreateFailed;ImagePullFailed;JobFailed;UploadFailed;IntegrityCheckFailed;SnapshotCreateFailed;SnapshotFailed
+type FailureCode string
+
+const (
+	// FailureInvalidSpec means the spec can't produce a valid backup
+	FailureInvalidSpec FailureCode = "InvalidSpec"
+
+	// FailureInvalidSchedule means the cron schedule doesn't parse
+	FailureInvalidSchedule FailureCode = "InvalidSchedule"
+
+	// FailureInvalidBackupWindow means the backup window doesn't parse
+	FailureInvalidBackupWindow FailureCode = "InvalidBackupWindow"
+
+	// FailureDependencyCycle means DependsOn leads back to this backup
+	FailureDependencyCycle FailureCode = "DependencyCycle"
+
+	// FailureStorageUnavailable means the storage credentials couldn't be provided
+	FailureStorageUnavailable FailureCode = "StorageUnavailable"
+
+	// FailureQuotaExceeded means a ResourceQuota kept the backup Job from being created
+	FailureQuotaExceeded FailureCode = "QuotaExceeded"
+
+	// FailureJobCreateFailed means the backup Job couldn't be created
+	FailureJobCreateFailed FailureCode = "JobCreateFailed"
+
+	// FailureImagePullFailed means a backup pod couldn't pull its image
+	FailureImagePullFailed FailureCode = "ImagePullFailed"
+
+	// FailureJobFailed means the backup Job ran and failed
+	FailureJobFailed FailureCode = "JobFailed"
+
+	// FailureUploadFailed means some storage destinations didn't receive the backup
+	FailureUploadFailed FailureCode = "UploadFailed"
+
+	// FailureIntegrityCheckFailed means the database failed its pre-backup consistency check
+	FailureIntegrityCheckFailed FailureCode = "IntegrityCheckFailed"
+
+	// FailureSnapshotCreateFailed means the VolumeSnapshot couldn't be created
+	FailureSnapshotCreateFailed FailureCode = "SnapshotCreateFailed"
+
+	// FailureSnapshotFailed means the VolumeSnapshot failed or vanished before it was ready
+	FailureSnapshotFailed FailureCode = "SnapshotFailed"
+
+	// FailureSizeLimitExceeded means the database is larger than MaxDatabaseSizeBytes
+	FailureSizeLimitExceeded FailureCode = "SizeLimitExceeded"
+
+	// FailureDatabaseRefUnresolved means the DatabaseRef couldn't be resolved
+	// into connection details
+	FailureDatabaseRefUnresolved FailureCode = "DatabaseRefUnresolved"
+)
+
+// Preflight check results
+const (
+	PreflightPass    = "Pass"
+	PreflightFail    = "Fail"
+	PreflightSkipped = "Skipped"
+)
+
+// PreflightStatus holds the results of a preflight run
+type PreflightStatus struct {
+	// Time the checks ran
+	Time metav1.Time `json:"time"`
+
+	// Passed is true when no check failed
+	Passed bool `json:"passed"`
+
+	// Checks are the individual check results
+	// +listType=map
+	// +listMapKey=name
+	Checks []PreflightCheck `json:"checks,omitempty"`
+}
+
+// PreflightCheck is the result of one preflight check
+type PreflightCheck struct {
+	// Name of the check
+	Name string `json:"name"`
+
+	// Result is Pass, Fail or Skipped
+	// +kubebuilder:validation:Enum=Pass;Fail;Skipped
+	Result string `json:"result"`
+
+	// Message explains the result
+	Message string `json:"message,omitempty"`
+}
+
+// IntegrityCheckStatus holds the results of an integrity check
+type IntegrityCheckStatus struct {
+	// Time the check finished
+	Time metav1.Time `json:"time"`
+
+	// Result is Passed when every verified artifact passed, Unknown when the
+	// check found no artifacts to verify, Failed otherwise
+	// +kubebuilder:validation:Enum=Passed;Failed;Unknown
+	Result string `json:"result"`
+
+	// Message explains a failed or Unknown check that has no per-artifact results
+	Message string `json:"message,omitempty"`
+
+	// Artifacts are the per-artifact results, as reported by the check
+	// +listType=map
+	// +listMapKey=name
+	Artifacts []ArtifactIntegrity `json:"artifacts,omitempty"`
+}
+
+// ArtifactIntegrity is the integrity of one stored artifact
+type ArtifactIntegrity struct {
+	// Name of the artifact
+	Name string `json:"name"`
+
+	// Result is Pass when the artifact decompressed and matched its recorded checksum
+	// +kubebuilder:validation:Enum=Pass;Fail
+	Result string `json:"result"`
+
+	// Message explains a failed artifact
+	Message string `json:"message,omitempty"`
+}
+
+// PendingReupload is a staged artifact waiting to be re-uploaded
+type PendingReupload struct {
+	// Artifact is the staged artifact, relative to the staging volume
+	Artifact string `json:"artifact"`
+
+	// Destinations are the names of the destinations missing the artifact
+	Destinations []string `json:"destinations"`
+
+	// Attempts is the number of re-uploads tried so far
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// LastAttemptTime is when the last re-upload finished
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+}
+
+// TargetStatus is the observed state of a consistency group target
+type TargetStatus struct {
+	// Name of the target
+	Name string `json:"name"`
+
+	// Job backing up the target
+	Job string `json:"job,omitempty"`
+
+	// LastBackupStatus is Running, Succeeded or Failed
+	LastBackupStatus string `json:"lastBackupStatus,omitempty"`
+
+	// Message provides more information about a failed target
+	Message string `json:"message,omitempty"`
+}
+
+// StorageUsageTrend is the storage consumed by retained backups and how it grows
+type StorageUsageTrend struct {
+	// RetainedBackups is the number of retained backups with a known size
+	RetainedBackups int32 `json:"retainedBackups"`
+
+	// TotalBytes is the combined size of the retained backups
+	TotalBytes int64 `json:"totalBytes"`
+
+	// LatestBytes is the size of the most recent backup
+	LatestBytes int64 `json:"latestBytes"`
+
+	// AverageDailyGrowthBytes is how much backups grew per day on average
+	// between the oldest and the most recent retained backup. Unset until
+	// they are at least a day apart
+	AverageDailyGrowthBytes *int64 `json:"averageDailyGrowthBytes,omitempty"`
+
+	// Since is when the oldest retained backup completed
+	Since metav1.Time `json:"since"`
+}
+
+// DestinationStatus is the observed state of a single storage destination
+type DestinationStatus struct {
+	// Name of the destination
+	Name string `json:"name"`
+
+	// LastBackupStatus indicates if the last upload to this destination succeeded or failed
+	LastBackupStatus string `json:"lastBackupStatus,omitempty"`
+
+	// LastSuccessfulBackup is when a backup was last written to this destination
+	LastSuccessfulBackup *metav1.Time `json:"lastSuccessfulBackup,omitempty"`
+
+	// Message provides more information about a failed upload
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	// ConditionSLOBreached is true when the last successful backup exceeded BackupSLO.MaxDuration
+	ConditionSLOBreached = "SLOBreached"
+
+	// ConditionAutoSuspended is true when scheduling stopped after repeated failures
+	ConditionAutoSuspended = "AutoSuspended"
+
+	// ConditionAPIUnavailable is true while reconciles are backing off from API server errors
+	ConditionAPIUnavailable = "APIUnavailable"
+
+	// ConditionRetentionRisk is true when the last successful backup is close
+	// to falling out of BackupRetention with no newer one to replace it
+	ConditionRetentionRisk = "RetentionRisk"
+
+	// ConditionScheduleValid is true when Schedule parses. When false its
+	// message hints at the likely mistake
+	ConditionScheduleValid = "ScheduleValid"
+
+	// ConditionGloballyPaused is true while the operator ConfigMap pauses all backups
+	ConditionGloballyPaused = "GloballyPaused"
+
+	// ConditionComplete is true once the backup requested through the
+	// backup-now annotation has finished, and false while it is pending or running
+	ConditionComplete = "Complete"
+
+	// ConditionPotentialConflict is true when another DatabaseBackup backs up
+	// the same database pods on a near-simultaneous schedule
+	ConditionPotentialConflict = "PotentialConflict"
+
+	// ConditionDependencyMissing is true while a DatabaseBackup named in
+	// DependsOn doesn't exist, which holds back every backup
+	ConditionDependencyMissing = "DependencyMissing"
+
+	// ConditionMetadataPublished is false while the PublishMetadata ConfigMap
+	// couldn't be written for the last successful backup. It is retried
+	ConditionMetadataPublished = "MetadataPublished"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=dbb
 // +kubebuilder:printcolumn:name="Database",type="string",JSONPath=".spec.databaseType"
 // +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+// +kubebuilder:printcolumn:name="Schedule Description",type="string",JSONPath=".status.scheduleDescription",priority=1
 // +kubebuilder:printcolumn:name="Last Backup",type="string",JSONPath=".status.lastSuccessfulBackup"
+// +kubebuilder:printcolumn:name="Next Backup",type="date",JSONPath=".status.nextScheduledBackup"
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.lastBackupStatus"
+// +kubebuilder:printcolumn:name="Failures",type="integer",JSONPath=".status.consecutiveFailures"
+// +kubebuilder:printcolumn:name="Summary",type="string",JSONPath=".status.summary",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // DatabaseBackup is the Schema for the databasebackups API
 type DatabaseBackup struct {
 	metav1.TypeMeta   `json:",inline"`